@@ -1,10 +1,14 @@
 package tstype
 
 import (
+	"bytes"
+	"encoding/json"
 	"reflect"
 	"strings"
 
 	"github.com/jackc/pgtype"
+
+	errors "golang.org/x/xerrors"
 )
 
 var quoteArrayReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
@@ -32,3 +36,104 @@ func findDimensionsFromValue(value reflect.Value, dimensions []pgtype.ArrayDimen
 	}
 	return dimensions, elementsLength, true
 }
+
+// marshalArrayJSON encodes the n elements of an array as nested JSON arrays,
+// one level per dimension. marshalElement encodes element i.
+func marshalArrayJSON(dimensions []pgtype.ArrayDimension, n int, marshalElement func(i int) ([]byte, error)) ([]byte, error) {
+	if len(dimensions) == 0 || n == 0 {
+		return []byte("[]"), nil
+	}
+
+	// dimElemCounts is the number of elements in each array of a dimension, as
+	// in EncodeText.
+	dimElemCounts := make([]int, len(dimensions))
+	dimElemCounts[len(dimensions)-1] = int(dimensions[len(dimensions)-1].Length)
+	for i := len(dimensions) - 2; i > -1; i-- {
+		dimElemCounts[i] = int(dimensions[i].Length) * dimElemCounts[i+1]
+	}
+
+	var buf []byte
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		for _, dec := range dimElemCounts {
+			if i%dec == 0 {
+				buf = append(buf, '[')
+			}
+		}
+
+		elemBuf, err := marshalElement(i)
+		if err != nil {
+			return nil, err
+		}
+		buf = append(buf, elemBuf...)
+
+		for _, dec := range dimElemCounts {
+			if (i+1)%dec == 0 {
+				buf = append(buf, ']')
+			}
+		}
+	}
+	return buf, nil
+}
+
+// unmarshalArrayJSON is the inverse of marshalArrayJSON. It returns the
+// elements in row-major order along with the dimensions of the nested arrays,
+// which must all have matching lengths. Unless nested is set, only the outer
+// array is a dimension and its elements may be JSON arrays themselves. ok is
+// false when b is JSON null.
+func unmarshalArrayJSON(b []byte, nested bool) (elements []json.RawMessage, dimensions []pgtype.ArrayDimension, ok bool, err error) {
+	var top *[]json.RawMessage
+	if err := json.Unmarshal(b, &top); err != nil {
+		return nil, nil, false, err
+	}
+	if top == nil {
+		return nil, nil, false, nil
+	}
+
+	leafDepth := -1
+	var walk func(level []json.RawMessage, depth int) error
+	walk = func(level []json.RawMessage, depth int) error {
+		if depth == len(dimensions) {
+			dimensions = append(dimensions, pgtype.ArrayDimension{Length: int32(len(level)), LowerBound: 1})
+		} else if int(dimensions[depth].Length) != len(level) {
+			return errors.New("multidimensional arrays must have array expressions with matching dimensions")
+		}
+
+		for _, raw := range level {
+			if trimmed := bytes.TrimLeft(raw, " \t\r\n"); nested && len(trimmed) > 0 && trimmed[0] == '[' {
+				if leafDepth >= 0 && depth+1 >= leafDepth {
+					return errors.New("multidimensional arrays must have array expressions with matching dimensions")
+				}
+				var sub []json.RawMessage
+				if err := json.Unmarshal(raw, &sub); err != nil {
+					return err
+				}
+				if err := walk(sub, depth+1); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if leafDepth < 0 {
+				leafDepth = depth + 1
+			} else if leafDepth != depth+1 {
+				return errors.New("multidimensional arrays must have array expressions with matching dimensions")
+			}
+			elements = append(elements, raw)
+		}
+		return nil
+	}
+
+	if err := walk(*top, 0); err != nil {
+		return nil, nil, false, err
+	}
+	if len(elements) == 0 {
+		return nil, nil, true, nil
+	}
+	if leafDepth != len(dimensions) {
+		return nil, nil, false, errors.New("multidimensional arrays must have array expressions with matching dimensions")
+	}
+	return elements, dimensions, true, nil
+}
@@ -0,0 +1,145 @@
+package tstype
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+
+	"github.com/jackc/pgtype"
+
+	errors "golang.org/x/xerrors"
+)
+
+// Bit represents the PostgreSQL bit type. It is stored and encoded as Varbit,
+// but a positive Typmod is the exact length of a bit(n) column rather than the
+// maximum one.
+type Bit Varbit
+
+func (dst *Bit) Set(src interface{}) error {
+	return (*Varbit)(dst).set(src, true)
+}
+
+func (dst Bit) Get() interface{} {
+	switch dst.Status {
+	case Present:
+		return dst
+	case Null:
+		return nil
+	default:
+		return dst.Status
+	}
+}
+
+func (src *Bit) AssignTo(dst interface{}) error {
+	if v, ok := dst.(*Bit); ok && src.Status == Present {
+		*v = *src
+		return nil
+	}
+	return (*Varbit)(src).AssignTo(dst)
+}
+
+// DecodeText accepts the same formats as Varbit.DecodeText.
+func (dst *Bit) DecodeText(ci *pgtype.ConnInfo, src []byte) error {
+	return (*Varbit)(dst).decodeText(src, true)
+}
+
+func (dst *Bit) DecodeBinary(ci *pgtype.ConnInfo, src []byte) error {
+	return (*Varbit)(dst).decodeBinary(src, true)
+}
+
+func (src Bit) EncodeText(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	return (Varbit)(src).encodeText(buf, true)
+}
+
+func (src Bit) EncodeBinary(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	return (Varbit)(src).encodeBinary(buf, true)
+}
+
+// Scan implements the database/sql Scanner interface.
+func (dst *Bit) Scan(src interface{}) error {
+	if src == nil {
+		*dst = Bit{Typmod: dst.Typmod, Status: Null}
+		return nil
+	}
+
+	switch src := src.(type) {
+	case string:
+		return dst.DecodeText(nil, []byte(src))
+	case []byte:
+		srcCopy := make([]byte, len(src))
+		copy(srcCopy, src)
+		return dst.DecodeText(nil, srcCopy)
+	}
+
+	return errors.Errorf("cannot scan %T", src)
+}
+
+// Value implements the database/sql/driver Valuer interface.
+func (src Bit) Value() (driver.Value, error) {
+	return EncodeValueText(src)
+}
+
+// MarshalJSON encodes src as a JSON string of 0s and 1s.
+func (src Bit) MarshalJSON() ([]byte, error) {
+	switch src.Status {
+	case Present:
+		buf, err := src.EncodeText(nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(string(buf))
+	case Null:
+		return []byte("null"), nil
+	}
+
+	return nil, errBadStatus
+}
+
+func (dst *Bit) UnmarshalJSON(b []byte) error {
+	var s *string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		return err
+	}
+
+	return dst.Set(s)
+}
+
+// NewBit returns a Present Bit of n zero bits with Typmod n.
+func NewBit(n int) Bit {
+	b := Bit(NewVarbit(n))
+	b.Typmod = int32(n)
+	return b
+}
+
+// Len returns the number of bits in src.
+func (src Bit) Len() int {
+	return int(src.BitLen)
+}
+
+// GetBit reports whether bit i of src is set. See Varbit.GetBit.
+func (src Bit) GetBit(i int) bool {
+	return (Varbit)(src).GetBit(i)
+}
+
+// SetBit sets bit i of dst to v in place. See Varbit.SetBit.
+func (dst *Bit) SetBit(i int, v bool) {
+	(*Varbit)(dst).SetBit(i, v)
+}
+
+// And returns the bitwise AND of src and other. See Varbit.And.
+func (src Bit) And(other Bit) (Bit, error) {
+	v, err := (Varbit)(src).And(Varbit(other))
+	return Bit(v), err
+}
+
+// Or returns the bitwise OR of src and other. See Varbit.Or.
+func (src Bit) Or(other Bit) (Bit, error) {
+	v, err := (Varbit)(src).Or(Varbit(other))
+	return Bit(v), err
+}
+
+// Xor returns the bitwise XOR of src and other. See Varbit.Xor.
+func (src Bit) Xor(other Bit) (Bit, error) {
+	v, err := (Varbit)(src).Xor(Varbit(other))
+	return Bit(v), err
+}
@@ -0,0 +1,301 @@
+package tstype
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"math"
+
+	"github.com/jackc/pgtype"
+
+	errors "golang.org/x/xerrors"
+)
+
+// Box represents the PostgreSQL box type. P[0] is the upper right corner and
+// P[1] the lower left one; Set and the decoders swap coordinates as needed, as
+// the server does. JSON is a GeoJSON Polygon of the four corners.
+type Box struct {
+	P      [2]pgtype.Vec2
+	Status Status
+}
+
+// normalizeBox returns the box with opposite corners a and b.
+func normalizeBox(a, b pgtype.Vec2) Box {
+	return Box{
+		P: [2]pgtype.Vec2{
+			{X: math.Max(a.X, b.X), Y: math.Max(a.Y, b.Y)},
+			{X: math.Min(a.X, b.X), Y: math.Min(a.Y, b.Y)},
+		},
+		Status: Present,
+	}
+}
+
+func (dst *Box) Set(src interface{}) error {
+	if src == nil {
+		*dst = Box{Status: Null}
+		return nil
+	}
+
+	switch value := src.(type) {
+	case Box:
+		*dst = value
+	case *Box:
+		if value == nil {
+			*dst = Box{Status: Null}
+		} else {
+			*dst = *value
+		}
+	case [2]pgtype.Vec2:
+		*dst = normalizeBox(value[0], value[1])
+	case string:
+		return dst.DecodeText(nil, []byte(value))
+	case *string:
+		if value == nil {
+			*dst = Box{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	default:
+		if originalSrc, ok := underlyingStringType(src); ok {
+			return dst.Set(originalSrc)
+		}
+		return errors.Errorf("cannot convert %v to Box", value)
+	}
+
+	return nil
+}
+
+func (dst Box) Get() interface{} {
+	switch dst.Status {
+	case Present:
+		return dst
+	case Null:
+		return nil
+	default:
+		return dst.Status
+	}
+}
+
+func (src *Box) AssignTo(dst interface{}) error {
+	switch src.Status {
+	case Present:
+		switch v := dst.(type) {
+		case *Box:
+			*v = *src
+			return nil
+		case *[2]pgtype.Vec2:
+			*v = src.P
+			return nil
+		case *string:
+			buf, err := src.EncodeText(nil, nil)
+			if err != nil {
+				return err
+			}
+			*v = string(buf)
+			return nil
+		default:
+			if nextDst, retry := GetAssignToDstType(dst); retry {
+				return src.AssignTo(nextDst)
+			}
+			return errors.Errorf("unable to assign to %T", dst)
+		}
+	case Null:
+		return NullAssignTo(dst)
+	}
+
+	return errors.Errorf("cannot decode %#v into %T", src, dst)
+}
+
+// DecodeText accepts ((x1,y1),(x2,y2)), (x1,y1),(x2,y2) and x1,y1,x2,y2 with
+// the corners in either order.
+func (dst *Box) DecodeText(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Box{Status: Null}
+		return nil
+	}
+
+	p := geoParser{typeName: "box", s: string(src)}
+	points, _, err := p.parsePoints(2, false)
+	if err != nil {
+		return err
+	}
+	if err := p.end(); err != nil {
+		return err
+	}
+
+	*dst = normalizeBox(points[0], points[1])
+	return nil
+}
+
+func (dst *Box) DecodeBinary(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Box{Status: Null}
+		return nil
+	}
+
+	fs, err := readFloat8s("box", src, 4)
+	if err != nil {
+		return err
+	}
+
+	*dst = normalizeBox(pgtype.Vec2{X: fs[0], Y: fs[1]}, pgtype.Vec2{X: fs[2], Y: fs[3]})
+	return nil
+}
+
+func (src Box) EncodeText(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	b := normalizeBox(src.P[0], src.P[1])
+	return appendVec2sText(buf, b.P[:]), nil
+}
+
+func (src Box) EncodeBinary(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	b := normalizeBox(src.P[0], src.P[1])
+	buf = appendVec2Binary(buf, b.P[0])
+	return appendVec2Binary(buf, b.P[1]), nil
+}
+
+// Scan implements the database/sql Scanner interface.
+func (dst *Box) Scan(src interface{}) error {
+	if src == nil {
+		*dst = Box{Status: Null}
+		return nil
+	}
+
+	switch src := src.(type) {
+	case string:
+		return dst.DecodeText(nil, []byte(src))
+	case []byte:
+		srcCopy := make([]byte, len(src))
+		copy(srcCopy, src)
+		return dst.DecodeText(nil, srcCopy)
+	}
+
+	return errors.Errorf("cannot scan %T", src)
+}
+
+// Value implements the database/sql/driver Valuer interface.
+func (src Box) Value() (driver.Value, error) {
+	return EncodeValueText(src)
+}
+
+// MarshalJSON encodes src as a GeoJSON Polygon whose ring runs counterclockwise
+// from the lower left corner.
+func (src Box) MarshalJSON() ([]byte, error) {
+	switch src.Status {
+	case Present:
+		b := normalizeBox(src.P[0], src.P[1])
+		high, low := b.P[0], b.P[1]
+		return marshalGeoJSON("Polygon", geoJSONRing([]pgtype.Vec2{
+			low,
+			{X: high.X, Y: low.Y},
+			high,
+			{X: low.X, Y: high.Y},
+		}))
+	case Null:
+		return []byte("null"), nil
+	}
+
+	return nil, errBadStatus
+}
+
+// UnmarshalJSON decodes a GeoJSON Polygon into its bounding box.
+func (dst *Box) UnmarshalJSON(b []byte) error {
+	g, ok, err := unmarshalGeoJSON(b)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		*dst = Box{Status: Null}
+		return nil
+	}
+	if g.Type != "Polygon" {
+		return unsupportedGeoJSON("Box", g)
+	}
+
+	var rings [][]geoJSONPosition
+	if err := json.Unmarshal(g.Coordinates, &rings); err != nil {
+		return err
+	}
+	points, err := vec2sFromGeoJSONRing(rings)
+	if err != nil {
+		return err
+	}
+
+	*dst = boundingBox(points)
+	return nil
+}
+
+// boundingBox returns the smallest box that contains all points, which must
+// not be empty.
+func boundingBox(points []pgtype.Vec2) Box {
+	b := normalizeBox(points[0], points[0])
+	for _, v := range points[1:] {
+		b.P[0].X = math.Max(b.P[0].X, v.X)
+		b.P[0].Y = math.Max(b.P[0].Y, v.Y)
+		b.P[1].X = math.Min(b.P[1].X, v.X)
+		b.P[1].Y = math.Min(b.P[1].Y, v.Y)
+	}
+	return b
+}
+
+// ContainsPoint reports whether p is inside src or on its boundary, as the
+// server's @> operator does.
+func (src Box) ContainsPoint(p Point) bool {
+	if src.Status != Present || p.Status != Present {
+		return false
+	}
+	b := normalizeBox(src.P[0], src.P[1])
+	high, low := b.P[0], b.P[1]
+	return p.P.X <= high.X && p.P.X >= low.X && p.P.Y <= high.Y && p.P.Y >= low.Y
+}
+
+// ContainsBox reports whether other is inside src, as the server's @> operator
+// does, including its tolerance for rounding errors.
+func (src Box) ContainsBox(other Box) bool {
+	if src.Status != Present || other.Status != Present {
+		return false
+	}
+	a := normalizeBox(src.P[0], src.P[1])
+	b := normalizeBox(other.P[0], other.P[1])
+	return fpGe(a.P[0].X, b.P[0].X) && fpLe(a.P[1].X, b.P[1].X) &&
+		fpGe(a.P[0].Y, b.P[0].Y) && fpLe(a.P[1].Y, b.P[1].Y)
+}
+
+// Overlaps reports whether src and other have a point in common, as the
+// server's && operator does.
+func (src Box) Overlaps(other Box) bool {
+	if src.Status != Present || other.Status != Present {
+		return false
+	}
+	a := normalizeBox(src.P[0], src.P[1])
+	b := normalizeBox(other.P[0], other.P[1])
+	return fpLe(a.P[1].X, b.P[0].X) && fpLe(b.P[1].X, a.P[0].X) &&
+		fpLe(a.P[1].Y, b.P[0].Y) && fpLe(b.P[1].Y, a.P[0].Y)
+}
+
+// Distance returns the distance from p to src, which is 0 if src contains p,
+// as the server's <-> operator does. It is NaN if either is not Present.
+func (src Box) Distance(p Point) float64 {
+	if src.Status != Present || p.Status != Present {
+		return math.NaN()
+	}
+	b := normalizeBox(src.P[0], src.P[1])
+	high, low := b.P[0], b.P[1]
+	dx := math.Max(math.Max(low.X-p.P.X, p.P.X-high.X), 0)
+	dy := math.Max(math.Max(low.Y-p.P.Y, p.P.Y-high.Y), 0)
+	return math.Hypot(dx, dy)
+}
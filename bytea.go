@@ -0,0 +1,281 @@
+package tstype
+
+import (
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"github.com/jackc/pgtype"
+
+	errors "golang.org/x/xerrors"
+)
+
+type Bytea struct {
+	Bytes  []byte
+	Status Status
+}
+
+func (dst *Bytea) Set(src interface{}) error {
+	if src == nil {
+		*dst = Bytea{Status: Null}
+		return nil
+	}
+
+	if value, ok := src.(interface{ Get() interface{} }); ok {
+		value2 := value.Get()
+		if value2 != value {
+			return dst.Set(value2)
+		}
+	}
+
+	switch value := src.(type) {
+	case []byte:
+		if value != nil {
+			*dst = Bytea{Bytes: value, Status: Present}
+		} else {
+			*dst = Bytea{Status: Null}
+		}
+	default:
+		if originalSrc, ok := underlyingBytesType(src); ok {
+			return dst.Set(originalSrc)
+		}
+		return errors.Errorf("cannot convert %v to Bytea", value)
+	}
+
+	return nil
+}
+
+func (dst Bytea) Get() interface{} {
+	switch dst.Status {
+	case Present:
+		return dst.Bytes
+	case Null:
+		return nil
+	default:
+		return dst.Status
+	}
+}
+
+func (src *Bytea) AssignTo(dst interface{}) error {
+	switch src.Status {
+	case Present:
+		switch v := dst.(type) {
+		case *[]byte:
+			buf := make([]byte, len(src.Bytes))
+			copy(buf, src.Bytes)
+			*v = buf
+			return nil
+		default:
+			if nextDst, retry := GetAssignToDstType(dst); retry {
+				return src.AssignTo(nextDst)
+			}
+			return errors.Errorf("unable to assign to %T", dst)
+		}
+	case Null:
+		return NullAssignTo(dst)
+	}
+
+	return errors.Errorf("cannot decode %#v into %T", src, dst)
+}
+
+// DecodeText decodes both the hex format ("\x0102") and the legacy escape
+// format ("\001\002") that the server produces depending on bytea_output.
+func (dst *Bytea) DecodeText(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Bytea{Status: Null}
+		return nil
+	}
+
+	var buf []byte
+	var err error
+	if len(src) >= 2 && src[0] == '\\' && src[1] == 'x' {
+		buf, err = decodeByteaHex(src[2:])
+	} else {
+		buf, err = decodeByteaEscape(src)
+	}
+	if err != nil {
+		return err
+	}
+
+	*dst = Bytea{Bytes: buf, Status: Present}
+	return nil
+}
+
+// DecodeBinary copies src, as the driver may reuse its buffer once decoding
+// returns.
+func (dst *Bytea) DecodeBinary(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Bytea{Status: Null}
+		return nil
+	}
+
+	buf := make([]byte, len(src))
+	copy(buf, src)
+
+	*dst = Bytea{Bytes: buf, Status: Present}
+	return nil
+}
+
+func (src Bytea) EncodeText(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	buf = append(buf, `\x`...)
+	return append(buf, hex.EncodeToString(src.Bytes)...), nil
+}
+
+func (src Bytea) EncodeBinary(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	if buf == nil {
+		buf = []byte{}
+	}
+	return append(buf, src.Bytes...), nil
+}
+
+// Scan implements the database/sql Scanner interface.
+func (dst *Bytea) Scan(src interface{}) error {
+	if src == nil {
+		*dst = Bytea{Status: Null}
+		return nil
+	}
+
+	switch src := src.(type) {
+	case string:
+		return dst.DecodeText(nil, []byte(src))
+	case []byte:
+		buf := make([]byte, len(src))
+		copy(buf, src)
+		*dst = Bytea{Bytes: buf, Status: Present}
+		return nil
+	}
+
+	return errors.Errorf("cannot scan %T", src)
+}
+
+// Value implements the database/sql/driver Valuer interface.
+func (src Bytea) Value() (driver.Value, error) {
+	switch src.Status {
+	case Present:
+		return src.Bytes, nil
+	case Null:
+		return nil, nil
+	default:
+		return nil, errBadStatus
+	}
+}
+
+// MarshalJSON encodes src as a base64 string, as encoding/json does for
+// []byte. Use ByteaHex for the hex format of PostgreSQL's to_json.
+func (src Bytea) MarshalJSON() ([]byte, error) {
+	switch src.Status {
+	case Present:
+		return json.Marshal(base64.StdEncoding.EncodeToString(src.Bytes))
+	case Null:
+		return []byte("null"), nil
+	}
+
+	return nil, errBadStatus
+}
+
+// UnmarshalJSON accepts either a base64 string or a "\x" prefixed hex string.
+func (dst *Bytea) UnmarshalJSON(b []byte) error {
+	var s *string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		return err
+	}
+
+	if s == nil {
+		*dst = Bytea{Status: Null}
+		return nil
+	}
+
+	var buf []byte
+	if strings.HasPrefix(*s, `\x`) {
+		buf, err = decodeByteaHex([]byte((*s)[2:]))
+	} else {
+		buf, err = base64.StdEncoding.DecodeString(*s)
+	}
+	if err != nil {
+		return err
+	}
+
+	*dst = Bytea{Bytes: buf, Status: Present}
+	return nil
+}
+
+// ByteaHex is a Bytea that MarshalJSON encodes as a "\x" prefixed hex string,
+// as PostgreSQL's to_json does. Its other methods are those of Bytea.
+type ByteaHex struct {
+	Bytea
+}
+
+func (src ByteaHex) MarshalJSON() ([]byte, error) {
+	switch src.Status {
+	case Present:
+		return json.Marshal(`\x` + hex.EncodeToString(src.Bytes))
+	case Null:
+		return []byte("null"), nil
+	}
+
+	return nil, errBadStatus
+}
+
+func decodeByteaHex(src []byte) ([]byte, error) {
+	buf := make([]byte, hex.DecodedLen(len(src)))
+	_, err := hex.Decode(buf, src)
+	if err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// decodeByteaEscape decodes the escape format, where a backslash is written as
+// "\\" and any other byte may be written as a backslash and three octal digits.
+func decodeByteaEscape(src []byte) ([]byte, error) {
+	buf := make([]byte, 0, len(src))
+	for i := 0; i < len(src); i++ {
+		if src[i] != '\\' {
+			buf = append(buf, src[i])
+			continue
+		}
+
+		if i+1 < len(src) && src[i+1] == '\\' {
+			buf = append(buf, '\\')
+			i++
+			continue
+		}
+
+		if i+3 >= len(src) {
+			return nil, errors.Errorf("invalid bytea escape sequence at offset %d", i)
+		}
+		var b int
+		for _, c := range src[i+1 : i+4] {
+			if c < '0' || c > '7' {
+				return nil, errors.Errorf("invalid bytea escape sequence at offset %d", i)
+			}
+			b = b*8 + int(c-'0')
+		}
+		if b > 0xff {
+			return nil, errors.Errorf("invalid bytea escape sequence at offset %d", i)
+		}
+		buf = append(buf, byte(b))
+		i += 3
+	}
+
+	return buf, nil
+}
@@ -0,0 +1,129 @@
+package tstype_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/tossp/tstype"
+)
+
+func TestByteaDecodeText(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected []byte
+	}{
+		{`\x`, []byte{}},
+		{`\x00ff7f`, []byte{0, 0xff, 0x7f}},
+		{`\xDEADbeef`, []byte{0xde, 0xad, 0xbe, 0xef}},
+		{``, []byte{}},
+		{`abc`, []byte("abc")},
+		{`\000\001\377`, []byte{0, 1, 0xff}},
+		{`a\\b`, []byte(`a\b`)},
+		{`\\\\`, []byte(`\\`)},
+		{`\101\\\102c`, []byte(`A\Bc`)},
+	}
+
+	for i, tt := range tests {
+		var b tstype.Bytea
+		if err := b.DecodeText(nil, []byte(tt.src)); err != nil {
+			t.Errorf("%d: %q: %v", i, tt.src, err)
+			continue
+		}
+		if b.Status != tstype.Present || !bytes.Equal(b.Bytes, tt.expected) {
+			t.Errorf("%d: %q: expected %v, got %v", i, tt.src, tt.expected, b.Bytes)
+		}
+	}
+
+	for i, src := range []string{`\x0`, `\xzz`, `\`, `\12`, `\400`, `\19a`, `a\b`} {
+		var b tstype.Bytea
+		if err := b.DecodeText(nil, []byte(src)); err == nil {
+			t.Errorf("%d: %q: expected error, got %v", i, src, b.Bytes)
+		}
+	}
+
+	var b tstype.Bytea
+	if err := b.DecodeText(nil, nil); err != nil || b.Status != tstype.Null {
+		t.Errorf("DecodeText nil: got %v, %v", b, err)
+	}
+}
+
+func TestByteaEncode(t *testing.T) {
+	b := tstype.Bytea{Bytes: []byte{0, 0xab}, Status: tstype.Present}
+	if buf, err := b.EncodeText(nil, nil); err != nil || string(buf) != `\x00ab` {
+		t.Errorf("EncodeText: got %q, %v", buf, err)
+	}
+
+	empty := tstype.Bytea{Bytes: []byte{}, Status: tstype.Present}
+	if buf, err := empty.EncodeBinary(nil, nil); err != nil || buf == nil || len(buf) != 0 {
+		t.Errorf("EncodeBinary empty: got %#v, %v", buf, err)
+	}
+
+	bad := tstype.Bytea{Bytes: []byte{1}, Status: tstype.Status(2)}
+	if _, err := bad.EncodeText(nil, nil); err == nil {
+		t.Error("EncodeText invalid status: expected error")
+	}
+	if _, err := bad.EncodeBinary(nil, nil); err == nil {
+		t.Error("EncodeBinary invalid status: expected error")
+	}
+}
+
+func TestByteaSetAndAssignTo(t *testing.T) {
+	type blob []byte
+
+	var b tstype.Bytea
+	if err := b.Set(blob{1, 2, 3}); err != nil || !bytes.Equal(b.Bytes, []byte{1, 2, 3}) {
+		t.Errorf("Set named []byte: got %v, %v", b, err)
+	}
+	if err := b.Set([]byte(nil)); err != nil || b.Status != tstype.Null {
+		t.Errorf("Set nil []byte: got %v, %v", b, err)
+	}
+	if err := b.Set("abc"); err == nil {
+		t.Error("Set string: expected error")
+	}
+
+	b = tstype.Bytea{Bytes: []byte{1, 2, 3}, Status: tstype.Present}
+	var buf []byte
+	if err := b.AssignTo(&buf); err != nil || !bytes.Equal(buf, []byte{1, 2, 3}) {
+		t.Errorf("AssignTo []byte: got %v, %v", buf, err)
+	}
+	buf[0] = 9
+	if b.Bytes[0] != 1 {
+		t.Error("AssignTo []byte: destination shares memory with source")
+	}
+
+	var named blob
+	if err := b.AssignTo(&named); err != nil || !bytes.Equal(named, []byte{1, 2, 3}) {
+		t.Errorf("AssignTo named []byte: got %v, %v", named, err)
+	}
+
+	null := tstype.Bytea{Status: tstype.Null}
+	if err := null.AssignTo(&buf); err != nil || buf != nil {
+		t.Errorf("AssignTo []byte from Null: got %v, %v", buf, err)
+	}
+}
+
+func TestByteaJSON(t *testing.T) {
+	var b tstype.Bytea
+	if err := json.Unmarshal([]byte(`"AQID"`), &b); err != nil || !bytes.Equal(b.Bytes, []byte{1, 2, 3}) {
+		t.Errorf("UnmarshalJSON base64: got %v, %v", b, err)
+	}
+	if err := json.Unmarshal([]byte(`"\\x010203"`), &b); err != nil || !bytes.Equal(b.Bytes, []byte{1, 2, 3}) {
+		t.Errorf("UnmarshalJSON hex: got %v, %v", b, err)
+	}
+	if js, err := json.Marshal(b); err != nil || string(js) != `"AQID"` {
+		t.Errorf("MarshalJSON: got %s, %v", js, err)
+	}
+
+	h := tstype.ByteaHex{Bytea: b}
+	if js, err := json.Marshal(h); err != nil || string(js) != `"\\x010203"` {
+		t.Errorf("ByteaHex MarshalJSON: got %s, %v", js, err)
+	}
+	var back tstype.ByteaHex
+	if err := json.Unmarshal([]byte(`"\\x0a0b"`), &back); err != nil || !bytes.Equal(back.Bytes, []byte{10, 11}) {
+		t.Errorf("ByteaHex UnmarshalJSON: got %v, %v", back, err)
+	}
+	if js, err := json.Marshal(tstype.ByteaHex{}); err != nil || string(js) != "null" {
+		t.Errorf("ByteaHex MarshalJSON null: got %s, %v", js, err)
+	}
+}
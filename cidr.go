@@ -0,0 +1,162 @@
+package tstype
+
+import (
+	"database/sql/driver"
+	"net/netip"
+
+	"github.com/jackc/pgtype"
+
+	errors "golang.org/x/xerrors"
+)
+
+// Cidr represents the PostgreSQL cidr type. As in the server, the address must
+// not have bits set to the right of the netmask: 192.168.1.0/24 is valid but
+// 192.168.1.5/24 is rejected.
+type Cidr Inet
+
+func (dst *Cidr) Set(src interface{}) error {
+	if src == nil {
+		*dst = Cidr{Status: Null}
+		return nil
+	}
+
+	if value, ok := src.(interface{ Get() interface{} }); ok {
+		value2 := value.Get()
+		if value2 != value {
+			return dst.Set(value2)
+		}
+	}
+
+	prefix, ok, err := prefixFromValue(src)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		*dst = Cidr{Status: Null}
+		return nil
+	}
+
+	if err := checkCidrPrefix(prefix); err != nil {
+		return err
+	}
+
+	*dst = Cidr{Prefix: prefix, Status: Present}
+	return nil
+}
+
+func (dst Cidr) Get() interface{} {
+	return (Inet)(dst).Get()
+}
+
+func (src *Cidr) AssignTo(dst interface{}) error {
+	switch src.Status {
+	case Present:
+		return assignPrefixTo(src.Prefix, dst, true)
+	case Null:
+		return NullAssignTo(dst)
+	}
+
+	return errors.Errorf("cannot decode %#v into %T", src, dst)
+}
+
+func (dst *Cidr) DecodeText(ci *pgtype.ConnInfo, src []byte) error {
+	var inet Inet
+	if err := inet.DecodeText(ci, src); err != nil {
+		return err
+	}
+
+	return dst.setInet(inet)
+}
+
+func (dst *Cidr) DecodeBinary(ci *pgtype.ConnInfo, src []byte) error {
+	var inet Inet
+	if err := inet.DecodeBinary(ci, src); err != nil {
+		return err
+	}
+
+	return dst.setInet(inet)
+}
+
+// EncodeText always includes the netmask, as the server does for cidr.
+func (src Cidr) EncodeText(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	if err := checkCidrPrefix(src.Prefix); err != nil {
+		return nil, err
+	}
+
+	return append(buf, src.Prefix.String()...), nil
+}
+
+func (src Cidr) EncodeBinary(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	if err := checkCidrPrefix(src.Prefix); err != nil {
+		return nil, err
+	}
+
+	return appendInetBinary(buf, src.Prefix, true)
+}
+
+// Scan implements the database/sql Scanner interface.
+func (dst *Cidr) Scan(src interface{}) error {
+	var inet Inet
+	if err := inet.Scan(src); err != nil {
+		return err
+	}
+
+	return dst.setInet(inet)
+}
+
+// Value implements the database/sql/driver Valuer interface.
+func (src Cidr) Value() (driver.Value, error) {
+	return EncodeValueText(src)
+}
+
+func (src Cidr) MarshalJSON() ([]byte, error) {
+	return (Inet)(src).MarshalJSON()
+}
+
+func (dst *Cidr) UnmarshalJSON(b []byte) error {
+	var inet Inet
+	if err := inet.UnmarshalJSON(b); err != nil {
+		return err
+	}
+
+	return dst.setInet(inet)
+}
+
+// setInet sets dst to inet if it is a valid cidr value, and leaves dst
+// unchanged otherwise.
+func (dst *Cidr) setInet(inet Inet) error {
+	if inet.Status == Present {
+		if err := checkCidrPrefix(inet.Prefix); err != nil {
+			return err
+		}
+	}
+
+	*dst = Cidr(inet)
+	return nil
+}
+
+func checkCidrPrefix(prefix netip.Prefix) error {
+	if !prefix.IsValid() {
+		return errors.Errorf("invalid cidr value %v", prefix)
+	}
+	if prefix.Masked() != prefix {
+		return errors.Errorf("invalid cidr value %v: value has bits set to right of mask", prefix)
+	}
+	return nil
+}
@@ -0,0 +1,258 @@
+package tstype
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"math"
+
+	"github.com/jackc/pgtype"
+
+	errors "golang.org/x/xerrors"
+)
+
+// Circle represents the PostgreSQL circle type with center P and radius R.
+// JSON is a string of the text form, as GeoJSON has no circles.
+type Circle struct {
+	P      pgtype.Vec2
+	R      float64
+	Status Status
+}
+
+func (dst *Circle) Set(src interface{}) error {
+	if src == nil {
+		*dst = Circle{Status: Null}
+		return nil
+	}
+
+	switch value := src.(type) {
+	case Circle:
+		*dst = value
+	case *Circle:
+		if value == nil {
+			*dst = Circle{Status: Null}
+		} else {
+			*dst = *value
+		}
+	case string:
+		return dst.DecodeText(nil, []byte(value))
+	case *string:
+		if value == nil {
+			*dst = Circle{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	default:
+		if originalSrc, ok := underlyingStringType(src); ok {
+			return dst.Set(originalSrc)
+		}
+		return errors.Errorf("cannot convert %v to Circle", value)
+	}
+
+	return nil
+}
+
+func (dst Circle) Get() interface{} {
+	switch dst.Status {
+	case Present:
+		return dst
+	case Null:
+		return nil
+	default:
+		return dst.Status
+	}
+}
+
+func (src *Circle) AssignTo(dst interface{}) error {
+	switch src.Status {
+	case Present:
+		switch v := dst.(type) {
+		case *Circle:
+			*v = *src
+			return nil
+		case *string:
+			buf, err := src.EncodeText(nil, nil)
+			if err != nil {
+				return err
+			}
+			*v = string(buf)
+			return nil
+		default:
+			if nextDst, retry := GetAssignToDstType(dst); retry {
+				return src.AssignTo(nextDst)
+			}
+			return errors.Errorf("unable to assign to %T", dst)
+		}
+	case Null:
+		return NullAssignTo(dst)
+	}
+
+	return errors.Errorf("cannot decode %#v into %T", src, dst)
+}
+
+// DecodeText accepts <(x,y),r>, ((x,y),r), (x,y),r and x,y,r.
+func (dst *Circle) DecodeText(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Circle{Status: Null}
+		return nil
+	}
+
+	p := geoParser{typeName: "circle", s: string(src)}
+	var closing byte
+	switch p.peek() {
+	case '<':
+		p.rp++
+		closing = '>'
+	case '(':
+		if next := skipTsSpace(p.s, p.rp+1); next < len(p.s) && p.s[next] == '(' {
+			p.rp++
+			closing = ')'
+		}
+	}
+
+	center, err := p.parsePoint()
+	if err != nil {
+		return err
+	}
+	if !p.consume(',') {
+		return p.syntaxError()
+	}
+	r, err := p.parseFloat()
+	if err != nil {
+		return err
+	}
+	if closing != 0 && !p.consume(closing) {
+		return p.syntaxError()
+	}
+	if err := p.end(); err != nil {
+		return err
+	}
+	if r < 0 {
+		return p.syntaxError()
+	}
+
+	*dst = Circle{P: center, R: r, Status: Present}
+	return nil
+}
+
+func (dst *Circle) DecodeBinary(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Circle{Status: Null}
+		return nil
+	}
+
+	fs, err := readFloat8s("circle", src, 3)
+	if err != nil {
+		return err
+	}
+	if fs[2] < 0 {
+		return errors.New(`invalid radius in external "circle" value`)
+	}
+
+	*dst = Circle{P: pgtype.Vec2{X: fs[0], Y: fs[1]}, R: fs[2], Status: Present}
+	return nil
+}
+
+func (src Circle) EncodeText(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	if src.R < 0 {
+		return nil, errors.Errorf("circle radius cannot be negative: %v", src.R)
+	}
+
+	buf = append(buf, '<')
+	buf = appendVec2Text(buf, src.P)
+	buf = append(buf, ',')
+	buf = append(buf, formatPgFloat(src.R, 64)...)
+	return append(buf, '>'), nil
+}
+
+func (src Circle) EncodeBinary(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	if src.R < 0 {
+		return nil, errors.Errorf("circle radius cannot be negative: %v", src.R)
+	}
+
+	buf = appendVec2Binary(buf, src.P)
+	return appendFloat8Binary(buf, src.R), nil
+}
+
+// Scan implements the database/sql Scanner interface.
+func (dst *Circle) Scan(src interface{}) error {
+	if src == nil {
+		*dst = Circle{Status: Null}
+		return nil
+	}
+
+	switch src := src.(type) {
+	case string:
+		return dst.DecodeText(nil, []byte(src))
+	case []byte:
+		srcCopy := make([]byte, len(src))
+		copy(srcCopy, src)
+		return dst.DecodeText(nil, srcCopy)
+	}
+
+	return errors.Errorf("cannot scan %T", src)
+}
+
+// Value implements the database/sql/driver Valuer interface.
+func (src Circle) Value() (driver.Value, error) {
+	return EncodeValueText(src)
+}
+
+// MarshalJSON encodes src as a JSON string of its text form.
+func (src Circle) MarshalJSON() ([]byte, error) {
+	switch src.Status {
+	case Present:
+		buf, err := src.EncodeText(nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(string(buf))
+	case Null:
+		return []byte("null"), nil
+	}
+
+	return nil, errBadStatus
+}
+
+func (dst *Circle) UnmarshalJSON(b []byte) error {
+	var s *string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		return err
+	}
+
+	return dst.Set(s)
+}
+
+// ContainsPoint reports whether p is inside src or on its boundary, as the
+// server's @> operator does.
+func (src Circle) ContainsPoint(p Point) bool {
+	if src.Status != Present || p.Status != Present {
+		return false
+	}
+	return vec2Distance(src.P, p.P) <= src.R
+}
+
+// Distance returns the distance from p to src, which is 0 if src contains p,
+// as the server's <-> operator does. It is NaN if either is not Present.
+func (src Circle) Distance(p Point) float64 {
+	if src.Status != Present || p.Status != Present {
+		return math.NaN()
+	}
+	return math.Max(vec2Distance(src.P, p.P)-src.R, 0)
+}
@@ -0,0 +1,123 @@
+package tstype
+
+import (
+	"database/sql/driver"
+	"hash/fnv"
+	"strings"
+	"unicode"
+
+	"github.com/jackc/pgtype"
+)
+
+// Citext represents the PostgreSQL citext type. It is stored and encoded as
+// Text, but Equal, Compare and Hash ignore case as the server does.
+type Citext Text
+
+// Set converts from src to dst. It accepts the same strings and byte slices as
+// Text.Set and keeps their case; only comparisons ignore it.
+func (dst *Citext) Set(src interface{}) error {
+	return (*Text)(dst).Set(src)
+}
+
+func (dst Citext) Get() interface{} {
+	return (Text)(dst).Get()
+}
+
+// AssignTo assigns from src to dst as Text.AssignTo does, with the case of the
+// stored string unchanged.
+func (src *Citext) AssignTo(dst interface{}) error {
+	return (*Text)(src).AssignTo(dst)
+}
+
+func (Citext) PreferredResultFormat() int16 {
+	return pgtype.TextFormatCode
+}
+
+func (dst *Citext) DecodeText(ci *pgtype.ConnInfo, src []byte) error {
+	return (*Text)(dst).DecodeText(ci, src)
+}
+
+func (dst *Citext) DecodeBinary(ci *pgtype.ConnInfo, src []byte) error {
+	return (*Text)(dst).DecodeBinary(ci, src)
+}
+
+func (Citext) PreferredParamFormat() int16 {
+	return pgtype.TextFormatCode
+}
+
+func (src Citext) EncodeText(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	return (Text)(src).EncodeText(ci, buf)
+}
+
+func (src Citext) EncodeBinary(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	return (Text)(src).EncodeBinary(ci, buf)
+}
+
+// Scan implements the database/sql Scanner interface.
+func (dst *Citext) Scan(src interface{}) error {
+	return (*Text)(dst).Scan(src)
+}
+
+// Value implements the database/sql/driver Valuer interface.
+func (src Citext) Value() (driver.Value, error) {
+	return (Text)(src).Value()
+}
+
+func (src Citext) MarshalJSON() ([]byte, error) {
+	return (Text)(src).MarshalJSON()
+}
+
+func (dst *Citext) UnmarshalJSON(b []byte) error {
+	return (*Text)(dst).UnmarshalJSON(b)
+}
+
+// lower returns src lowered rune by rune with the Unicode simple lowercase
+// mapping, as the server's lower() does and citext compares with.
+func (src Citext) lower() string {
+	return strings.Map(unicode.ToLower, src.String)
+}
+
+// Equal reports whether src and other are equal ignoring case, as the
+// server's citext = operator does. Null is not equal to anything.
+func (src Citext) Equal(other Citext) bool {
+	if src.Status != Present || other.Status != Present {
+		return false
+	}
+	return src.lower() == other.lower()
+}
+
+// Compare orders src and other by their lowered strings byte by byte,
+// returning -1, 0 or 1. This is the order of the server's citext operators in
+// the C collation. Null sorts after every string.
+func (src Citext) Compare(other Citext) int {
+	switch {
+	case src.Status != Present && other.Status != Present:
+		return 0
+	case src.Status != Present:
+		return 1
+	case other.Status != Present:
+		return -1
+	}
+	return strings.Compare(src.lower(), other.lower())
+}
+
+// Hash returns a hash of src that is the same for every Citext that src is
+// Equal to, so that it can be used to key maps case-insensitively.
+func (src Citext) Hash() uint64 {
+	h := fnv.New64a()
+	if src.Status == Present {
+		h.Write([]byte{1})
+		h.Write([]byte(src.lower()))
+	}
+	return h.Sum64()
+}
+
+// Key returns the lowered string of src, which can be used as a map key that
+// treats Equal values as the same key. The key of Null is "\x00", which no
+// Present value has since the server does not allow NUL in text.
+func (src Citext) Key() string {
+	if src.Status != Present {
+		return "\x00"
+	}
+	return src.lower()
+}
@@ -0,0 +1,59 @@
+package tstype_test
+
+import (
+	"testing"
+
+	"github.com/tossp/tstype"
+)
+
+func TestCitextEqual(t *testing.T) {
+	c := func(s string) tstype.Citext { return tstype.Citext{String: s, Status: tstype.Present} }
+
+	tests := []struct {
+		a, b  string
+		equal bool
+	}{
+		{a: "Hello", b: "hELLO", equal: true},
+		{a: "ÀÉÎ", b: "àéî", equal: true},
+		{a: "ΣΊΣΥΦΟΣ", b: "σίσυφοσ", equal: true},
+		{a: "straße", b: "STRASSE", equal: false},
+		{a: "a", b: "b", equal: false},
+	}
+
+	for i, tt := range tests {
+		if got := c(tt.a).Equal(c(tt.b)); got != tt.equal {
+			t.Errorf("%d: %q = %q: expected %v", i, tt.a, tt.b, tt.equal)
+		}
+		if tt.equal && c(tt.a).Hash() != c(tt.b).Hash() {
+			t.Errorf("%d: expected equal hashes for %q and %q", i, tt.a, tt.b)
+		}
+		if tt.equal != (c(tt.a).Compare(c(tt.b)) == 0) {
+			t.Errorf("%d: Compare disagrees with Equal for %q and %q", i, tt.a, tt.b)
+		}
+	}
+
+	if c("apple").Compare(c("BANANA")) != -1 || c("b").Compare(tstype.Citext{Status: tstype.Null}) != -1 {
+		t.Error("Compare")
+	}
+	if (tstype.Citext{Status: tstype.Null}).Equal(tstype.Citext{Status: tstype.Null}) {
+		t.Error("expected Null not to equal Null")
+	}
+}
+
+func TestCitextKey(t *testing.T) {
+	seen := map[string]string{}
+	for _, v := range []tstype.Citext{
+		{String: "Hello", Status: tstype.Present},
+		{String: "hELLO", Status: tstype.Present},
+		{String: "", Status: tstype.Present},
+		{Status: tstype.Null},
+	} {
+		seen[v.Key()] = v.String
+	}
+	if len(seen) != 3 {
+		t.Errorf("expected 3 keys, got %q", seen)
+	}
+	if (tstype.Citext{Status: tstype.Null}).Key() == (tstype.Citext{String: "", Status: tstype.Present}).Key() {
+		t.Error("expected Null and the empty string to have different keys")
+	}
+}
@@ -0,0 +1,688 @@
+package tstype
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/json"
+	"reflect"
+	"strconv"
+
+	"github.com/jackc/pgio"
+	"github.com/jackc/pgtype"
+
+	errors "golang.org/x/xerrors"
+)
+
+// CompositeField is a field of a composite type. Value holds the field and is
+// usually a pointer to a tstype value, such as *Text, *Numeric or *UUID. OID is
+// the type of the field in the binary format. When it is 0 the OID is inferred
+// from the type of Value for the built-in types of this package.
+type CompositeField struct {
+	Name  string
+	OID   uint32
+	Value pgtype.ValueTranscoder
+}
+
+// Composite represents a PostgreSQL composite (row) value as an ordered list of
+// fields. Fields describes the composite type and is kept by Set, the decoders
+// and UnmarshalJSON, which decode each field into its Value.
+//
+// When Fields is empty, or a field has no Value, the decoders create it: text
+// fields are decoded as *Text and binary fields as the tstype value for their
+// OID.
+type Composite struct {
+	Fields []CompositeField
+	Status Status
+}
+
+// NewComposite returns a Null Composite with fields.
+func NewComposite(fields ...CompositeField) *Composite {
+	return &Composite{Fields: fields, Status: Null}
+}
+
+// compositeFieldTypes are the tstype values the decoders create for a field of
+// a composite without Fields, and whose OID the binary encoder infers.
+var compositeFieldTypes = map[uint32]func() pgtype.ValueTranscoder{
+	pgtype.BoolOID:        func() pgtype.ValueTranscoder { return &Bool{} },
+	pgtype.ByteaOID:       func() pgtype.ValueTranscoder { return &Bytea{} },
+	pgtype.Int8OID:        func() pgtype.ValueTranscoder { return &Int8{} },
+	pgtype.Int2OID:        func() pgtype.ValueTranscoder { return &Int2{} },
+	pgtype.Int4OID:        func() pgtype.ValueTranscoder { return &Int4{} },
+	pgtype.TextOID:        func() pgtype.ValueTranscoder { return &Text{} },
+	pgtype.JSONOID:        func() pgtype.ValueTranscoder { return &JSON{} },
+	pgtype.CIDROID:        func() pgtype.ValueTranscoder { return &Cidr{} },
+	pgtype.Float4OID:      func() pgtype.ValueTranscoder { return &Float4{} },
+	pgtype.Float8OID:      func() pgtype.ValueTranscoder { return &Float8{} },
+	pgtype.MacaddrOID:     func() pgtype.ValueTranscoder { return &Macaddr{} },
+	pgtype.InetOID:        func() pgtype.ValueTranscoder { return &Inet{} },
+	pgtype.VarcharOID:     func() pgtype.ValueTranscoder { return &Varchar{} },
+	pgtype.DateOID:        func() pgtype.ValueTranscoder { return &Date{} },
+	pgtype.TimeOID:        func() pgtype.ValueTranscoder { return &Time{} },
+	pgtype.TimestampOID:   func() pgtype.ValueTranscoder { return &Timestamp{} },
+	pgtype.TimestamptzOID: func() pgtype.ValueTranscoder { return &Timestamptz{} },
+	pgtype.IntervalOID:    func() pgtype.ValueTranscoder { return &Interval{} },
+	pgtype.NumericOID:     func() pgtype.ValueTranscoder { return &Numeric{} },
+	pgtype.UUIDOID:        func() pgtype.ValueTranscoder { return &UUID{} },
+	pgtype.JSONBOID:       func() pgtype.ValueTranscoder { return &JSONB{} },
+	pgtype.Int4rangeOID:   func() pgtype.ValueTranscoder { return &Int4range{} },
+	pgtype.NumrangeOID:    func() pgtype.ValueTranscoder { return &Numrange{} },
+	pgtype.TsrangeOID:     func() pgtype.ValueTranscoder { return &Tsrange{} },
+	pgtype.TstzrangeOID:   func() pgtype.ValueTranscoder { return &Tstzrange{} },
+	pgtype.DaterangeOID:   func() pgtype.ValueTranscoder { return &Daterange{} },
+	pgtype.Int8rangeOID:   func() pgtype.ValueTranscoder { return &Int8range{} },
+	pgtype.PointOID:       func() pgtype.ValueTranscoder { return &Point{} },
+	pgtype.LsegOID:        func() pgtype.ValueTranscoder { return &Lseg{} },
+	pgtype.PathOID:        func() pgtype.ValueTranscoder { return &Path{} },
+	pgtype.BoxOID:         func() pgtype.ValueTranscoder { return &Box{} },
+	pgtype.PolygonOID:     func() pgtype.ValueTranscoder { return &Polygon{} },
+	pgtype.LineOID:        func() pgtype.ValueTranscoder { return &Line{} },
+	pgtype.CircleOID:      func() pgtype.ValueTranscoder { return &Circle{} },
+	pgtype.BitOID:         func() pgtype.ValueTranscoder { return &Bit{} },
+	pgtype.VarbitOID:      func() pgtype.ValueTranscoder { return &Varbit{} },
+	xmlOID:                func() pgtype.ValueTranscoder { return &XML{} },
+	macaddr8OID:           func() pgtype.ValueTranscoder { return &Macaddr8{} },
+	moneyOID:              func() pgtype.ValueTranscoder { return &Money{} },
+	timetzOID:             func() pgtype.ValueTranscoder { return &Timetz{} },
+}
+
+// OIDs of built-in types that pgtype has no constant for.
+const (
+	xmlOID      = 142
+	macaddr8OID = 774
+	moneyOID    = 790
+	timetzOID   = 1266
+)
+
+var compositeFieldOIDs = func() map[reflect.Type]uint32 {
+	oids := make(map[reflect.Type]uint32, len(compositeFieldTypes))
+	for oid, newValue := range compositeFieldTypes {
+		oids[reflect.TypeOf(newValue())] = oid
+	}
+	return oids
+}()
+
+func (f CompositeField) oid() (uint32, error) {
+	if f.OID != 0 {
+		return f.OID, nil
+	}
+	if oid, ok := compositeFieldOIDs[reflect.TypeOf(f.Value)]; ok {
+		return oid, nil
+	}
+	return 0, errors.Errorf("unknown OID for composite field %q of type %T", f.Name, f.Value)
+}
+
+// value returns the Value of f, or an error when the field has none.
+func (f CompositeField) value() (pgtype.ValueTranscoder, error) {
+	if f.Value == nil {
+		return nil, errors.Errorf("composite field %q has no value type", f.Name)
+	}
+	return f.Value, nil
+}
+
+// jsonName returns the key of the field in the JSON form of a composite. Like
+// the server's row_to_json, an unnamed field is named f1, f2, ... by position.
+func (f CompositeField) jsonName(i int) string {
+	if f.Name != "" {
+		return f.Name
+	}
+	return "f" + strconv.Itoa(i+1)
+}
+
+// Set sets the fields of dst from src, which may be a Composite, a
+// []interface{} with a value for each field, or a struct or pointer to struct
+// mapped to the fields as AssignTo describes. Each value is passed to the Set
+// method of its field.
+func (dst *Composite) Set(src interface{}) error {
+	if src == nil {
+		dst.Status = Null
+		return nil
+	}
+
+	switch value := src.(type) {
+	case Composite:
+		*dst = value
+		return nil
+	case *Composite:
+		if value == nil {
+			dst.Status = Null
+		} else {
+			*dst = *value
+		}
+		return nil
+	case []interface{}:
+		if value == nil {
+			dst.Status = Null
+			return nil
+		}
+		if len(value) != len(dst.Fields) {
+			return errors.Errorf("cannot set composite with %d fields from %d values", len(dst.Fields), len(value))
+		}
+		for i := range value {
+			v, err := dst.Fields[i].value()
+			if err != nil {
+				return err
+			}
+			if err := v.Set(value[i]); err != nil {
+				return errors.Errorf("composite field %q: %w", dst.Fields[i].Name, err)
+			}
+		}
+		dst.Status = Present
+		return nil
+	}
+
+	refVal := reflect.ValueOf(src)
+	if refVal.Kind() == reflect.Ptr {
+		if refVal.IsNil() {
+			dst.Status = Null
+			return nil
+		}
+		refVal = refVal.Elem()
+	}
+	if refVal.Kind() != reflect.Struct {
+		return errors.Errorf("cannot convert %v to Composite", src)
+	}
+
+	indexes, err := dst.structFieldIndexes(refVal.Type())
+	if err != nil {
+		return err
+	}
+	for i, index := range indexes {
+		if index == nil {
+			continue
+		}
+		v, err := dst.Fields[i].value()
+		if err != nil {
+			return err
+		}
+		if err := v.Set(refVal.FieldByIndex(index).Interface()); err != nil {
+			return errors.Errorf("composite field %q: %w", dst.Fields[i].Name, err)
+		}
+	}
+
+	dst.Status = Present
+	return nil
+}
+
+// Get returns the values of the fields of dst as a []interface{}.
+func (dst Composite) Get() interface{} {
+	switch dst.Status {
+	case Present:
+		values := make([]interface{}, len(dst.Fields))
+		for i := range dst.Fields {
+			if dst.Fields[i].Value != nil {
+				values[i] = dst.Fields[i].Value.Get()
+			}
+		}
+		return values
+	case Null:
+		return nil
+	default:
+		return dst.Status
+	}
+}
+
+// AssignTo assigns src to dst, which may be a *Composite, a *[]interface{},
+// a *string or a pointer to a struct. Struct fields are matched to composite
+// fields by their db tag, or by position among the exported fields when no
+// field has a db tag. A field tagged db:"-" is skipped. Each struct field is
+// passed to the AssignTo method of its composite field.
+func (src *Composite) AssignTo(dst interface{}) error {
+	switch src.Status {
+	case Present:
+		switch v := dst.(type) {
+		case *Composite:
+			*v = *src
+			return nil
+		case *[]interface{}:
+			*v = src.Get().([]interface{})
+			return nil
+		case *string:
+			buf, err := src.EncodeText(nil, nil)
+			if err != nil {
+				return err
+			}
+			*v = string(buf)
+			return nil
+		}
+
+		refVal := reflect.ValueOf(dst)
+		if refVal.Kind() == reflect.Ptr && !refVal.IsNil() && refVal.Elem().Kind() == reflect.Struct {
+			structVal := refVal.Elem()
+			indexes, err := src.structFieldIndexes(structVal.Type())
+			if err != nil {
+				return err
+			}
+			for i, index := range indexes {
+				if index == nil {
+					continue
+				}
+				v, err := src.Fields[i].value()
+				if err != nil {
+					return err
+				}
+				if err := v.AssignTo(structVal.FieldByIndex(index).Addr().Interface()); err != nil {
+					return errors.Errorf("composite field %q: %w", src.Fields[i].Name, err)
+				}
+			}
+			return nil
+		}
+
+		if nextDst, retry := GetAssignToDstType(dst); retry {
+			return src.AssignTo(nextDst)
+		}
+		return errors.Errorf("unable to assign to %T", dst)
+	case Null:
+		return NullAssignTo(dst)
+	}
+
+	return errors.Errorf("cannot decode %#v into %T", src, dst)
+}
+
+// structFieldIndexes returns the index of the struct field of t for each
+// composite field, or nil for a composite field no struct field maps to.
+func (c *Composite) structFieldIndexes(t reflect.Type) ([][]int, error) {
+	var exported []reflect.StructField
+	tagged := false
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		if _, ok := sf.Tag.Lookup("db"); ok {
+			tagged = true
+		}
+		exported = append(exported, sf)
+	}
+
+	indexes := make([][]int, len(c.Fields))
+
+	if !tagged {
+		if len(exported) != len(c.Fields) {
+			return nil, errors.Errorf("cannot map composite with %d fields to %v with %d exported fields", len(c.Fields), t, len(exported))
+		}
+		for i, sf := range exported {
+			indexes[i] = sf.Index
+		}
+		return indexes, nil
+	}
+
+	for _, sf := range exported {
+		name, ok := sf.Tag.Lookup("db")
+		if !ok || name == "-" {
+			continue
+		}
+		found := false
+		for i := range c.Fields {
+			if c.Fields[i].Name == name {
+				indexes[i] = sf.Index
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, errors.Errorf("composite has no field %q for %v.%s", name, t, sf.Name)
+		}
+	}
+	return indexes, nil
+}
+
+// DecodeText parses the record text format, such as (a,"b c",). An unquoted
+// empty field is NULL. Whitespace is part of a field, as it is for the server.
+func (dst *Composite) DecodeText(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		dst.Status = Null
+		return nil
+	}
+
+	fields, err := parseCompositeText(src)
+	if err != nil {
+		return err
+	}
+	if len(dst.Fields) == 0 && len(fields) == 1 && fields[0] == nil && bytes.Equal(bytes.TrimSpace(src), []byte("()")) {
+		fields = nil
+	}
+
+	if len(dst.Fields) == 0 {
+		dst.Fields = make([]CompositeField, len(fields))
+	} else if len(fields) != len(dst.Fields) {
+		return errors.Errorf("composite has %d fields, record has %d", len(dst.Fields), len(fields))
+	}
+
+	for i, field := range fields {
+		if dst.Fields[i].Value == nil {
+			dst.Fields[i].Value = &Text{}
+		}
+		if err := dst.Fields[i].Value.DecodeText(ci, field); err != nil {
+			return errors.Errorf("composite field %q: %w", dst.Fields[i].Name, err)
+		}
+	}
+
+	dst.Status = Present
+	return nil
+}
+
+// parseCompositeText splits a record in text format into its fields, with
+// quoting removed. A NULL field is nil.
+func parseCompositeText(src []byte) ([][]byte, error) {
+	s := bytes.TrimLeft(src, " \t\n\r\v\f")
+	if len(s) == 0 || s[0] != '(' {
+		return nil, errors.Errorf("malformed record literal: %q: missing left parenthesis", src)
+	}
+	rp := 1
+
+	var fields [][]byte
+	for {
+		if rp >= len(s) {
+			return nil, errors.Errorf("malformed record literal: %q: unexpected end of input", src)
+		}
+
+		if s[rp] == ',' || s[rp] == ')' {
+			fields = append(fields, nil)
+		} else {
+			field := []byte{}
+			inQuote := false
+		fieldLoop:
+			for {
+				if rp >= len(s) {
+					return nil, errors.Errorf("malformed record literal: %q: unexpected end of input", src)
+				}
+				c := s[rp]
+				switch {
+				case c == '\\':
+					rp++
+					if rp >= len(s) {
+						return nil, errors.Errorf("malformed record literal: %q: unexpected end of input", src)
+					}
+					field = append(field, s[rp])
+				case c == '"' && inQuote && rp+1 < len(s) && s[rp+1] == '"':
+					rp++
+					field = append(field, '"')
+				case c == '"':
+					inQuote = !inQuote
+				case !inQuote && (c == ',' || c == ')'):
+					break fieldLoop
+				default:
+					field = append(field, c)
+				}
+				rp++
+			}
+			fields = append(fields, field)
+		}
+
+		if s[rp] == ')' {
+			rp++
+			break
+		}
+		rp++
+	}
+
+	if len(bytes.TrimSpace(s[rp:])) > 0 {
+		return nil, errors.Errorf("malformed record literal: %q: junk after right parenthesis", src)
+	}
+
+	return fields, nil
+}
+
+func (dst *Composite) DecodeBinary(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		dst.Status = Null
+		return nil
+	}
+
+	if len(src) < 4 {
+		return errors.Errorf("record too short: %v", len(src))
+	}
+	fieldCount := int(int32(binary.BigEndian.Uint32(src)))
+	rp := 4
+
+	if len(dst.Fields) == 0 {
+		if fieldCount < 0 || fieldCount > len(src[rp:])/8 {
+			return errors.Errorf("invalid record field count: %d", fieldCount)
+		}
+		dst.Fields = make([]CompositeField, fieldCount)
+	} else if fieldCount != len(dst.Fields) {
+		return errors.Errorf("composite has %d fields, record has %d", len(dst.Fields), fieldCount)
+	}
+
+	for i := range dst.Fields {
+		if len(src[rp:]) < 8 {
+			return errors.Errorf("record too short for field %d", i)
+		}
+		oid := binary.BigEndian.Uint32(src[rp:])
+		fieldLen := int(int32(binary.BigEndian.Uint32(src[rp+4:])))
+		rp += 8
+
+		var fieldBytes []byte
+		if fieldLen >= 0 {
+			if len(src[rp:]) < fieldLen {
+				return errors.Errorf("record too short for field %d", i)
+			}
+			fieldBytes = src[rp : rp+fieldLen]
+			rp += fieldLen
+		}
+
+		field := &dst.Fields[i]
+		if field.Value == nil {
+			newValue, ok := compositeFieldTypes[oid]
+			if !ok {
+				return errors.Errorf("unknown OID %d for composite field %d", oid, i)
+			}
+			field.Value = newValue()
+		}
+		if field.OID == 0 {
+			field.OID = oid
+		}
+
+		if err := field.Value.DecodeBinary(ci, fieldBytes); err != nil {
+			return errors.Errorf("composite field %q: %w", field.Name, err)
+		}
+	}
+
+	if rp != len(src) {
+		return errors.Errorf("record has %d trailing bytes", len(src)-rp)
+	}
+
+	dst.Status = Present
+	return nil
+}
+
+func (src Composite) EncodeText(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	buf = append(buf, '(')
+	inFieldBuf := make([]byte, 0, 32)
+	for i := range src.Fields {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+
+		v, err := src.Fields[i].value()
+		if err != nil {
+			return nil, err
+		}
+		b, err := v.EncodeText(ci, inFieldBuf)
+		if err != nil {
+			return nil, errors.Errorf("composite field %q: %w", src.Fields[i].Name, err)
+		}
+		if b != nil {
+			buf = appendCompositeFieldText(buf, b)
+		}
+	}
+
+	return append(buf, ')'), nil
+}
+
+// appendCompositeFieldText quotes a field the way the server's record output
+// does: when it is empty or contains quotes, backslashes, parentheses, commas
+// or whitespace. Quotes and backslashes are doubled.
+func appendCompositeFieldText(buf []byte, b []byte) []byte {
+	if len(b) > 0 && !bytes.ContainsAny(b, "\"\\(), \t\n\r\v\f") {
+		return append(buf, b...)
+	}
+
+	buf = append(buf, '"')
+	for _, c := range b {
+		if c == '"' || c == '\\' {
+			buf = append(buf, c)
+		}
+		buf = append(buf, c)
+	}
+	return append(buf, '"')
+}
+
+func (src Composite) EncodeBinary(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	buf = pgio.AppendInt32(buf, int32(len(src.Fields)))
+	for i := range src.Fields {
+		v, err := src.Fields[i].value()
+		if err != nil {
+			return nil, err
+		}
+		oid, err := src.Fields[i].oid()
+		if err != nil {
+			return nil, err
+		}
+		buf = pgio.AppendUint32(buf, oid)
+
+		sp := len(buf)
+		buf = pgio.AppendInt32(buf, -1)
+
+		fieldBuf, err := v.EncodeBinary(ci, buf)
+		if err != nil {
+			return nil, errors.Errorf("composite field %q: %w", src.Fields[i].Name, err)
+		}
+		if fieldBuf != nil {
+			buf = fieldBuf
+			pgio.SetInt32(buf[sp:], int32(len(buf[sp:])-4))
+		}
+	}
+
+	return buf, nil
+}
+
+// Scan implements the database/sql Scanner interface.
+func (dst *Composite) Scan(src interface{}) error {
+	if src == nil {
+		dst.Status = Null
+		return nil
+	}
+
+	switch src := src.(type) {
+	case string:
+		return dst.DecodeText(nil, []byte(src))
+	case []byte:
+		srcCopy := make([]byte, len(src))
+		copy(srcCopy, src)
+		return dst.DecodeText(nil, srcCopy)
+	}
+
+	return errors.Errorf("cannot scan %T", src)
+}
+
+// Value implements the database/sql/driver Valuer interface.
+func (src Composite) Value() (driver.Value, error) {
+	return EncodeValueText(src)
+}
+
+// MarshalJSON encodes src as a JSON object of its fields in order, as the
+// server's row_to_json does. Unnamed fields are named f1, f2, ... by position.
+// Fields are encoded with their MarshalJSON method, or as JSON of their Get
+// value.
+func (src Composite) MarshalJSON() ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return []byte("null"), nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	buf := []byte{'{'}
+	for i := range src.Fields {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+
+		name, err := json.Marshal(src.Fields[i].jsonName(i))
+		if err != nil {
+			return nil, err
+		}
+		buf = append(buf, name...)
+		buf = append(buf, ':')
+
+		v, err := src.Fields[i].value()
+		if err != nil {
+			return nil, err
+		}
+		var value []byte
+		if m, ok := v.(json.Marshaler); ok {
+			value, err = m.MarshalJSON()
+		} else {
+			value, err = json.Marshal(v.Get())
+		}
+		if err != nil {
+			return nil, errors.Errorf("composite field %q: %w", src.Fields[i].Name, err)
+		}
+		buf = append(buf, value...)
+	}
+
+	return append(buf, '}'), nil
+}
+
+// UnmarshalJSON decodes the form written by MarshalJSON into the fields of dst,
+// which must be set. A field missing from the object is set to NULL.
+func (dst *Composite) UnmarshalJSON(b []byte) error {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return err
+	}
+
+	if obj == nil {
+		dst.Status = Null
+		return nil
+	}
+
+	for i := range dst.Fields {
+		field := &dst.Fields[i]
+		if _, err := field.value(); err != nil {
+			return err
+		}
+		value, ok := obj[field.jsonName(i)]
+		if !ok || string(value) == "null" {
+			if err := field.Value.Set(nil); err != nil {
+				return errors.Errorf("composite field %q: %w", field.Name, err)
+			}
+			continue
+		}
+
+		var err error
+		if u, ok := field.Value.(json.Unmarshaler); ok {
+			err = u.UnmarshalJSON(value)
+		} else {
+			var v interface{}
+			if err = json.Unmarshal(value, &v); err == nil {
+				err = field.Value.Set(v)
+			}
+		}
+		if err != nil {
+			return errors.Errorf("composite field %q: %w", field.Name, err)
+		}
+	}
+
+	dst.Status = Present
+	return nil
+}
@@ -0,0 +1,192 @@
+package tstype_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/tossp/tstype"
+)
+
+func newAddress() *tstype.Composite {
+	return tstype.NewComposite(
+		tstype.CompositeField{Name: "street", Value: &tstype.Text{}},
+		tstype.CompositeField{Name: "city", Value: &tstype.Text{}},
+		tstype.CompositeField{Name: "zip", Value: &tstype.Int4{}},
+		tstype.CompositeField{Name: "lat", Value: &tstype.Numeric{}},
+	)
+}
+
+func TestCompositeDecodeText(t *testing.T) {
+	c := newAddress()
+	if err := c.DecodeText(nil, []byte(`("1 Main St","a ""b"" \\c",,12.5)`)); err != nil {
+		t.Fatal(err)
+	}
+
+	street := c.Fields[0].Value.(*tstype.Text)
+	city := c.Fields[1].Value.(*tstype.Text)
+	zip := c.Fields[2].Value.(*tstype.Int4)
+	lat := c.Fields[3].Value.(*tstype.Numeric)
+	if street.String != "1 Main St" || city.String != `a "b" \c` || zip.Status != tstype.Null || !lat.Decimal.Equal(decimal.RequireFromString("12.5")) {
+		t.Errorf("got %+v %+v %+v %+v", street, city, zip, lat)
+	}
+
+	buf, err := c.EncodeText(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(buf) != `("1 Main St","a ""b"" \\c",,12.5)` {
+		t.Errorf("EncodeText: got %s", buf)
+	}
+
+	for i, src := range []string{"", "(a,b", "a,b)", "(a,b,c,d)x", "(a,b)", `("a,b,c,d)`} {
+		if err := newAddress().DecodeText(nil, []byte(src)); err == nil {
+			t.Errorf("%d: %q: expected error", i, src)
+		}
+	}
+}
+
+func TestCompositeUntyped(t *testing.T) {
+	var c tstype.Composite
+	if err := c.DecodeText(nil, []byte(`(a,"",)`)); err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Fields) != 3 || c.Fields[1].Value.(*tstype.Text).String != "" || c.Fields[2].Value.(*tstype.Text).Status != tstype.Null {
+		t.Errorf("got %+v", c)
+	}
+	if js, _ := json.Marshal(c); string(js) != `{"f1":"a","f2":"","f3":null}` {
+		t.Errorf("MarshalJSON: got %s", js)
+	}
+}
+
+func TestCompositeEmptyStringField(t *testing.T) {
+	c := tstype.NewComposite(
+		tstype.CompositeField{Name: "a", Value: &tstype.Text{}},
+		tstype.CompositeField{Name: "b", Value: &tstype.Text{}},
+	)
+	if err := c.Set([]interface{}{"", nil}); err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := c.EncodeText(nil, nil)
+	if err != nil || string(buf) != `("",)` {
+		t.Fatalf("EncodeText: expected %q, got %q, %v", `("",)`, buf, err)
+	}
+
+	var back tstype.Composite
+	if err := back.DecodeText(nil, buf); err != nil {
+		t.Fatal(err)
+	}
+	first := back.Fields[0].Value.(*tstype.Text)
+	second := back.Fields[1].Value.(*tstype.Text)
+	if first.Status != tstype.Present || first.String != "" || second.Status != tstype.Null {
+		t.Errorf("DecodeText: got %+v %+v", first, second)
+	}
+}
+
+func TestCompositeBinary(t *testing.T) {
+	src := newAddress()
+	if err := src.Set([]interface{}{"1 Main St", "Springfield", 12345, nil}); err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := src.EncodeBinary(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var untyped tstype.Composite
+	if err := untyped.DecodeBinary(nil, buf); err != nil {
+		t.Fatal(err)
+	}
+	text, _ := untyped.EncodeText(nil, nil)
+	if string(text) != `("1 Main St",Springfield,12345,)` {
+		t.Errorf("got %s", text)
+	}
+	if _, ok := untyped.Fields[3].Value.(*tstype.Numeric); !ok {
+		t.Errorf("expected *Numeric for numeric OID, got %T", untyped.Fields[3].Value)
+	}
+
+	if err := untyped.DecodeBinary(nil, buf[:len(buf)-1]); err == nil {
+		t.Error("expected error for truncated input")
+	}
+}
+
+func TestCompositeStruct(t *testing.T) {
+	type positional struct {
+		Street string
+		City   string
+		Zip    *int32
+		Lat    decimal.Decimal
+	}
+	type tagged struct {
+		City   string `db:"city"`
+		Zip    int32  `db:"zip"`
+		Ignore string `db:"-"`
+	}
+
+	c := newAddress()
+	zip := int32(12345)
+	if err := c.Set(positional{Street: "1 Main St", City: "Springfield", Zip: &zip, Lat: decimal.New(125, -1)}); err != nil {
+		t.Fatal(err)
+	}
+
+	var p positional
+	if err := c.AssignTo(&p); err != nil {
+		t.Fatal(err)
+	}
+	if p.Street != "1 Main St" || p.City != "Springfield" || p.Zip == nil || *p.Zip != zip || !p.Lat.Equal(decimal.New(125, -1)) {
+		t.Errorf("positional: got %+v", p)
+	}
+
+	var tg tagged
+	if err := c.AssignTo(&tg); err != nil {
+		t.Fatal(err)
+	}
+	if tg.City != "Springfield" || tg.Zip != zip {
+		t.Errorf("tagged: got %+v", tg)
+	}
+
+	var short struct{ Street string }
+	if err := c.AssignTo(&short); err == nil {
+		t.Error("expected error for struct with too few fields")
+	}
+}
+
+func TestCompositeFieldWithoutValue(t *testing.T) {
+	c := tstype.NewComposite(
+		tstype.CompositeField{Name: "id", Value: &tstype.Int4{}},
+		tstype.CompositeField{Name: "note"},
+	)
+
+	if err := c.Set([]interface{}{1, "x"}); err == nil {
+		t.Error("Set []interface{}: expected error")
+	}
+	if err := c.Set(struct {
+		ID   int32
+		Note string
+	}{1, "x"}); err == nil {
+		t.Error("Set struct: expected error")
+	}
+	if err := c.UnmarshalJSON([]byte(`{"id":1,"note":"x"}`)); err == nil {
+		t.Error("UnmarshalJSON: expected error")
+	}
+
+	c.Status = tstype.Present
+	if _, err := c.EncodeText(nil, nil); err == nil {
+		t.Error("EncodeText: expected error")
+	}
+	if _, err := c.EncodeBinary(nil, nil); err == nil {
+		t.Error("EncodeBinary: expected error")
+	}
+	if _, err := c.MarshalJSON(); err == nil {
+		t.Error("MarshalJSON: expected error")
+	}
+	var dst struct {
+		ID   int32
+		Note string
+	}
+	if err := c.AssignTo(&dst); err == nil {
+		t.Error("AssignTo struct: expected error")
+	}
+}
@@ -417,6 +417,11 @@ func GetAssignToDstType(dst interface{}) (interface{}, bool) {
 	if dstVal.Kind() == reflect.Slice {
 		if baseElemType, ok := kindTypes[dstVal.Type().Elem().Kind()]; ok {
 			baseSliceType := reflect.PtrTo(reflect.SliceOf(baseElemType))
+			// A slice or array of a named element type has a different
+			// underlying type and is left to the callers' reflection fallback.
+			if !dstPtr.Type().ConvertibleTo(baseSliceType) {
+				return nil, false
+			}
 			nextDst := dstPtr.Convert(baseSliceType)
 			return nextDst.Interface(), dstPtr.Type() != nextDst.Type()
 		}
@@ -425,6 +430,9 @@ func GetAssignToDstType(dst interface{}) (interface{}, bool) {
 	if dstVal.Kind() == reflect.Array {
 		if baseElemType, ok := kindTypes[dstVal.Type().Elem().Kind()]; ok {
 			baseArrayType := reflect.PtrTo(reflect.ArrayOf(dstVal.Len(), baseElemType))
+			if !dstPtr.Type().ConvertibleTo(baseArrayType) {
+				return nil, false
+			}
 			nextDst := dstPtr.Convert(baseArrayType)
 			return nextDst.Interface(), dstPtr.Type() != nextDst.Type()
 		}
@@ -0,0 +1,356 @@
+package tstype
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgio"
+	"github.com/jackc/pgtype"
+
+	errors "golang.org/x/xerrors"
+)
+
+const (
+	negativeInfinityDayOffset = -2147483648
+	infinityDayOffset         = 2147483647
+)
+
+type Date struct {
+	Time             time.Time
+	Status           Status
+	InfinityModifier pgtype.InfinityModifier
+}
+
+func (dst *Date) Set(src interface{}) error {
+	if src == nil {
+		*dst = Date{Status: Null}
+		return nil
+	}
+
+	if value, ok := src.(interface{ Get() interface{} }); ok {
+		value2 := value.Get()
+		if value2 != value {
+			return dst.Set(value2)
+		}
+	}
+
+	switch value := src.(type) {
+	case time.Time:
+		*dst = Date{Time: value, Status: Present}
+	case string:
+		return dst.DecodeText(nil, []byte(value))
+	case *time.Time:
+		if value == nil {
+			*dst = Date{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	case *string:
+		if value == nil {
+			*dst = Date{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	case pgtype.InfinityModifier:
+		*dst = Date{InfinityModifier: value, Status: Present}
+	default:
+		if originalSrc, ok := underlyingTimeType(src); ok {
+			return dst.Set(originalSrc)
+		}
+		return errors.Errorf("cannot convert %v to Date", value)
+	}
+
+	return nil
+}
+
+func (dst Date) Get() interface{} {
+	switch dst.Status {
+	case Present:
+		if dst.InfinityModifier != pgtype.None {
+			return dst.InfinityModifier
+		}
+		return dst.Time
+	case Null:
+		return nil
+	default:
+		return dst.Status
+	}
+}
+
+func (src *Date) AssignTo(dst interface{}) error {
+	switch src.Status {
+	case Present:
+		switch v := dst.(type) {
+		case *time.Time:
+			if src.InfinityModifier != pgtype.None {
+				return errors.Errorf("cannot assign %v to %T", src, dst)
+			}
+			*v = src.Time
+			return nil
+		case *string:
+			buf, err := src.EncodeText(nil, nil)
+			if err != nil {
+				return err
+			}
+			*v = string(buf)
+			return nil
+		default:
+			if nextDst, retry := GetAssignToDstType(dst); retry {
+				return src.AssignTo(nextDst)
+			}
+			return errors.Errorf("unable to assign to %T", dst)
+		}
+	case Null:
+		return NullAssignTo(dst)
+	}
+
+	return errors.Errorf("cannot decode %#v into %T", src, dst)
+}
+
+func (dst *Date) DecodeText(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Date{Status: Null}
+		return nil
+	}
+
+	sbuf := string(src)
+	switch sbuf {
+	case "infinity":
+		*dst = Date{Status: Present, InfinityModifier: pgtype.Infinity}
+	case "-infinity":
+		*dst = Date{Status: Present, InfinityModifier: pgtype.NegativeInfinity}
+	default:
+		t, err := parseDate(sbuf)
+		if err != nil {
+			return err
+		}
+
+		*dst = Date{Time: t, Status: Present}
+	}
+
+	return nil
+}
+
+func (dst *Date) DecodeBinary(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Date{Status: Null}
+		return nil
+	}
+
+	if len(src) != 4 {
+		return errors.Errorf("invalid length for date: %v", len(src))
+	}
+
+	dayOffset := int32(binary.BigEndian.Uint32(src))
+
+	switch dayOffset {
+	case infinityDayOffset:
+		*dst = Date{Status: Present, InfinityModifier: pgtype.Infinity}
+	case negativeInfinityDayOffset:
+		*dst = Date{Status: Present, InfinityModifier: pgtype.NegativeInfinity}
+	default:
+		t := time.Date(2000, 1, int(1+dayOffset), 0, 0, 0, 0, time.UTC)
+		*dst = Date{Time: t, Status: Present}
+	}
+
+	return nil
+}
+
+func (src Date) EncodeText(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	var s string
+
+	switch src.InfinityModifier {
+	case pgtype.None:
+		s = formatDate(src.Time)
+	case pgtype.Infinity:
+		s = "infinity"
+	case pgtype.NegativeInfinity:
+		s = "-infinity"
+	}
+
+	return append(buf, s...), nil
+}
+
+func (src Date) EncodeBinary(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	var daysSinceDateEpoch int32
+	switch src.InfinityModifier {
+	case pgtype.None:
+		tUnix := time.Date(src.Time.Year(), src.Time.Month(), src.Time.Day(), 0, 0, 0, 0, time.UTC).Unix()
+		dateEpoch := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+
+		secSinceDateEpoch := tUnix - dateEpoch
+		daysSinceDateEpoch = int32(secSinceDateEpoch / 86400)
+	case pgtype.Infinity:
+		daysSinceDateEpoch = infinityDayOffset
+	case pgtype.NegativeInfinity:
+		daysSinceDateEpoch = negativeInfinityDayOffset
+	}
+
+	return pgio.AppendInt32(buf, daysSinceDateEpoch), nil
+}
+
+// Scan implements the database/sql Scanner interface.
+func (dst *Date) Scan(src interface{}) error {
+	if src == nil {
+		*dst = Date{Status: Null}
+		return nil
+	}
+
+	switch src := src.(type) {
+	case string:
+		return dst.DecodeText(nil, []byte(src))
+	case []byte:
+		srcCopy := make([]byte, len(src))
+		copy(srcCopy, src)
+		return dst.DecodeText(nil, srcCopy)
+	case time.Time:
+		*dst = Date{Time: src, Status: Present}
+		return nil
+	}
+
+	return errors.Errorf("cannot scan %T", src)
+}
+
+// Value implements the database/sql/driver Valuer interface.
+func (src Date) Value() (driver.Value, error) {
+	switch src.Status {
+	case Present:
+		if src.InfinityModifier != pgtype.None {
+			return src.InfinityModifier.String(), nil
+		}
+		return src.Time, nil
+	case Null:
+		return nil, nil
+	default:
+		return nil, errBadStatus
+	}
+}
+
+// MarshalJSON encodes the date as YYYY-MM-DD rather than a full RFC3339
+// timestamp. Dates before year 1 get a " BC" suffix, as in the text format.
+func (src Date) MarshalJSON() ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return []byte("null"), nil
+	}
+
+	if src.Status != Present {
+		return nil, errBadStatus
+	}
+
+	var s string
+
+	switch src.InfinityModifier {
+	case pgtype.None:
+		s = formatDate(src.Time)
+	case pgtype.Infinity:
+		s = "infinity"
+	case pgtype.NegativeInfinity:
+		s = "-infinity"
+	}
+
+	return json.Marshal(s)
+}
+
+func (dst *Date) UnmarshalJSON(b []byte) error {
+	var s *string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		return err
+	}
+
+	if s == nil {
+		*dst = Date{Status: Null}
+		return nil
+	}
+
+	return dst.DecodeText(nil, []byte(*s))
+}
+
+// parseDate parses an ISO date as PostgreSQL prints it, e.g. "2000-01-01",
+// "12345-06-07" or "0044-03-15 BC". Unlike time.Parse it accepts years with
+// more than four digits and the BC suffix.
+func parseDate(s string) (time.Time, error) {
+	bc := false
+	if strings.HasSuffix(s, " BC") {
+		bc = true
+		s = s[:len(s)-3]
+	}
+
+	parts := strings.Split(s, "-")
+	if len(parts) != 3 || len(parts[0]) < 4 || len(parts[1]) != 2 || len(parts[2]) != 2 {
+		return time.Time{}, errors.Errorf("invalid date format: %q", s)
+	}
+
+	year, err := strconv.ParseUint(parts[0], 10, 31)
+	if err != nil {
+		return time.Time{}, errors.Errorf("invalid date year: %q", s)
+	}
+	month, err := strconv.ParseUint(parts[1], 10, 8)
+	if err != nil || month < 1 || month > 12 {
+		return time.Time{}, errors.Errorf("invalid date month: %q", s)
+	}
+	day, err := strconv.ParseUint(parts[2], 10, 8)
+	if err != nil || day < 1 {
+		return time.Time{}, errors.Errorf("invalid date day: %q", s)
+	}
+	if year == 0 {
+		return time.Time{}, errors.Errorf("invalid date year: %q", s)
+	}
+
+	y := int(year)
+	if bc {
+		// There is no year 0 in the Gregorian calendar: 1 BC is astronomical year 0.
+		y = 1 - y
+	}
+
+	t := time.Date(y, time.Month(month), int(day), 0, 0, 0, 0, time.UTC)
+	if t.Day() != int(day) {
+		return time.Time{}, errors.Errorf("invalid date day: %q", s)
+	}
+
+	return t, nil
+}
+
+// formatDate is the inverse of parseDate.
+func formatDate(t time.Time) string {
+	return formatDateYear(t.Year()) + t.Format("-01-02") + formatDateEra(t.Year())
+}
+
+func formatDateYear(year int) string {
+	if year <= 0 {
+		year = 1 - year
+	}
+	s := strconv.Itoa(year)
+	if len(s) < 4 {
+		s = strings.Repeat("0", 4-len(s)) + s
+	}
+	return s
+}
+
+func formatDateEra(year int) string {
+	if year <= 0 {
+		return " BC"
+	}
+	return ""
+}
@@ -0,0 +1,150 @@
+package tstype_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgtype"
+	"github.com/tossp/tstype"
+)
+
+func TestDateTextRoundTrip(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected tstype.Date
+	}{
+		{"2000-01-01", tstype.Date{Time: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), Status: tstype.Present}},
+		{"1999-12-31", tstype.Date{Time: time.Date(1999, 12, 31, 0, 0, 0, 0, time.UTC), Status: tstype.Present}},
+		{"12345-06-07", tstype.Date{Time: time.Date(12345, 6, 7, 0, 0, 0, 0, time.UTC), Status: tstype.Present}},
+		{"0001-01-01 BC", tstype.Date{Time: time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC), Status: tstype.Present}},
+		{"0044-03-15 BC", tstype.Date{Time: time.Date(-43, 3, 15, 0, 0, 0, 0, time.UTC), Status: tstype.Present}},
+		{"infinity", tstype.Date{Status: tstype.Present, InfinityModifier: pgtype.Infinity}},
+		{"-infinity", tstype.Date{Status: tstype.Present, InfinityModifier: pgtype.NegativeInfinity}},
+	}
+
+	for i, tt := range tests {
+		var d tstype.Date
+		if err := d.DecodeText(nil, []byte(tt.src)); err != nil {
+			t.Errorf("%d: %v", i, err)
+			continue
+		}
+		if d != tt.expected {
+			t.Errorf("%d: %q: expected %v, got %v", i, tt.src, tt.expected, d)
+		}
+
+		buf, err := d.EncodeText(nil, nil)
+		if err != nil || string(buf) != tt.src {
+			t.Errorf("%d: EncodeText: expected %q, got %q, %v", i, tt.src, buf, err)
+		}
+	}
+
+	for i, src := range []string{"", "2000-1-01", "2000-02-30", "0000-01-01", "2000-13-01", "99-01-01"} {
+		var d tstype.Date
+		if err := d.DecodeText(nil, []byte(src)); err == nil {
+			t.Errorf("%d: %q: expected error, got %v", i, src, d)
+		}
+	}
+}
+
+func TestDateBinary(t *testing.T) {
+	tests := []struct {
+		date     tstype.Date
+		expected []byte
+	}{
+		{tstype.Date{Time: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), Status: tstype.Present}, []byte{0, 0, 0, 0}},
+		{tstype.Date{Time: time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC), Status: tstype.Present}, []byte{0, 0, 0, 1}},
+		{tstype.Date{Time: time.Date(1999, 12, 31, 0, 0, 0, 0, time.UTC), Status: tstype.Present}, []byte{0xff, 0xff, 0xff, 0xff}},
+		{tstype.Date{Time: time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC), Status: tstype.Present}, []byte{0xff, 0xf4, 0xda, 0x8b}},
+		{tstype.Date{Status: tstype.Present, InfinityModifier: pgtype.Infinity}, []byte{0x7f, 0xff, 0xff, 0xff}},
+		{tstype.Date{Status: tstype.Present, InfinityModifier: pgtype.NegativeInfinity}, []byte{0x80, 0, 0, 0}},
+	}
+
+	for i, tt := range tests {
+		buf, err := tt.date.EncodeBinary(nil, nil)
+		if err != nil || !bytes.Equal(buf, tt.expected) {
+			t.Errorf("%d: EncodeBinary: expected %x, got %x, %v", i, tt.expected, buf, err)
+		}
+
+		var d tstype.Date
+		if err := d.DecodeBinary(nil, tt.expected); err != nil || d != tt.date {
+			t.Errorf("%d: DecodeBinary: expected %v, got %v, %v", i, tt.date, d, err)
+		}
+	}
+
+	// The time of day and location of Time are ignored.
+	d := tstype.Date{Time: time.Date(2000, 1, 2, 23, 59, 0, 0, time.FixedZone("", -8*60*60)), Status: tstype.Present}
+	if buf, _ := d.EncodeBinary(nil, nil); !bytes.Equal(buf, []byte{0, 0, 0, 1}) {
+		t.Errorf("EncodeBinary with time of day: got %x", buf)
+	}
+
+	var null tstype.Date
+	if err := null.DecodeBinary(nil, nil); err != nil || null.Status != tstype.Null {
+		t.Errorf("DecodeBinary nil: got %v, %v", null, err)
+	}
+	if err := null.DecodeBinary(nil, []byte{0, 0, 0}); err == nil {
+		t.Error("DecodeBinary short: expected error")
+	}
+}
+
+func TestDateEncodeInvalidStatus(t *testing.T) {
+	d := tstype.Date{Time: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), Status: tstype.Status(2)}
+	if _, err := d.EncodeText(nil, nil); err == nil {
+		t.Error("EncodeText: expected error")
+	}
+	if _, err := d.EncodeBinary(nil, nil); err == nil {
+		t.Error("EncodeBinary: expected error")
+	}
+}
+
+func TestDateAssignTo(t *testing.T) {
+	d := tstype.Date{Time: time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC), Status: tstype.Present}
+
+	var tm time.Time
+	if err := d.AssignTo(&tm); err != nil || !tm.Equal(d.Time) {
+		t.Errorf("AssignTo time.Time: got %v, %v", tm, err)
+	}
+
+	var ptm *time.Time
+	if err := d.AssignTo(&ptm); err != nil || ptm == nil || !ptm.Equal(d.Time) {
+		t.Errorf("AssignTo *time.Time: got %v, %v", ptm, err)
+	}
+
+	var s string
+	if err := d.AssignTo(&s); err != nil || s != "2020-02-29" {
+		t.Errorf("AssignTo string: got %q, %v", s, err)
+	}
+
+	inf := tstype.Date{Status: tstype.Present, InfinityModifier: pgtype.Infinity}
+	if err := inf.AssignTo(&tm); err == nil {
+		t.Error("AssignTo time.Time from infinity: expected error")
+	}
+	if err := inf.AssignTo(&s); err != nil || s != "infinity" {
+		t.Errorf("AssignTo string from infinity: got %q, %v", s, err)
+	}
+
+	null := tstype.Date{Status: tstype.Null}
+	if err := null.AssignTo(&ptm); err != nil || ptm != nil {
+		t.Errorf("AssignTo *time.Time from Null: got %v, %v", ptm, err)
+	}
+	if err := null.AssignTo(&tm); err == nil {
+		t.Error("AssignTo time.Time from Null: expected error")
+	}
+}
+
+func TestDateJSON(t *testing.T) {
+	d := tstype.Date{Time: time.Date(-43, 3, 15, 0, 0, 0, 0, time.UTC), Status: tstype.Present}
+	buf, err := json.Marshal(d)
+	if err != nil || string(buf) != `"0044-03-15 BC"` {
+		t.Errorf("MarshalJSON: got %s, %v", buf, err)
+	}
+
+	var back tstype.Date
+	if err := json.Unmarshal(buf, &back); err != nil || back != d {
+		t.Errorf("UnmarshalJSON: got %v, %v", back, err)
+	}
+	if err := json.Unmarshal([]byte("null"), &back); err != nil || back.Status != tstype.Null {
+		t.Errorf("UnmarshalJSON null: got %v, %v", back, err)
+	}
+}
@@ -0,0 +1,203 @@
+package tstype
+
+import (
+	"database/sql/driver"
+	"time"
+
+	"github.com/jackc/pgtype"
+
+	errors "golang.org/x/xerrors"
+)
+
+// Datemultirange represents the PostgreSQL datemultirange type, available since
+// PostgreSQL 14. Ranges are kept in the order they were set or decoded; use
+// Canonical to sort and merge them as the server does.
+type Datemultirange struct {
+	Ranges []Daterange
+	Status Status
+}
+
+func (dst *Datemultirange) Set(src interface{}) error {
+	if src == nil {
+		*dst = Datemultirange{Status: Null}
+		return nil
+	}
+
+	switch value := src.(type) {
+	case Datemultirange:
+		*dst = value
+	case *Datemultirange:
+		if value == nil {
+			*dst = Datemultirange{Status: Null}
+		} else {
+			*dst = *value
+		}
+	case []Daterange:
+		if value == nil {
+			*dst = Datemultirange{Status: Null}
+		} else {
+			*dst = Datemultirange{Ranges: value, Status: Present}
+		}
+	case string:
+		return dst.DecodeText(nil, []byte(value))
+	case *string:
+		if value == nil {
+			*dst = Datemultirange{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	default:
+		return errors.Errorf("cannot convert %v to Datemultirange", src)
+	}
+
+	return nil
+}
+
+func (dst Datemultirange) Get() interface{} {
+	switch dst.Status {
+	case Present:
+		return dst
+	case Null:
+		return nil
+	default:
+		return dst.Status
+	}
+}
+
+func (src *Datemultirange) AssignTo(dst interface{}) error {
+	switch src.Status {
+	case Present:
+		switch v := dst.(type) {
+		case *Datemultirange:
+			*v = *src
+			return nil
+		case *[]Daterange:
+			*v = make([]Daterange, len(src.Ranges))
+			copy(*v, src.Ranges)
+			return nil
+		case *string:
+			buf, err := src.EncodeText(nil, nil)
+			if err != nil {
+				return err
+			}
+			*v = string(buf)
+			return nil
+		default:
+			if nextDst, retry := GetAssignToDstType(dst); retry {
+				return src.AssignTo(nextDst)
+			}
+			return errors.Errorf("unable to assign to %T", dst)
+		}
+	case Null:
+		return NullAssignTo(dst)
+	}
+
+	return errors.Errorf("cannot decode %#v into %T", src, dst)
+}
+
+func (dst *Datemultirange) DecodeText(ci *pgtype.ConnInfo, src []byte) error {
+	m, err := decodeMultirangeText[Daterange, Date](ci, src)
+	if err != nil {
+		return err
+	}
+
+	*dst = Datemultirange(m)
+	return nil
+}
+
+func (dst *Datemultirange) DecodeBinary(ci *pgtype.ConnInfo, src []byte) error {
+	m, err := decodeMultirangeBinary[Daterange, Date](ci, src)
+	if err != nil {
+		return err
+	}
+
+	*dst = Datemultirange(m)
+	return nil
+}
+
+func (src Datemultirange) EncodeText(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	return encodeMultirangeText(ci, buf, multirangeValue[Daterange](src))
+}
+
+func (src Datemultirange) EncodeBinary(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	return encodeMultirangeBinary(ci, buf, multirangeValue[Daterange](src))
+}
+
+// Scan implements the database/sql Scanner interface.
+func (dst *Datemultirange) Scan(src interface{}) error {
+	if src == nil {
+		*dst = Datemultirange{Status: Null}
+		return nil
+	}
+
+	switch src := src.(type) {
+	case string:
+		return dst.DecodeText(nil, []byte(src))
+	case []byte:
+		srcCopy := make([]byte, len(src))
+		copy(srcCopy, src)
+		return dst.DecodeText(nil, srcCopy)
+	}
+
+	return errors.Errorf("cannot scan %T", src)
+}
+
+// Value implements the database/sql/driver Valuer interface.
+func (src Datemultirange) Value() (driver.Value, error) {
+	return EncodeValueText(src)
+}
+
+// MarshalJSON encodes src as a JSON array of its ranges, each in the form
+// written by Daterange.MarshalJSON.
+func (src Datemultirange) MarshalJSON() ([]byte, error) {
+	return marshalMultirangeJSON(multirangeValue[Daterange](src))
+}
+
+func (dst *Datemultirange) UnmarshalJSON(b []byte) error {
+	m, err := unmarshalMultirangeJSON[Daterange, Date](b)
+	if err != nil {
+		return err
+	}
+
+	*dst = Datemultirange(m)
+	return nil
+}
+
+// Canonical returns src in the form the server stores it. Each range is
+// canonicalized, empty ranges are dropped, and the rest are sorted with
+// overlapping and adjacent ranges merged. It returns an error for a range the
+// server would reject.
+func (src Datemultirange) Canonical() (Datemultirange, error) {
+	m, err := canonicalMultirange(dateRangeSubtype, multirangeValue[Daterange](src), true)
+	if err != nil {
+		return Datemultirange{}, err
+	}
+	return Datemultirange(m), nil
+}
+
+// IsEmpty reports whether src contains no values.
+func (src Datemultirange) IsEmpty() bool {
+	if src.Status != Present {
+		return false
+	}
+	for _, r := range src.Ranges {
+		if !r.IsEmpty() {
+			return false
+		}
+	}
+	return true
+}
+
+// Contains reports whether v is in one of the ranges of src, as the server's @>
+// operator does.
+func (src Datemultirange) Contains(v time.Time) bool {
+	if src.Status != Present {
+		return false
+	}
+	for _, r := range src.Ranges {
+		if r.Contains(v) {
+			return true
+		}
+	}
+	return false
+}
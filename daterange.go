@@ -0,0 +1,222 @@
+package tstype
+
+import (
+	"database/sql/driver"
+	"time"
+
+	"github.com/jackc/pgtype"
+
+	errors "golang.org/x/xerrors"
+)
+
+// Daterange represents the PostgreSQL daterange type. Lower and Upper are only
+// used when LowerType and UpperType are pgtype.Inclusive or pgtype.Exclusive.
+// An empty range has both bound types set to pgtype.Empty.
+type Daterange struct {
+	Lower     Date
+	Upper     Date
+	LowerType pgtype.BoundType
+	UpperType pgtype.BoundType
+	Status    Status
+}
+
+func (dst *Daterange) Set(src interface{}) error {
+	if src == nil {
+		*dst = Daterange{Status: Null}
+		return nil
+	}
+
+	switch value := src.(type) {
+	case Daterange:
+		*dst = value
+	case *Daterange:
+		if value == nil {
+			*dst = Daterange{Status: Null}
+		} else {
+			*dst = *value
+		}
+	case string:
+		return dst.DecodeText(nil, []byte(value))
+	case *string:
+		if value == nil {
+			*dst = Daterange{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	default:
+		return errors.Errorf("cannot convert %v to Daterange", src)
+	}
+
+	return nil
+}
+
+func (dst Daterange) Get() interface{} {
+	switch dst.Status {
+	case Present:
+		return dst
+	case Null:
+		return nil
+	default:
+		return dst.Status
+	}
+}
+
+func (src *Daterange) AssignTo(dst interface{}) error {
+	switch src.Status {
+	case Present:
+		switch v := dst.(type) {
+		case *Daterange:
+			*v = *src
+			return nil
+		case *string:
+			buf, err := src.EncodeText(nil, nil)
+			if err != nil {
+				return err
+			}
+			*v = string(buf)
+			return nil
+		default:
+			if nextDst, retry := GetAssignToDstType(dst); retry {
+				return src.AssignTo(nextDst)
+			}
+			return errors.Errorf("unable to assign to %T", dst)
+		}
+	case Null:
+		return NullAssignTo(dst)
+	}
+
+	return errors.Errorf("cannot decode %#v into %T", src, dst)
+}
+
+func (dst *Daterange) DecodeText(ci *pgtype.ConnInfo, src []byte) error {
+	r, err := decodeRangeText[Date](ci, src)
+	if err != nil {
+		return err
+	}
+
+	*dst = Daterange(r)
+	return nil
+}
+
+func (dst *Daterange) DecodeBinary(ci *pgtype.ConnInfo, src []byte) error {
+	r, err := decodeRangeBinary[Date](ci, src)
+	if err != nil {
+		return err
+	}
+
+	*dst = Daterange(r)
+	return nil
+}
+
+func (src Daterange) EncodeText(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	return encodeRangeText(ci, buf, rangeValue[Date](src))
+}
+
+func (src Daterange) EncodeBinary(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	return encodeRangeBinary(ci, buf, rangeValue[Date](src))
+}
+
+// Scan implements the database/sql Scanner interface.
+func (dst *Daterange) Scan(src interface{}) error {
+	if src == nil {
+		*dst = Daterange{Status: Null}
+		return nil
+	}
+
+	switch src := src.(type) {
+	case string:
+		return dst.DecodeText(nil, []byte(src))
+	case []byte:
+		srcCopy := make([]byte, len(src))
+		copy(srcCopy, src)
+		return dst.DecodeText(nil, srcCopy)
+	}
+
+	return errors.Errorf("cannot scan %T", src)
+}
+
+// Value implements the database/sql/driver Valuer interface.
+func (src Daterange) Value() (driver.Value, error) {
+	return EncodeValueText(src)
+}
+
+// MarshalJSON encodes src as {"lower": "2020-01-01", "upper": "2020-02-01",
+// "bounds": "[)"}, with null for an unbounded side, or as {"empty": true}.
+func (src Daterange) MarshalJSON() ([]byte, error) {
+	return marshalRangeJSON(rangeValue[Date](src))
+}
+
+func (dst *Daterange) UnmarshalJSON(b []byte) error {
+	r, err := unmarshalRangeJSON[Date](b)
+	if err != nil {
+		return err
+	}
+
+	*dst = Daterange(r)
+	return nil
+}
+
+// Canonical returns src in the form the server stores it. Discrete bounds are
+// made inclusive lower and exclusive upper, except for infinity. A range with
+// no values becomes empty. It returns an error for a range the server would
+// reject, such as one with a lower bound greater than its upper bound.
+func (src Daterange) Canonical() (Daterange, error) {
+	r, err := dateRangeSubtype.makeRange(rangeValue[Date](src), true)
+	if err != nil {
+		return Daterange{}, err
+	}
+	return Daterange(r), nil
+}
+
+// IsEmpty reports whether src contains no values.
+func (src Daterange) IsEmpty() bool {
+	r := dateRangeSubtype.normalize(rangeValue[Date](src))
+	return r.Status == Present && r.isEmpty()
+}
+
+// Contains reports whether v is in src, as the server's @> operator does.
+func (src Daterange) Contains(v time.Time) bool {
+	return dateRangeSubtype.contains(rangeValue[Date](src), Date{Time: v, Status: Present})
+}
+
+// ContainsRange reports whether other is entirely within src, as the server's
+// @> operator does. Every range contains the empty range.
+func (src Daterange) ContainsRange(other Daterange) bool {
+	return dateRangeSubtype.containsRange(rangeValue[Date](src), rangeValue[Date](other))
+}
+
+// Overlaps reports whether src and other have values in common, as the server's
+// && operator does.
+func (src Daterange) Overlaps(other Daterange) bool {
+	return dateRangeSubtype.overlaps(rangeValue[Date](src), rangeValue[Date](other))
+}
+
+// Adjacent reports whether src and other do not overlap and no value lies
+// between them, as the server's -|- operator does.
+func (src Daterange) Adjacent(other Daterange) bool {
+	return dateRangeSubtype.adjacent(rangeValue[Date](src), rangeValue[Date](other))
+}
+
+// Intersect returns the values src and other have in common, as the server's *
+// operator does. The result is Null if either range is Null.
+func (src Daterange) Intersect(other Daterange) Daterange {
+	return Daterange(dateRangeSubtype.intersect(rangeValue[Date](src), rangeValue[Date](other)))
+}
+
+var dateRangeSubtype = rangeSubtype[Date]{
+	cmp: func(a, b Date) int {
+		return cmpInfinityTime(a.InfinityModifier, b.InfinityModifier, dateOnly(a.Time), dateOnly(b.Time))
+	},
+	next: func(v Date) (Date, bool, error) {
+		if v.InfinityModifier != pgtype.None {
+			return v, false, nil
+		}
+		return Date{Time: dateOnly(v.Time).AddDate(0, 0, 1), Status: Present}, true, nil
+	},
+}
+
+// dateOnly returns the calendar date of t as midnight UTC.
+func dateOnly(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
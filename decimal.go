@@ -108,6 +108,8 @@ func (src *Numeric) AssignTo(dst interface{}) error {
 		switch v := dst.(type) {
 		case *decimal.Decimal:
 			*v = src.Decimal
+		case *string:
+			*v = src.Decimal.String()
 		case *float32:
 			f, _ := src.Decimal.Float64()
 			*v = float32(f)
@@ -0,0 +1,463 @@
+package tstype
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"sort"
+	"sync"
+
+	"github.com/jackc/pgtype"
+
+	errors "golang.org/x/xerrors"
+)
+
+// maxEnumLabelLen is the longest label the server accepts, NAMEDATALEN - 1
+// bytes.
+const maxEnumLabelLen = 63
+
+// EnumType is the set of labels of a PostgreSQL enum type, in sort order. It
+// is immutable once created and safe for concurrent use.
+type EnumType struct {
+	name   string
+	labels []string
+	index  map[string]int
+}
+
+// NewEnumType returns the enum type name with labels in sort order, as created
+// by CREATE TYPE name AS ENUM (labels...). It returns an error for an empty,
+// too long or duplicate label.
+func NewEnumType(name string, labels ...string) (*EnumType, error) {
+	t := &EnumType{
+		name:   name,
+		labels: make([]string, len(labels)),
+		index:  make(map[string]int, len(labels)),
+	}
+
+	for i, label := range labels {
+		if label == "" || len(label) > maxEnumLabelLen {
+			return nil, errors.Errorf("invalid enum label %q: must be 1 to %d bytes", label, maxEnumLabelLen)
+		}
+		if _, ok := t.index[label]; ok {
+			return nil, errors.Errorf("enum label %q used more than once", label)
+		}
+		t.labels[i] = label
+		t.index[label] = i
+	}
+
+	return t, nil
+}
+
+// EnumRows is the subset of *sql.Rows and pgx.Rows that LoadEnumType reads.
+type EnumRows interface {
+	Next() bool
+	Scan(dest ...interface{}) error
+	Err() error
+}
+
+// LoadEnumType returns the enum type name from rows of enumlabel and
+// enumsortorder read from pg_enum, such as the result of
+//
+//	SELECT enumlabel, enumsortorder FROM pg_enum WHERE enumtypid = $1::regtype
+//
+// The rows may be in any order. LoadEnumType does not close rows.
+func LoadEnumType(name string, rows EnumRows) (*EnumType, error) {
+	type enumLabel struct {
+		label     string
+		sortOrder float64
+	}
+
+	var labels []enumLabel
+	for rows.Next() {
+		var l enumLabel
+		if err := rows.Scan(&l.label, &l.sortOrder); err != nil {
+			return nil, err
+		}
+		labels = append(labels, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(labels, func(i, j int) bool {
+		return labels[i].sortOrder < labels[j].sortOrder
+	})
+
+	names := make([]string, len(labels))
+	for i, l := range labels {
+		names[i] = l.label
+	}
+
+	return NewEnumType(name, names...)
+}
+
+// Name returns the name of the enum type.
+func (t *EnumType) Name() string {
+	return t.name
+}
+
+// Labels returns the labels of t in sort order.
+func (t *EnumType) Labels() []string {
+	labels := make([]string, len(t.labels))
+	copy(labels, t.labels)
+	return labels
+}
+
+// Has reports whether label is one of the labels of t.
+func (t *EnumType) Has(label string) bool {
+	_, ok := t.index[label]
+	return ok
+}
+
+// Position returns the position of label in the sort order of t, or false if
+// label is not one of its labels.
+func (t *EnumType) Position(label string) (int, bool) {
+	i, ok := t.index[label]
+	return i, ok
+}
+
+// Enum returns label as a Present Enum of type t. It returns an error if label
+// is not one of the labels of t.
+func (t *EnumType) Enum(label string) (Enum, error) {
+	e := Enum{String: label, Type: t, Status: Present}
+	if err := e.validate(); err != nil {
+		return Enum{}, err
+	}
+	return e, nil
+}
+
+func (t *EnumType) invalidLabelError(label string) error {
+	return errors.Errorf("invalid input value for enum %s: %q", t.name, label)
+}
+
+var (
+	enumTypesMu sync.RWMutex
+	enumTypes   = map[string]*EnumType{}
+)
+
+// RegisterEnumType makes t available to LookupEnumType and TypedEnum under its
+// name, replacing any enum type previously registered with that name.
+func RegisterEnumType(t *EnumType) {
+	enumTypesMu.Lock()
+	defer enumTypesMu.Unlock()
+	enumTypes[t.name] = t
+}
+
+// LookupEnumType returns the enum type registered with name.
+func LookupEnumType(name string) (*EnumType, bool) {
+	enumTypesMu.RLock()
+	defer enumTypesMu.RUnlock()
+	t, ok := enumTypes[name]
+	return t, ok
+}
+
+// Enum represents a value of a PostgreSQL enum type. When Type is set, Set,
+// DecodeText, DecodeBinary, UnmarshalJSON and the encoders reject labels that
+// are not in Type, and Set and the decoders keep Type. When Type is nil any
+// label is accepted, as with Text. Use TypedEnum to validate values scanned
+// into without setting Type.
+type Enum struct {
+	String string
+	Type   *EnumType
+	Status Status
+}
+
+func (src Enum) validate() error {
+	if src.Status != Present || src.Type == nil || src.Type.Has(src.String) {
+		return nil
+	}
+	return src.Type.invalidLabelError(src.String)
+}
+
+func (dst *Enum) Set(src interface{}) error {
+	if src == nil {
+		*dst = Enum{Type: dst.Type, Status: Null}
+		return nil
+	}
+
+	if value, ok := src.(interface{ Get() interface{} }); ok {
+		value2 := value.Get()
+		if value2 != value {
+			return dst.Set(value2)
+		}
+	}
+
+	var e Enum
+	switch value := src.(type) {
+	case string:
+		e = Enum{String: value, Type: dst.Type, Status: Present}
+	case *string:
+		if value == nil {
+			e = Enum{Type: dst.Type, Status: Null}
+		} else {
+			e = Enum{String: *value, Type: dst.Type, Status: Present}
+		}
+	case []byte:
+		if value == nil {
+			e = Enum{Type: dst.Type, Status: Null}
+		} else {
+			e = Enum{String: string(value), Type: dst.Type, Status: Present}
+		}
+	default:
+		if originalSrc, ok := underlyingStringType(src); ok {
+			return dst.Set(originalSrc)
+		}
+		return errors.Errorf("cannot convert %v to Enum", value)
+	}
+
+	if err := e.validate(); err != nil {
+		return err
+	}
+
+	*dst = e
+	return nil
+}
+
+func (dst Enum) Get() interface{} {
+	switch dst.Status {
+	case Present:
+		return dst.String
+	case Null:
+		return nil
+	default:
+		return dst.Status
+	}
+}
+
+func (src *Enum) AssignTo(dst interface{}) error {
+	switch src.Status {
+	case Present:
+		switch v := dst.(type) {
+		case *string:
+			*v = src.String
+			return nil
+		case *[]byte:
+			*v = make([]byte, len(src.String))
+			copy(*v, src.String)
+			return nil
+		default:
+			if nextDst, retry := GetAssignToDstType(dst); retry {
+				return src.AssignTo(nextDst)
+			}
+			return errors.Errorf("unable to assign to %T", dst)
+		}
+	case Null:
+		return NullAssignTo(dst)
+	}
+
+	return errors.Errorf("cannot decode %#v into %T", src, dst)
+}
+
+func (Enum) PreferredResultFormat() int16 {
+	return pgtype.TextFormatCode
+}
+
+func (dst *Enum) DecodeText(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Enum{Type: dst.Type, Status: Null}
+		return nil
+	}
+
+	e := Enum{String: string(src), Type: dst.Type, Status: Present}
+	if err := e.validate(); err != nil {
+		return err
+	}
+
+	*dst = e
+	return nil
+}
+
+func (dst *Enum) DecodeBinary(ci *pgtype.ConnInfo, src []byte) error {
+	return dst.DecodeText(ci, src)
+}
+
+func (Enum) PreferredParamFormat() int16 {
+	return pgtype.TextFormatCode
+}
+
+func (src Enum) EncodeText(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	if err := src.validate(); err != nil {
+		return nil, err
+	}
+
+	return append(buf, src.String...), nil
+}
+
+func (src Enum) EncodeBinary(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	return src.EncodeText(ci, buf)
+}
+
+// Scan implements the database/sql Scanner interface.
+func (dst *Enum) Scan(src interface{}) error {
+	if src == nil {
+		*dst = Enum{Type: dst.Type, Status: Null}
+		return nil
+	}
+
+	switch src := src.(type) {
+	case string:
+		return dst.DecodeText(nil, []byte(src))
+	case []byte:
+		srcCopy := make([]byte, len(src))
+		copy(srcCopy, src)
+		return dst.DecodeText(nil, srcCopy)
+	}
+
+	return errors.Errorf("cannot scan %T", src)
+}
+
+// Value implements the database/sql/driver Valuer interface.
+func (src Enum) Value() (driver.Value, error) {
+	return EncodeValueText(src)
+}
+
+func (src Enum) MarshalJSON() ([]byte, error) {
+	switch src.Status {
+	case Present:
+		return json.Marshal(src.String)
+	case Null:
+		return []byte("null"), nil
+	}
+
+	return nil, errBadStatus
+}
+
+func (dst *Enum) UnmarshalJSON(b []byte) error {
+	var s *string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		return err
+	}
+
+	return dst.Set(s)
+}
+
+// Compare orders src and other by the sort order of their enum type, as the
+// server's comparison operators do, returning -1, 0 or 1. Null sorts after
+// every label. It returns an error if src and other have different enum
+// types, neither has a type, or a label is not in the type.
+func (src Enum) Compare(other Enum) (int, error) {
+	t := src.Type
+	if t == nil {
+		t = other.Type
+	} else if other.Type != nil && other.Type != t {
+		return 0, errors.Errorf("cannot compare enum %s with enum %s", t.name, other.Type.name)
+	}
+	if t == nil {
+		return 0, errors.New("cannot compare enums without an enum type")
+	}
+
+	position := func(e Enum) (int, error) {
+		switch e.Status {
+		case Present:
+			i, ok := t.Position(e.String)
+			if !ok {
+				return 0, t.invalidLabelError(e.String)
+			}
+			return i, nil
+		case Null:
+			return len(t.labels), nil
+		}
+		return 0, errBadStatus
+	}
+
+	i, err := position(src)
+	if err != nil {
+		return 0, err
+	}
+	j, err := position(other)
+	if err != nil {
+		return 0, err
+	}
+
+	return cmpInt64(int64(i), int64(j)), nil
+}
+
+// EnumTypeName is the type parameter of TypedEnum. The EnumTypeName method of
+// its zero value returns the name of a registered enum type.
+type EnumTypeName interface {
+	EnumTypeName() string
+}
+
+// TypedEnum is an Enum bound to the enum type registered with the name given by
+// T, so that a zero TypedEnum, such as a struct field or slice element being
+// scanned into, validates labels without setting Type:
+//
+//	type mood struct{}
+//
+//	func (mood) EnumTypeName() string { return "mood" }
+//
+//	var m tstype.TypedEnum[mood]
+//	err := row.Scan(&m)
+//
+// Set, the decoders and the encoders set Type to the registered enum type
+// first, and return an error if none is registered with that name.
+type TypedEnum[T EnumTypeName] struct {
+	Enum
+}
+
+func (e *TypedEnum[T]) bind() error {
+	var name T
+	t, ok := LookupEnumType(name.EnumTypeName())
+	if !ok {
+		return errors.Errorf("enum type %s is not registered", name.EnumTypeName())
+	}
+	e.Type = t
+	return nil
+}
+
+func (dst *TypedEnum[T]) Set(src interface{}) error {
+	if err := dst.bind(); err != nil {
+		return err
+	}
+	return dst.Enum.Set(src)
+}
+
+func (dst *TypedEnum[T]) DecodeText(ci *pgtype.ConnInfo, src []byte) error {
+	if err := dst.bind(); err != nil {
+		return err
+	}
+	return dst.Enum.DecodeText(ci, src)
+}
+
+func (dst *TypedEnum[T]) DecodeBinary(ci *pgtype.ConnInfo, src []byte) error {
+	return dst.DecodeText(ci, src)
+}
+
+func (src TypedEnum[T]) EncodeText(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	if err := src.bind(); err != nil {
+		return nil, err
+	}
+	return src.Enum.EncodeText(ci, buf)
+}
+
+func (src TypedEnum[T]) EncodeBinary(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	return src.EncodeText(ci, buf)
+}
+
+// Scan implements the database/sql Scanner interface.
+func (dst *TypedEnum[T]) Scan(src interface{}) error {
+	if err := dst.bind(); err != nil {
+		return err
+	}
+	return dst.Enum.Scan(src)
+}
+
+// Value implements the database/sql/driver Valuer interface.
+func (src TypedEnum[T]) Value() (driver.Value, error) {
+	return EncodeValueText(src)
+}
+
+func (dst *TypedEnum[T]) UnmarshalJSON(b []byte) error {
+	if err := dst.bind(); err != nil {
+		return err
+	}
+	return dst.Enum.UnmarshalJSON(b)
+}
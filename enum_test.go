@@ -0,0 +1,150 @@
+package tstype_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/tossp/tstype"
+)
+
+type enumRows struct {
+	labels     []string
+	sortOrders []float64
+	i          int
+}
+
+func (r *enumRows) Next() bool {
+	r.i++
+	return r.i <= len(r.labels)
+}
+
+func (r *enumRows) Scan(dest ...interface{}) error {
+	*dest[0].(*string) = r.labels[r.i-1]
+	*dest[1].(*float64) = r.sortOrders[r.i-1]
+	return nil
+}
+
+func (r *enumRows) Err() error {
+	return nil
+}
+
+func TestLoadEnumType(t *testing.T) {
+	// ALTER TYPE ... ADD VALUE 'ok' BEFORE 'happy' gives a fractional sort order.
+	rows := &enumRows{labels: []string{"happy", "sad", "ok"}, sortOrders: []float64{2, 1, 1.5}}
+	mood, err := tstype.LoadEnumType("mood", rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	labels := mood.Labels()
+	if len(labels) != 3 || labels[0] != "sad" || labels[1] != "ok" || labels[2] != "happy" {
+		t.Errorf("got %v", labels)
+	}
+
+	if _, err := tstype.NewEnumType("mood", "sad", "sad"); err == nil {
+		t.Error("expected error for duplicate label")
+	}
+}
+
+func TestEnumValidation(t *testing.T) {
+	mood, err := tstype.NewEnumType("mood", "sad", "ok", "happy")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := tstype.Enum{Type: mood}
+	if err := e.Set("ok"); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Set("angry"); err == nil || err.Error() != `invalid input value for enum mood: "angry"` {
+		t.Errorf("Set: got %v", err)
+	}
+	if e.String != "ok" {
+		t.Errorf("Set: expected failed Set to leave %q, got %q", "ok", e.String)
+	}
+	if err := e.DecodeText(nil, []byte("Happy")); err == nil {
+		t.Error("DecodeText: expected error for label in wrong case")
+	}
+	if err := json.Unmarshal([]byte(`"meh"`), &e); err == nil {
+		t.Error("UnmarshalJSON: expected error")
+	}
+	if err := json.Unmarshal([]byte(`"happy"`), &e); err != nil || e.String != "happy" || e.Type != mood {
+		t.Errorf("UnmarshalJSON: got %+v, %v", e, err)
+	}
+	if js, _ := json.Marshal(e); string(js) != `"happy"` {
+		t.Errorf("MarshalJSON: got %s", js)
+	}
+
+	var untyped tstype.Enum
+	if err := untyped.Set("anything"); err != nil {
+		t.Errorf("untyped Set: %v", err)
+	}
+}
+
+func TestEnumCompare(t *testing.T) {
+	mood, _ := tstype.NewEnumType("mood", "sad", "ok", "happy")
+	sad, _ := mood.Enum("sad")
+	happy, _ := mood.Enum("happy")
+
+	if c, err := happy.Compare(sad); err != nil || c != 1 {
+		t.Errorf("got %d, %v", c, err)
+	}
+	if c, err := sad.Compare(tstype.Enum{String: "ok", Status: tstype.Present}); err != nil || c != -1 {
+		t.Errorf("got %d, %v", c, err)
+	}
+	if c, err := happy.Compare(tstype.Enum{Status: tstype.Null}); err != nil || c != -1 {
+		t.Errorf("got %d, %v", c, err)
+	}
+
+	other, _ := tstype.NewEnumType("color", "sad")
+	otherSad, _ := other.Enum("sad")
+	if _, err := sad.Compare(otherSad); err == nil {
+		t.Error("expected error comparing different enum types")
+	}
+}
+
+type mood struct{}
+
+func (mood) EnumTypeName() string { return "mood" }
+
+type unregistered struct{}
+
+func (unregistered) EnumTypeName() string { return "unregistered" }
+
+func TestTypedEnum(t *testing.T) {
+	moodType, err := tstype.NewEnumType("mood", "sad", "ok", "happy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tstype.RegisterEnumType(moodType)
+	if registered, ok := tstype.LookupEnumType("mood"); !ok || registered != moodType {
+		t.Fatal("LookupEnumType")
+	}
+
+	var e tstype.TypedEnum[mood]
+	if err := e.Scan("angry"); err == nil || err.Error() != `invalid input value for enum mood: "angry"` {
+		t.Errorf("Scan unknown label: got %v", err)
+	}
+	if err := e.Scan([]byte("happy")); err != nil || e.String != "happy" || e.Type != moodType {
+		t.Errorf("Scan: got %+v, %v", e, err)
+	}
+	if err := e.Scan(nil); err != nil || e.Status != tstype.Null || e.Type != moodType {
+		t.Errorf("Scan nil: got %+v, %v", e, err)
+	}
+
+	var fields struct {
+		Moods []tstype.TypedEnum[mood] `json:"moods"`
+	}
+	if err := json.Unmarshal([]byte(`{"moods":["ok","meh"]}`), &fields); err == nil {
+		t.Error("UnmarshalJSON unknown label: expected error")
+	}
+
+	unbound := tstype.TypedEnum[mood]{Enum: tstype.Enum{String: "meh", Status: tstype.Present}}
+	if _, err := unbound.Value(); err == nil {
+		t.Error("Value unknown label: expected error")
+	}
+
+	var missing tstype.TypedEnum[unregistered]
+	if err := missing.DecodeText(nil, []byte("x")); err == nil || err.Error() != "enum type unregistered is not registered" {
+		t.Errorf("DecodeText unregistered type: got %v", err)
+	}
+}
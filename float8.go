@@ -0,0 +1,368 @@
+package tstype
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgio"
+	"github.com/jackc/pgtype"
+
+	errors "golang.org/x/xerrors"
+)
+
+// PostgreSQL spells the non-finite float values differently than strconv. These
+// spellings are used for the text format and for JSON, where encoding/json
+// refuses to encode non-finite numbers.
+const (
+	pgFloatNaN              = "NaN"
+	pgFloatInfinity         = "Infinity"
+	pgFloatNegativeInfinity = "-Infinity"
+)
+
+// formatPgFloat formats f the way PostgreSQL does for float4 and float8: the
+// shortest representation that round-trips, in exponential notation such as
+// "1e+300" or "1.5e-05" when the decimal exponent is below -4 or at least the
+// precision of the type, 15 for float8 and 6 for float4.
+func formatPgFloat(f float64, bitSize int) string {
+	switch {
+	case math.IsNaN(f):
+		return pgFloatNaN
+	case math.IsInf(f, 1):
+		return pgFloatInfinity
+	case math.IsInf(f, -1):
+		return pgFloatNegativeInfinity
+	}
+
+	s := strconv.FormatFloat(f, 'e', -1, bitSize)
+	exp, _ := strconv.Atoi(s[strings.IndexByte(s, 'e')+1:])
+	maxExp := 15
+	if bitSize == 32 {
+		maxExp = 6
+	}
+	if exp < -4 || exp >= maxExp {
+		return s
+	}
+	return strconv.FormatFloat(f, 'f', -1, bitSize)
+}
+
+// parsePgFloat parses the PostgreSQL text representation of float4 and float8.
+// strconv.ParseFloat already accepts "NaN", "Infinity" and "-Infinity" case
+// insensitively.
+func parsePgFloat(s string, bitSize int) (float64, error) {
+	return strconv.ParseFloat(s, bitSize)
+}
+
+// marshalPgFloatJSON encodes finite values as JSON numbers and non-finite
+// values as strings, the same way PostgreSQL's to_json does.
+func marshalPgFloatJSON(f float64, bitSize int) []byte {
+	s := formatPgFloat(f, bitSize)
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return []byte(`"` + s + `"`)
+	}
+	return []byte(s)
+}
+
+// unmarshalPgFloatJSON is the inverse of marshalPgFloatJSON. The returned bool
+// is false when b is JSON null.
+func unmarshalPgFloatJSON(b []byte, bitSize int) (float64, bool, error) {
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return 0, false, err
+	}
+
+	switch v := v.(type) {
+	case nil:
+		return 0, false, nil
+	case float64:
+		return v, true, nil
+	case string:
+		f, err := parsePgFloat(v, bitSize)
+		if err != nil {
+			return 0, false, err
+		}
+		return f, true, nil
+	}
+
+	return 0, false, errors.Errorf("cannot unmarshal %s into float", b)
+}
+
+type Float8 struct {
+	Float  float64
+	Status Status
+}
+
+func (dst *Float8) Set(src interface{}) error {
+	if src == nil {
+		*dst = Float8{Status: Null}
+		return nil
+	}
+
+	if value, ok := src.(interface{ Get() interface{} }); ok {
+		value2 := value.Get()
+		if value2 != value {
+			return dst.Set(value2)
+		}
+	}
+
+	switch value := src.(type) {
+	case float32:
+		*dst = Float8{Float: float64(value), Status: Present}
+	case float64:
+		*dst = Float8{Float: value, Status: Present}
+	case int8:
+		*dst = Float8{Float: float64(value), Status: Present}
+	case uint8:
+		*dst = Float8{Float: float64(value), Status: Present}
+	case int16:
+		*dst = Float8{Float: float64(value), Status: Present}
+	case uint16:
+		*dst = Float8{Float: float64(value), Status: Present}
+	case int32:
+		*dst = Float8{Float: float64(value), Status: Present}
+	case uint32:
+		*dst = Float8{Float: float64(value), Status: Present}
+	case int64:
+		f64 := float64(value)
+		if int64(f64) == value {
+			*dst = Float8{Float: f64, Status: Present}
+		} else {
+			return errors.Errorf("%v cannot be exactly represented as float64", value)
+		}
+	case uint64:
+		f64 := float64(value)
+		if uint64(f64) == value {
+			*dst = Float8{Float: f64, Status: Present}
+		} else {
+			return errors.Errorf("%v cannot be exactly represented as float64", value)
+		}
+	case int:
+		return dst.Set(int64(value))
+	case uint:
+		return dst.Set(uint64(value))
+	case string:
+		num, err := parsePgFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		*dst = Float8{Float: num, Status: Present}
+	case *float64:
+		if value == nil {
+			*dst = Float8{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	case *float32:
+		if value == nil {
+			*dst = Float8{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	case *int8:
+		if value == nil {
+			*dst = Float8{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	case *uint8:
+		if value == nil {
+			*dst = Float8{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	case *int16:
+		if value == nil {
+			*dst = Float8{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	case *uint16:
+		if value == nil {
+			*dst = Float8{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	case *int32:
+		if value == nil {
+			*dst = Float8{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	case *uint32:
+		if value == nil {
+			*dst = Float8{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	case *int64:
+		if value == nil {
+			*dst = Float8{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	case *uint64:
+		if value == nil {
+			*dst = Float8{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	case *int:
+		if value == nil {
+			*dst = Float8{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	case *uint:
+		if value == nil {
+			*dst = Float8{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	case *string:
+		if value == nil {
+			*dst = Float8{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	default:
+		if originalSrc, ok := underlyingNumberType(src); ok {
+			return dst.Set(originalSrc)
+		}
+		return errors.Errorf("cannot convert %v to Float8", value)
+	}
+
+	return nil
+}
+
+func (dst Float8) Get() interface{} {
+	switch dst.Status {
+	case Present:
+		return dst.Float
+	case Null:
+		return nil
+	default:
+		return dst.Status
+	}
+}
+
+func (src *Float8) AssignTo(dst interface{}) error {
+	return float64AssignTo(src.Float, src.Status, dst)
+}
+
+func (dst *Float8) DecodeText(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Float8{Status: Null}
+		return nil
+	}
+
+	n, err := parsePgFloat(string(src), 64)
+	if err != nil {
+		return err
+	}
+
+	*dst = Float8{Float: n, Status: Present}
+	return nil
+}
+
+func (dst *Float8) DecodeBinary(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Float8{Status: Null}
+		return nil
+	}
+
+	if len(src) != 8 {
+		return errors.Errorf("invalid length for float8: %v", len(src))
+	}
+
+	n := binary.BigEndian.Uint64(src)
+
+	*dst = Float8{Float: math.Float64frombits(n), Status: Present}
+	return nil
+}
+
+func (src Float8) EncodeText(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Present:
+		return append(buf, formatPgFloat(src.Float, 64)...), nil
+	case Null:
+		return nil, nil
+	default:
+		return nil, errBadStatus
+	}
+}
+
+func (src Float8) EncodeBinary(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Present:
+		return pgio.AppendUint64(buf, math.Float64bits(src.Float)), nil
+	case Null:
+		return nil, nil
+	default:
+		return nil, errBadStatus
+	}
+}
+
+// Scan implements the database/sql Scanner interface.
+func (dst *Float8) Scan(src interface{}) error {
+	if src == nil {
+		*dst = Float8{Status: Null}
+		return nil
+	}
+
+	switch src := src.(type) {
+	case float64:
+		*dst = Float8{Float: src, Status: Present}
+		return nil
+	case string:
+		return dst.DecodeText(nil, []byte(src))
+	case []byte:
+		srcCopy := make([]byte, len(src))
+		copy(srcCopy, src)
+		return dst.DecodeText(nil, srcCopy)
+	}
+
+	return errors.Errorf("cannot scan %T", src)
+}
+
+// Value implements the database/sql/driver Valuer interface.
+func (src Float8) Value() (driver.Value, error) {
+	switch src.Status {
+	case Present:
+		return src.Float, nil
+	case Null:
+		return nil, nil
+	default:
+		return nil, errBadStatus
+	}
+}
+
+// MarshalJSON encodes NaN and the infinities as the strings "NaN", "Infinity"
+// and "-Infinity".
+func (src Float8) MarshalJSON() ([]byte, error) {
+	switch src.Status {
+	case Present:
+		return marshalPgFloatJSON(src.Float, 64), nil
+	case Null:
+		return []byte("null"), nil
+	}
+
+	return nil, errBadStatus
+}
+
+func (dst *Float8) UnmarshalJSON(b []byte) error {
+	f, ok, err := unmarshalPgFloatJSON(b, 64)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		*dst = Float8{Status: Null}
+	} else {
+		*dst = Float8{Float: f, Status: Present}
+	}
+
+	return nil
+}
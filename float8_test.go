@@ -0,0 +1,127 @@
+package tstype_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/tossp/tstype"
+)
+
+func TestFloat8NonFiniteText(t *testing.T) {
+	tests := []struct {
+		text  string
+		check func(float64) bool
+	}{
+		{text: "NaN", check: math.IsNaN},
+		{text: "Infinity", check: func(f float64) bool { return math.IsInf(f, 1) }},
+		{text: "-Infinity", check: func(f float64) bool { return math.IsInf(f, -1) }},
+	}
+
+	for i, tt := range tests {
+		var f tstype.Float8
+		if err := f.DecodeText(nil, []byte(tt.text)); err != nil {
+			t.Fatalf("%d: %v", i, err)
+		}
+		if !tt.check(f.Float) {
+			t.Errorf("%d: unexpected value %v", i, f.Float)
+		}
+
+		buf, err := f.EncodeText(nil, nil)
+		if err != nil {
+			t.Fatalf("%d: %v", i, err)
+		}
+		if string(buf) != tt.text {
+			t.Errorf("%d: expected %q, got %q", i, tt.text, buf)
+		}
+
+		buf, err = f.MarshalJSON()
+		if err != nil {
+			t.Fatalf("%d: %v", i, err)
+		}
+		if string(buf) != `"`+tt.text+`"` {
+			t.Errorf("%d: expected JSON %q, got %s", i, tt.text, buf)
+		}
+
+		var g tstype.Float8
+		if err := g.UnmarshalJSON(buf); err != nil {
+			t.Fatalf("%d: %v", i, err)
+		}
+		if g.Status != tstype.Present || !tt.check(g.Float) {
+			t.Errorf("%d: JSON round trip produced %v", i, g)
+		}
+	}
+}
+
+func TestFloatEncodeTextMagnitudes(t *testing.T) {
+	float8s := []struct {
+		f        float64
+		expected string
+	}{
+		{1e300, "1e+300"},
+		{-1.5e300, "-1.5e+300"},
+		{math.MaxFloat64, "1.7976931348623157e+308"},
+		{1e-300, "1e-300"},
+		{math.SmallestNonzeroFloat64, "5e-324"},
+		{0.00001, "1e-05"},
+		{0.0001, "0.0001"},
+		{123456789012345, "123456789012345"},
+		{1234567890123456, "1.234567890123456e+15"},
+		{0.1, "0.1"},
+		{0, "0"},
+	}
+	for i, tt := range float8s {
+		buf, err := tstype.Float8{Float: tt.f, Status: tstype.Present}.EncodeText(nil, nil)
+		if err != nil || string(buf) != tt.expected {
+			t.Errorf("%d: Float8: expected %q, got %q, %v", i, tt.expected, buf, err)
+		}
+		var back tstype.Float8
+		if err := back.DecodeText(nil, buf); err != nil || back.Float != tt.f {
+			t.Errorf("%d: Float8 round trip: expected %v, got %v, %v", i, tt.f, back.Float, err)
+		}
+	}
+
+	float4s := []struct {
+		f        float32
+		expected string
+	}{
+		{1e30, "1e+30"},
+		{math.MaxFloat32, "3.4028235e+38"},
+		{1e-30, "1e-30"},
+		{123456, "123456"},
+		{1234567, "1.234567e+06"},
+		{0.1, "0.1"},
+	}
+	for i, tt := range float4s {
+		buf, err := tstype.Float4{Float: tt.f, Status: tstype.Present}.EncodeText(nil, nil)
+		if err != nil || string(buf) != tt.expected {
+			t.Errorf("%d: Float4: expected %q, got %q, %v", i, tt.expected, buf, err)
+		}
+	}
+
+	if js, err := (tstype.Float8{Float: 1e300, Status: tstype.Present}).MarshalJSON(); err != nil || string(js) != "1e+300" {
+		t.Errorf("MarshalJSON: got %s, %v", js, err)
+	}
+}
+
+func TestFloat4Binary(t *testing.T) {
+	src := tstype.Float4{Float: float32(math.Inf(-1)), Status: tstype.Present}
+	buf, err := src.EncodeBinary(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var dst tstype.Float4
+	if err := dst.DecodeBinary(nil, buf); err != nil {
+		t.Fatal(err)
+	}
+	if !math.IsInf(float64(dst.Float), -1) {
+		t.Errorf("expected -Infinity, got %v", dst.Float)
+	}
+}
+
+func TestFloat4SetOutOfRange(t *testing.T) {
+	var f tstype.Float4
+	if err := f.Set(1e300); err == nil {
+		t.Error("expected error for value out of range")
+	}
+}
@@ -0,0 +1,494 @@
+package tstype
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"strings"
+
+	"github.com/jackc/pgio"
+	"github.com/jackc/pgtype"
+
+	errors "golang.org/x/xerrors"
+)
+
+// geoEpsilon is the tolerance of the server's fuzzy geometric comparisons.
+// Values closer together than geoEpsilon compare as equal.
+const geoEpsilon = 1.0e-06
+
+func fpZero(a float64) bool        { return math.Abs(a) <= geoEpsilon }
+func fpEq(a, b float64) bool       { return a == b || math.Abs(a-b) <= geoEpsilon }
+func fpLt(a, b float64) bool       { return b-a > geoEpsilon }
+func fpLe(a, b float64) bool       { return a-b <= geoEpsilon }
+func fpGt(a, b float64) bool       { return a-b > geoEpsilon }
+func fpGe(a, b float64) bool       { return b-a <= geoEpsilon }
+func vec2Eq(a, b pgtype.Vec2) bool { return fpEq(a.X, b.X) && fpEq(a.Y, b.Y) }
+
+// geoDelimiters end a float in the geometric text formats.
+const geoDelimiters = ",()[]<>{}"
+
+// geoParser parses the geometric text formats as leniently as the server
+// does: points may be written as (x,y) or x,y, and the outer delimiters of a
+// point list may be left off.
+type geoParser struct {
+	typeName string
+	s        string
+	rp       int
+}
+
+func (p *geoParser) syntaxError() error {
+	return errors.Errorf("invalid input syntax for type %s: %q", p.typeName, p.s)
+}
+
+func (p *geoParser) skipSpace() {
+	p.rp = skipTsSpace(p.s, p.rp)
+}
+
+// consume skips spaces and then c, and reports whether c was there.
+func (p *geoParser) consume(c byte) bool {
+	p.skipSpace()
+	if p.rp < len(p.s) && p.s[p.rp] == c {
+		p.rp++
+		return true
+	}
+	return false
+}
+
+// peek returns the next byte after spaces, or 0 at the end of the input.
+func (p *geoParser) peek() byte {
+	p.skipSpace()
+	if p.rp < len(p.s) {
+		return p.s[p.rp]
+	}
+	return 0
+}
+
+func (p *geoParser) parseFloat() (float64, error) {
+	p.skipSpace()
+	start := p.rp
+	for p.rp < len(p.s) && !isTsSpace(p.s[p.rp]) && strings.IndexByte(geoDelimiters, p.s[p.rp]) < 0 {
+		p.rp++
+	}
+	f, err := parsePgFloat(p.s[start:p.rp], 64)
+	if err != nil {
+		return 0, p.syntaxError()
+	}
+	return f, nil
+}
+
+// parsePoint parses (x,y) or x,y.
+func (p *geoParser) parsePoint() (pgtype.Vec2, error) {
+	paren := p.consume('(')
+
+	x, err := p.parseFloat()
+	if err != nil {
+		return pgtype.Vec2{}, err
+	}
+	if !p.consume(',') {
+		return pgtype.Vec2{}, p.syntaxError()
+	}
+	y, err := p.parseFloat()
+	if err != nil {
+		return pgtype.Vec2{}, err
+	}
+
+	if paren && !p.consume(')') {
+		return pgtype.Vec2{}, p.syntaxError()
+	}
+	return pgtype.Vec2{X: x, Y: y}, nil
+}
+
+// parsePoints parses a comma separated list of points, optionally enclosed in
+// parentheses, or in square brackets when allowOpen is set. It parses exactly n
+// points, or as many as there are when n is negative. open reports whether
+// the list was enclosed in square brackets.
+func (p *geoParser) parsePoints(n int, allowOpen bool) (points []pgtype.Vec2, open bool, err error) {
+	depth := 0
+	switch p.peek() {
+	case '[':
+		if !allowOpen {
+			return nil, false, p.syntaxError()
+		}
+		p.rp++
+		depth++
+		open = true
+	case '(':
+		// The parenthesis encloses the list rather than the first point when
+		// another one follows it, or when it is the only one.
+		next := skipTsSpace(p.s, p.rp+1)
+		if (next < len(p.s) && p.s[next] == '(') || strings.LastIndexByte(p.s, '(') == p.rp {
+			p.rp++
+			depth++
+		}
+	}
+
+	for {
+		point, err := p.parsePoint()
+		if err != nil {
+			return nil, false, err
+		}
+		points = append(points, point)
+
+		more := p.consume(',')
+		if n >= 0 && len(points) == n {
+			break
+		}
+		if !more {
+			if n >= 0 {
+				return nil, false, p.syntaxError()
+			}
+			break
+		}
+	}
+
+	if depth > 0 {
+		closing := byte(')')
+		if open {
+			closing = ']'
+		}
+		if !p.consume(closing) {
+			return nil, false, p.syntaxError()
+		}
+	}
+
+	return points, open, nil
+}
+
+// end returns an error unless the whole input has been parsed.
+func (p *geoParser) end() error {
+	if p.skipSpace(); p.rp != len(p.s) {
+		return p.syntaxError()
+	}
+	return nil
+}
+
+func appendVec2Text(buf []byte, v pgtype.Vec2) []byte {
+	buf = append(buf, '(')
+	buf = append(buf, formatPgFloat(v.X, 64)...)
+	buf = append(buf, ',')
+	buf = append(buf, formatPgFloat(v.Y, 64)...)
+	return append(buf, ')')
+}
+
+func appendVec2sText(buf []byte, points []pgtype.Vec2) []byte {
+	for i, v := range points {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = appendVec2Text(buf, v)
+	}
+	return buf
+}
+
+func appendFloat8Binary(buf []byte, f float64) []byte {
+	return pgio.AppendUint64(buf, math.Float64bits(f))
+}
+
+func appendVec2Binary(buf []byte, v pgtype.Vec2) []byte {
+	buf = appendFloat8Binary(buf, v.X)
+	return appendFloat8Binary(buf, v.Y)
+}
+
+// readFloat8s reads n float8 values from src, which must hold exactly that
+// many.
+func readFloat8s(typeName string, src []byte, n int) ([]float64, error) {
+	if len(src) != n*8 {
+		return nil, errors.Errorf("invalid length for %s: %v", typeName, len(src))
+	}
+
+	fs := make([]float64, n)
+	for i := range fs {
+		fs[i] = math.Float64frombits(binary.BigEndian.Uint64(src[i*8:]))
+	}
+	return fs, nil
+}
+
+// decodeVec2sBinary decodes the point list of the path and polygon binary
+// formats: a point count followed by the points.
+func decodeVec2sBinary(typeName string, src []byte) ([]pgtype.Vec2, error) {
+	if len(src) < 4 {
+		return nil, errors.Errorf("invalid length for %s: %v", typeName, len(src))
+	}
+	n := int(int32(binary.BigEndian.Uint32(src)))
+	if n <= 0 || n > (len(src)-4)/16 {
+		return nil, errors.Errorf("invalid number of points in external %q value", typeName)
+	}
+
+	fs, err := readFloat8s(typeName, src[4:], n*2)
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]pgtype.Vec2, n)
+	for i := range points {
+		points[i] = pgtype.Vec2{X: fs[i*2], Y: fs[i*2+1]}
+	}
+	return points, nil
+}
+
+func appendVec2sBinary(buf []byte, points []pgtype.Vec2) []byte {
+	buf = pgio.AppendInt32(buf, int32(len(points)))
+	for _, v := range points {
+		buf = appendVec2Binary(buf, v)
+	}
+	return buf
+}
+
+// geoJSONPosition is a GeoJSON position. Non-finite coordinates are encoded
+// as strings, the same way Float8 encodes them.
+type geoJSONPosition pgtype.Vec2
+
+func (p geoJSONPosition) MarshalJSON() ([]byte, error) {
+	buf := []byte{'['}
+	buf = append(buf, marshalPgFloatJSON(p.X, 64)...)
+	buf = append(buf, ',')
+	buf = append(buf, marshalPgFloatJSON(p.Y, 64)...)
+	return append(buf, ']'), nil
+}
+
+func (p *geoJSONPosition) UnmarshalJSON(b []byte) error {
+	var coordinates []json.RawMessage
+	if err := json.Unmarshal(b, &coordinates); err != nil {
+		return err
+	}
+	if len(coordinates) != 2 {
+		return errors.Errorf("GeoJSON position must have 2 coordinates: %s", b)
+	}
+
+	var xy [2]float64
+	for i, c := range coordinates {
+		f, ok, err := unmarshalPgFloatJSON(c, 64)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errors.Errorf("GeoJSON coordinate cannot be null: %s", b)
+		}
+		xy[i] = f
+	}
+
+	*p = geoJSONPosition{X: xy[0], Y: xy[1]}
+	return nil
+}
+
+func geoJSONPositions(points []pgtype.Vec2) []geoJSONPosition {
+	positions := make([]geoJSONPosition, len(points))
+	for i, v := range points {
+		positions[i] = geoJSONPosition(v)
+	}
+	return positions
+}
+
+func vec2sFromGeoJSON(positions []geoJSONPosition) []pgtype.Vec2 {
+	points := make([]pgtype.Vec2, len(positions))
+	for i, p := range positions {
+		points[i] = pgtype.Vec2(p)
+	}
+	return points
+}
+
+// geoJSONRing returns points as a closed GeoJSON linear ring, whose last
+// position repeats the first.
+func geoJSONRing(points []pgtype.Vec2) [][]geoJSONPosition {
+	ring := geoJSONPositions(points)
+	if len(ring) > 0 {
+		ring = append(ring, ring[0])
+	}
+	return [][]geoJSONPosition{ring}
+}
+
+// vec2sFromGeoJSONRing is the inverse of geoJSONRing. Polygons with holes
+// have no PostgreSQL equivalent.
+func vec2sFromGeoJSONRing(rings [][]geoJSONPosition) ([]pgtype.Vec2, error) {
+	if len(rings) != 1 {
+		return nil, errors.Errorf("GeoJSON Polygon must have exactly 1 ring, not %d", len(rings))
+	}
+
+	ring := rings[0]
+	if n := len(ring); n > 1 && ring[0] == ring[n-1] {
+		ring = ring[:n-1]
+	}
+	if len(ring) == 0 {
+		return nil, errors.New("GeoJSON Polygon ring cannot be empty")
+	}
+	return vec2sFromGeoJSON(ring), nil
+}
+
+type geoJSONGeometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+func marshalGeoJSON(typ string, coordinates interface{}) ([]byte, error) {
+	c, err := json.Marshal(coordinates)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(geoJSONGeometry{Type: typ, Coordinates: c})
+}
+
+// unmarshalGeoJSON decodes a GeoJSON geometry object. ok is false when b is
+// JSON null.
+func unmarshalGeoJSON(b []byte) (g geoJSONGeometry, ok bool, err error) {
+	var p *geoJSONGeometry
+	if err := json.Unmarshal(b, &p); err != nil {
+		return geoJSONGeometry{}, false, err
+	}
+	if p == nil {
+		return geoJSONGeometry{}, false, nil
+	}
+	return *p, true, nil
+}
+
+// unsupportedGeoJSON returns the error for a GeoJSON geometry type that
+// cannot be decoded into typeName.
+func unsupportedGeoJSON(typeName string, g geoJSONGeometry) error {
+	return errors.Errorf("cannot unmarshal GeoJSON %s into %s", g.Type, typeName)
+}
+
+// lsegDistance returns the distance from v to the line segment from a to b,
+// as the server's <-> operator for point and lseg does.
+func lsegDistance(a, b, v pgtype.Vec2) float64 {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	closest := a
+	if lengthSquared := dx*dx + dy*dy; lengthSquared != 0 {
+		t := ((v.X-a.X)*dx + (v.Y-a.Y)*dy) / lengthSquared
+		switch {
+		case t >= 1:
+			closest = b
+		case t > 0:
+			closest = pgtype.Vec2{X: a.X + t*dx, Y: a.Y + t*dy}
+		}
+	}
+	return vec2Distance(closest, v)
+}
+
+func vec2Distance(a, b pgtype.Vec2) float64 {
+	return math.Hypot(a.X-b.X, a.Y-b.Y)
+}
+
+// pointOnPolygon is returned by lsegCrossing for a point on the boundary.
+const pointOnPolygon = math.MaxInt32
+
+// pointInside reports whether v is outside (0), inside (1) or on the boundary
+// (2) of the polygon with the given vertices. It uses the same crossing count
+// and tolerance as the server's point_inside.
+func pointInside(v pgtype.Vec2, vertices []pgtype.Vec2) int {
+	if len(vertices) == 0 {
+		return 0
+	}
+
+	x0, y0 := vertices[0].X-v.X, vertices[0].Y-v.Y
+	prevX, prevY := x0, y0
+	totalCross := 0
+	for _, vertex := range vertices[1:] {
+		x, y := vertex.X-v.X, vertex.Y-v.Y
+		cross := lsegCrossing(x, y, prevX, prevY)
+		if cross == pointOnPolygon {
+			return 2
+		}
+		totalCross += cross
+		prevX, prevY = x, y
+	}
+
+	cross := lsegCrossing(x0, y0, prevX, prevY)
+	if cross == pointOnPolygon {
+		return 2
+	}
+	totalCross += cross
+
+	if totalCross != 0 {
+		return 1
+	}
+	return 0
+}
+
+// lsegCrossing returns how the segment from (prevX, prevY) to (x, y) crosses
+// the positive X axis: 0 for no crossing, ±1 for touching it and ±2 for
+// crossing it, with the sign of the direction. It returns pointOnPolygon when
+// the segment passes through the origin.
+func lsegCrossing(x, y, prevX, prevY float64) int {
+	if fpZero(y) {
+		switch {
+		case fpZero(x):
+			return pointOnPolygon
+		case fpGt(x, 0):
+			if fpZero(prevY) {
+				if fpGt(prevX, 0) {
+					return 0
+				}
+				return pointOnPolygon
+			}
+			if fpLt(prevY, 0) {
+				return 1
+			}
+			return -1
+		default:
+			if fpZero(prevY) {
+				if fpLt(prevX, 0) {
+					return 0
+				}
+				return pointOnPolygon
+			}
+			return 0
+		}
+	}
+
+	ySign := -1
+	if fpGt(y, 0) {
+		ySign = 1
+	}
+
+	switch {
+	case fpZero(prevY):
+		if fpLt(prevX, 0) {
+			return 0
+		}
+		return ySign
+	case ySign < 0 && fpLt(prevY, 0), ySign > 0 && fpGt(prevY, 0):
+		return 0
+	case fpGe(x, 0) && fpGt(prevX, 0):
+		return 2 * ySign
+	case fpLt(x, 0) && fpLe(prevX, 0):
+		return 0
+	}
+
+	z := (x-prevX)*y - (y-prevY)*x
+	if fpZero(z) {
+		return pointOnPolygon
+	}
+	if (ySign < 0 && fpLt(z, 0)) || (ySign > 0 && fpGt(z, 0)) {
+		return 0
+	}
+	return 2 * ySign
+}
+
+// polygonDistance returns the distance from v to the nearest edge of the
+// closed polygon with the given vertices, or 0 if v is inside it.
+func polygonDistance(v pgtype.Vec2, vertices []pgtype.Vec2) float64 {
+	if pointInside(v, vertices) != 0 {
+		return 0
+	}
+	return pathDistance(v, vertices, true)
+}
+
+// pathDistance returns the distance from v to the nearest segment of the path
+// through points, including the segment from the last point back to the first
+// when closed is set.
+func pathDistance(v pgtype.Vec2, points []pgtype.Vec2, closed bool) float64 {
+	switch len(points) {
+	case 0:
+		return math.NaN()
+	case 1:
+		return vec2Distance(points[0], v)
+	}
+
+	d := math.Inf(1)
+	for i := 1; i < len(points); i++ {
+		d = math.Min(d, lsegDistance(points[i-1], points[i], v))
+	}
+	if closed {
+		d = math.Min(d, lsegDistance(points[len(points)-1], points[0], v))
+	}
+	return d
+}
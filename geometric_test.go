@@ -0,0 +1,259 @@
+package tstype_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/jackc/pgtype"
+	"github.com/tossp/tstype"
+)
+
+type geometricValue interface {
+	pgtype.TextEncoder
+	pgtype.BinaryEncoder
+}
+
+type geometricDecoder interface {
+	pgtype.TextDecoder
+	pgtype.BinaryDecoder
+}
+
+func TestGeometricTextRoundTrip(t *testing.T) {
+	tests := []struct {
+		newValue func() geometricDecoder
+		src      string
+		expected string
+	}{
+		{func() geometricDecoder { return &tstype.Point{} }, "(1.5,-2)", "(1.5,-2)"},
+		{func() geometricDecoder { return &tstype.Point{} }, " 1 , 2 ", "(1,2)"},
+		{func() geometricDecoder { return &tstype.Point{} }, "(NaN,Infinity)", "(NaN,Infinity)"},
+		{func() geometricDecoder { return &tstype.Line{} }, "{1,-1,0}", "{1,-1,0}"},
+		{func() geometricDecoder { return &tstype.Line{} }, "[(0,0),(2,2)]", "{1,-1,0}"},
+		{func() geometricDecoder { return &tstype.Line{} }, "(1,0),(1,5)", "{-1,0,1}"},
+		{func() geometricDecoder { return &tstype.Lseg{} }, "[(1,2),(3,4)]", "[(1,2),(3,4)]"},
+		{func() geometricDecoder { return &tstype.Lseg{} }, "((1,2),(3,4))", "[(1,2),(3,4)]"},
+		{func() geometricDecoder { return &tstype.Lseg{} }, "1,2,3,4", "[(1,2),(3,4)]"},
+		{func() geometricDecoder { return &tstype.Box{} }, "(0,0),(2,3)", "(2,3),(0,0)"},
+		{func() geometricDecoder { return &tstype.Box{} }, "((2,0),(0,3))", "(2,3),(0,0)"},
+		{func() geometricDecoder { return &tstype.Path{} }, "[(0,0),(1,1),(2,0)]", "[(0,0),(1,1),(2,0)]"},
+		{func() geometricDecoder { return &tstype.Path{} }, "((0,0),(1,1),(2,0))", "((0,0),(1,1),(2,0))"},
+		{func() geometricDecoder { return &tstype.Path{} }, "(0,0),(1,1)", "((0,0),(1,1))"},
+		{func() geometricDecoder { return &tstype.Path{} }, "(0,0,1,1)", "((0,0),(1,1))"},
+		{func() geometricDecoder { return &tstype.Polygon{} }, "((0,0),(4,0),(4,4))", "((0,0),(4,0),(4,4))"},
+		{func() geometricDecoder { return &tstype.Polygon{} }, "0,0,4,0,4,4", "((0,0),(4,0),(4,4))"},
+		{func() geometricDecoder { return &tstype.Circle{} }, "<(1,2),3>", "<(1,2),3>"},
+		{func() geometricDecoder { return &tstype.Circle{} }, "((1,2),3)", "<(1,2),3>"},
+		{func() geometricDecoder { return &tstype.Circle{} }, "(1,2),3", "<(1,2),3>"},
+		{func() geometricDecoder { return &tstype.Circle{} }, "1,2,3", "<(1,2),3>"},
+	}
+
+	for i, tt := range tests {
+		dst := tt.newValue()
+		if err := dst.DecodeText(nil, []byte(tt.src)); err != nil {
+			t.Errorf("%d: %q: %v", i, tt.src, err)
+			continue
+		}
+		buf, err := dst.(geometricValue).EncodeText(nil, nil)
+		if err != nil || string(buf) != tt.expected {
+			t.Errorf("%d: %q: expected %q, got %q, %v", i, tt.src, tt.expected, buf, err)
+		}
+
+		bin, err := dst.(geometricValue).EncodeBinary(nil, nil)
+		if err != nil {
+			t.Errorf("%d: EncodeBinary: %v", i, err)
+			continue
+		}
+		again := tt.newValue()
+		if err := again.DecodeBinary(nil, bin); err != nil {
+			t.Errorf("%d: DecodeBinary: %v", i, err)
+			continue
+		}
+		if buf, _ := again.(geometricValue).EncodeText(nil, nil); string(buf) != tt.expected {
+			t.Errorf("%d: binary round trip: expected %q, got %q", i, tt.expected, buf)
+		}
+	}
+}
+
+func TestGeometricInvalidText(t *testing.T) {
+	tests := []struct {
+		dst geometricDecoder
+		src string
+	}{
+		{&tstype.Point{}, "(1,2"},
+		{&tstype.Point{}, "(1,2,3)"},
+		{&tstype.Line{}, "{0,0,1}"},
+		{&tstype.Line{}, "[(1,1),(1,1)]"},
+		{&tstype.Lseg{}, "[(1,2)]"},
+		{&tstype.Box{}, "[(0,0),(1,1)]"},
+		{&tstype.Path{}, "[(0,0),(1,1))"},
+		{&tstype.Polygon{}, "[(0,0),(1,1)]"},
+		{&tstype.Circle{}, "<(1,2),-1>"},
+		{&tstype.Circle{}, "<(1,2),3"},
+	}
+
+	for i, tt := range tests {
+		if err := tt.dst.DecodeText(nil, []byte(tt.src)); err == nil {
+			t.Errorf("%d: %q: expected error", i, tt.src)
+		}
+	}
+}
+
+func TestPathBinaryClosedFlag(t *testing.T) {
+	src := tstype.Path{P: []pgtype.Vec2{{X: 1, Y: 2}}, Closed: true, Status: tstype.Present}
+	buf, err := src.EncodeBinary(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(buf) != 1+4+16 || buf[0] != 1 {
+		t.Errorf("EncodeBinary: got %x", buf)
+	}
+
+	var dst tstype.Path
+	if err := dst.DecodeBinary(nil, buf[:len(buf)-1]); err == nil {
+		t.Error("DecodeBinary: expected error for truncated input")
+	}
+}
+
+func TestGeometricJSON(t *testing.T) {
+	tests := []struct {
+		src      interface{}
+		expected string
+	}{
+		{tstype.Point{P: pgtype.Vec2{X: 1, Y: 2}, Status: tstype.Present}, `{"type":"Point","coordinates":[1,2]}`},
+		{tstype.Lseg{P: [2]pgtype.Vec2{{X: 0, Y: 0}, {X: 1, Y: 1}}, Status: tstype.Present}, `{"type":"LineString","coordinates":[[0,0],[1,1]]}`},
+		{tstype.Path{P: []pgtype.Vec2{{X: 0, Y: 0}, {X: 1, Y: 1}}, Status: tstype.Present}, `{"type":"LineString","coordinates":[[0,0],[1,1]]}`},
+		{tstype.Path{P: []pgtype.Vec2{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}}, Closed: true, Status: tstype.Present}, `{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,0]]]}`},
+		{tstype.Polygon{P: []pgtype.Vec2{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}}, Status: tstype.Present}, `{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,0]]]}`},
+		{tstype.Box{P: [2]pgtype.Vec2{{X: 2, Y: 1}, {X: 0, Y: 0}}, Status: tstype.Present}, `{"type":"Polygon","coordinates":[[[0,0],[2,0],[2,1],[0,1],[0,0]]]}`},
+		{tstype.Circle{P: pgtype.Vec2{X: 1, Y: 2}, R: 3, Status: tstype.Present}, `"\u003c(1,2),3\u003e"`},
+		{tstype.Line{A: 1, B: -1, C: 0, Status: tstype.Present}, `"{1,-1,0}"`},
+		{tstype.Point{P: pgtype.Vec2{X: math.Inf(1), Y: 0}, Status: tstype.Present}, `{"type":"Point","coordinates":["Infinity",0]}`},
+		{tstype.Polygon{Status: tstype.Null}, `null`},
+	}
+
+	for i, tt := range tests {
+		buf, err := json.Marshal(tt.src)
+		if err != nil || string(buf) != tt.expected {
+			t.Errorf("%d: expected %s, got %s, %v", i, tt.expected, buf, err)
+		}
+	}
+
+	var polygon tstype.Polygon
+	if err := json.Unmarshal([]byte(`{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,0]]]}`), &polygon); err != nil || len(polygon.P) != 3 {
+		t.Errorf("Polygon: got %+v, %v", polygon, err)
+	}
+	if err := json.Unmarshal([]byte(`{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1]],[[0,0],[1,0],[1,1]]]}`), &polygon); err == nil {
+		t.Error("Polygon: expected error for holes")
+	}
+
+	var path tstype.Path
+	if err := json.Unmarshal([]byte(`{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,0]]]}`), &path); err != nil || !path.Closed || len(path.P) != 3 {
+		t.Errorf("Path: got %+v, %v", path, err)
+	}
+
+	var box tstype.Box
+	if err := json.Unmarshal([]byte(`{"type":"Polygon","coordinates":[[[3,1],[0,4],[1,0],[3,1]]]}`), &box); err != nil || box.P != [2]pgtype.Vec2{{X: 3, Y: 4}, {X: 0, Y: 0}} {
+		t.Errorf("Box: got %+v, %v", box, err)
+	}
+
+	var point tstype.Point
+	if err := json.Unmarshal([]byte(`{"type":"LineString","coordinates":[[0,0],[1,1]]}`), &point); err == nil {
+		t.Error("Point: expected error for LineString")
+	}
+	if err := json.Unmarshal([]byte(`null`), &point); err != nil || point.Status != tstype.Null {
+		t.Errorf("Point: got %+v, %v", point, err)
+	}
+}
+
+func TestGeometricOperators(t *testing.T) {
+	pt := func(x, y float64) tstype.Point {
+		return tstype.Point{P: pgtype.Vec2{X: x, Y: y}, Status: tstype.Present}
+	}
+
+	square := tstype.Polygon{P: []pgtype.Vec2{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4}}, Status: tstype.Present}
+	concave := tstype.Polygon{P: []pgtype.Vec2{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 2, Y: 1}, {X: 0, Y: 4}}, Status: tstype.Present}
+	containsTests := []struct {
+		polygon  tstype.Polygon
+		p        tstype.Point
+		expected bool
+	}{
+		{square, pt(2, 2), true},
+		{square, pt(0, 2), true},
+		{square, pt(4, 4), true},
+		{square, pt(4.0000001, 2), true},
+		{square, pt(5, 2), false},
+		{square, pt(-1, 0), false},
+		{concave, pt(2, 3), false},
+		{concave, pt(1, 1), true},
+		{concave, pt(3.5, 3), true},
+	}
+	for i, tt := range containsTests {
+		if got := tt.polygon.ContainsPoint(tt.p); got != tt.expected {
+			t.Errorf("%d: Polygon.ContainsPoint(%v): expected %v", i, tt.p.P, tt.expected)
+		}
+	}
+
+	if d := square.Distance(pt(7, 8)); d != 5 {
+		t.Errorf("Polygon.Distance: got %v", d)
+	}
+	if d := square.Distance(pt(1, 1)); d != 0 {
+		t.Errorf("Polygon.Distance inside: got %v", d)
+	}
+
+	box := tstype.Box{P: [2]pgtype.Vec2{{X: 0, Y: 0}, {X: 2, Y: 2}}, Status: tstype.Present}
+	if !box.ContainsPoint(pt(2, 1)) || box.ContainsPoint(pt(2.0000001, 1)) {
+		t.Error("Box.ContainsPoint")
+	}
+	inner := tstype.Box{P: [2]pgtype.Vec2{{X: 2.0000001, Y: 1}, {X: 1, Y: 0}}, Status: tstype.Present}
+	if !box.ContainsBox(inner) || inner.ContainsBox(box) {
+		t.Error("Box.ContainsBox")
+	}
+	if !box.Overlaps(inner) || box.Overlaps(tstype.Box{P: [2]pgtype.Vec2{{X: 3, Y: 3}, {X: 4, Y: 4}}, Status: tstype.Present}) {
+		t.Error("Box.Overlaps")
+	}
+	if d := box.Distance(pt(5, 6)); d != 5 {
+		t.Errorf("Box.Distance: got %v", d)
+	}
+
+	if d := pt(0, 0).Distance(pt(3, 4)); d != 5 {
+		t.Errorf("Point.Distance: got %v", d)
+	}
+	if d := pt(0, 0).Distance(tstype.Point{Status: tstype.Null}); !math.IsNaN(d) {
+		t.Errorf("Point.Distance Null: got %v", d)
+	}
+
+	lseg := tstype.Lseg{P: [2]pgtype.Vec2{{X: 0, Y: 0}, {X: 4, Y: 0}}, Status: tstype.Present}
+	if d := lseg.Distance(pt(2, 3)); d != 3 {
+		t.Errorf("Lseg.Distance: got %v", d)
+	}
+	if d := lseg.Distance(pt(7, 4)); d != 5 {
+		t.Errorf("Lseg.Distance past the end: got %v", d)
+	}
+
+	line := tstype.Line{A: 0, B: -1, C: 1, Status: tstype.Present}
+	if d := line.Distance(pt(5, 4)); d != 3 {
+		t.Errorf("Line.Distance: got %v", d)
+	}
+
+	open := tstype.Path{P: []pgtype.Vec2{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}}, Status: tstype.Present}
+	closed := open
+	closed.Closed = true
+	if d := open.Distance(pt(1, 2)); d != 2 {
+		t.Errorf("open Path.Distance: got %v", d)
+	}
+	if d := closed.Distance(pt(1, 2)); math.Abs(d-math.Sqrt2/2) > 1e-9 {
+		t.Errorf("closed Path.Distance: got %v", d)
+	}
+
+	circle := tstype.Circle{P: pgtype.Vec2{X: 0, Y: 0}, R: 5, Status: tstype.Present}
+	if !circle.ContainsPoint(pt(3, 4)) || circle.ContainsPoint(pt(4, 4)) {
+		t.Error("Circle.ContainsPoint")
+	}
+	if d := circle.Distance(pt(6, 8)); d != 5 {
+		t.Errorf("Circle.Distance: got %v", d)
+	}
+	if d := circle.Distance(pt(1, 1)); d != 0 {
+		t.Errorf("Circle.Distance inside: got %v", d)
+	}
+}
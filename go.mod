@@ -1,12 +1,32 @@
 module github.com/tossp/tstype
 
-go 1.15
+// net/netip, which Inet and Cidr are built on, was added in Go 1.18.
+go 1.18
 
 require (
 	github.com/gofrs/uuid v3.3.0+incompatible
 	github.com/jackc/pgio v1.0.0
 	github.com/jackc/pgtype v1.6.1
 	github.com/shopspring/decimal v1.2.0
+	// Used by decimal_test.go, along with github.com/jackc/pgtype/testutil
+	// whose dependencies make up the indirect requirements below.
+	github.com/stretchr/testify v1.5.1
 	golang.org/x/xerrors v0.0.0-20200804184101-5ec99f83aff1
 )
-replace github.com/jackc/pgtype v1.6.1 => github.com/tossp/pgtype v1.6.2-0.20201126104256-ff11ce768d3d
\ No newline at end of file
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/jackc/chunkreader/v2 v2.0.1 // indirect
+	github.com/jackc/pgconn v1.5.1-0.20200601181101-fa742c524853 // indirect
+	github.com/jackc/pgpassfile v1.0.0 // indirect
+	github.com/jackc/pgproto3/v2 v2.0.1 // indirect
+	github.com/jackc/pgservicefile v0.0.0-20200307190119-3430c5407db8 // indirect
+	github.com/jackc/pgx/v4 v4.6.1-0.20200606145419-4e5062306904 // indirect
+	github.com/lib/pq v1.3.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/crypto v0.0.0-20200323165209-0ec3e9974c59 // indirect
+	golang.org/x/text v0.3.2 // indirect
+	gopkg.in/yaml.v2 v2.2.2 // indirect
+)
+
+replace github.com/jackc/pgtype v1.6.1 => github.com/tossp/pgtype v1.6.2-0.20201126104256-ff11ce768d3d
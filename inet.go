@@ -0,0 +1,388 @@
+package tstype
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"net"
+	"net/netip"
+	"strings"
+
+	"github.com/jackc/pgtype"
+
+	errors "golang.org/x/xerrors"
+)
+
+// Network address family bytes of the inet and cidr binary format. These are
+// the server's PGSQL_AF_INET and PGSQL_AF_INET6, not the platform's AF_INET6.
+const (
+	pgAFInet  = 2
+	pgAFInet6 = 3
+)
+
+// Inet represents the PostgreSQL inet type. Unlike Cidr, the address may have
+// bits set to the right of the netmask, e.g. 192.168.1.5/24. Prefix is not
+// masked.
+type Inet struct {
+	Prefix netip.Prefix
+	Status Status
+}
+
+func (dst *Inet) Set(src interface{}) error {
+	if src == nil {
+		*dst = Inet{Status: Null}
+		return nil
+	}
+
+	if value, ok := src.(interface{ Get() interface{} }); ok {
+		value2 := value.Get()
+		if value2 != value {
+			return dst.Set(value2)
+		}
+	}
+
+	prefix, ok, err := prefixFromValue(src)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		*dst = Inet{Status: Null}
+		return nil
+	}
+
+	*dst = Inet{Prefix: prefix, Status: Present}
+	return nil
+}
+
+func (dst Inet) Get() interface{} {
+	switch dst.Status {
+	case Present:
+		return dst.Prefix
+	case Null:
+		return nil
+	default:
+		return dst.Status
+	}
+}
+
+// AssignTo assigns src to dst. net.IP and netip.Addr destinations require the
+// netmask to cover the whole address, so that no information is lost.
+func (src *Inet) AssignTo(dst interface{}) error {
+	switch src.Status {
+	case Present:
+		return assignPrefixTo(src.Prefix, dst, false)
+	case Null:
+		return NullAssignTo(dst)
+	}
+
+	return errors.Errorf("cannot decode %#v into %T", src, dst)
+}
+
+func (dst *Inet) DecodeText(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Inet{Status: Null}
+		return nil
+	}
+
+	prefix, err := parsePrefix(string(src))
+	if err != nil {
+		return err
+	}
+
+	*dst = Inet{Prefix: prefix, Status: Present}
+	return nil
+}
+
+func (dst *Inet) DecodeBinary(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Inet{Status: Null}
+		return nil
+	}
+
+	prefix, _, err := decodeInetBinary(src)
+	if err != nil {
+		return err
+	}
+
+	*dst = Inet{Prefix: prefix, Status: Present}
+	return nil
+}
+
+// EncodeText omits the netmask when it covers the whole address, as the server
+// does for inet.
+func (src Inet) EncodeText(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	if src.Prefix.IsSingleIP() {
+		return append(buf, src.Prefix.Addr().String()...), nil
+	}
+	return append(buf, src.Prefix.String()...), nil
+}
+
+func (src Inet) EncodeBinary(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	return appendInetBinary(buf, src.Prefix, false)
+}
+
+// Scan implements the database/sql Scanner interface.
+func (dst *Inet) Scan(src interface{}) error {
+	if src == nil {
+		*dst = Inet{Status: Null}
+		return nil
+	}
+
+	switch src := src.(type) {
+	case string:
+		return dst.DecodeText(nil, []byte(src))
+	case []byte:
+		srcCopy := make([]byte, len(src))
+		copy(srcCopy, src)
+		return dst.DecodeText(nil, srcCopy)
+	}
+
+	return errors.Errorf("cannot scan %T", src)
+}
+
+// Value implements the database/sql/driver Valuer interface.
+func (src Inet) Value() (driver.Value, error) {
+	return EncodeValueText(src)
+}
+
+// MarshalJSON encodes src in CIDR notation, e.g. "192.168.1.5/24". The netmask
+// is always included.
+func (src Inet) MarshalJSON() ([]byte, error) {
+	switch src.Status {
+	case Present:
+		return json.Marshal(src.Prefix.String())
+	case Null:
+		return []byte("null"), nil
+	}
+
+	return nil, errBadStatus
+}
+
+func (dst *Inet) UnmarshalJSON(b []byte) error {
+	var s *string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		return err
+	}
+
+	if s == nil {
+		*dst = Inet{Status: Null}
+		return nil
+	}
+
+	return dst.DecodeText(nil, []byte(*s))
+}
+
+// parsePrefix parses an address with an optional netmask. Without a netmask the
+// prefix covers the whole address.
+func parsePrefix(s string) (netip.Prefix, error) {
+	if strings.IndexByte(s, '/') >= 0 {
+		return netip.ParsePrefix(s)
+	}
+
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// prefixFromValue converts the values accepted by Inet.Set and Cidr.Set. The
+// returned bool is false when src is a nil pointer.
+func prefixFromValue(src interface{}) (netip.Prefix, bool, error) {
+	switch value := src.(type) {
+	case netip.Prefix:
+		if !value.IsValid() {
+			return netip.Prefix{}, false, errors.Errorf("cannot convert invalid prefix %v", value)
+		}
+		return value, true, nil
+	case netip.Addr:
+		if !value.IsValid() {
+			return netip.Prefix{}, false, errors.Errorf("cannot convert invalid address %v", value)
+		}
+		return netip.PrefixFrom(value, value.BitLen()), true, nil
+	case net.IPNet:
+		addr, ok := addrFromIP(value.IP)
+		if !ok {
+			return netip.Prefix{}, false, errors.Errorf("cannot convert %v", value)
+		}
+		ones, bits := value.Mask.Size()
+		if bits != addr.BitLen() {
+			return netip.Prefix{}, false, errors.Errorf("cannot convert %v, netmask does not match address", value.String())
+		}
+		return netip.PrefixFrom(addr, ones), true, nil
+	case net.IP:
+		if value == nil {
+			return netip.Prefix{}, false, nil
+		}
+		addr, ok := addrFromIP(value)
+		if !ok {
+			return netip.Prefix{}, false, errors.Errorf("cannot convert %v", value)
+		}
+		return netip.PrefixFrom(addr, addr.BitLen()), true, nil
+	case string:
+		prefix, err := parsePrefix(value)
+		if err != nil {
+			return netip.Prefix{}, false, err
+		}
+		return prefix, true, nil
+	case *net.IPNet:
+		if value == nil {
+			return netip.Prefix{}, false, nil
+		}
+		return prefixFromValue(*value)
+	case *netip.Prefix:
+		if value == nil {
+			return netip.Prefix{}, false, nil
+		}
+		return prefixFromValue(*value)
+	case *netip.Addr:
+		if value == nil {
+			return netip.Prefix{}, false, nil
+		}
+		return prefixFromValue(*value)
+	case *net.IP:
+		if value == nil {
+			return netip.Prefix{}, false, nil
+		}
+		return prefixFromValue(*value)
+	case *string:
+		if value == nil {
+			return netip.Prefix{}, false, nil
+		}
+		return prefixFromValue(*value)
+	default:
+		if originalSrc, ok := underlyingPtrType(src); ok {
+			return prefixFromValue(originalSrc)
+		}
+		return netip.Prefix{}, false, errors.Errorf("cannot convert %v to a network address", value)
+	}
+}
+
+// addrFromIP converts ip, unmapping IPv4 addresses that net.IP keeps in their
+// 16 byte form.
+func addrFromIP(ip net.IP) (netip.Addr, bool) {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	return netip.AddrFromSlice(ip)
+}
+
+func assignPrefixTo(prefix netip.Prefix, dst interface{}, isCidr bool) error {
+	switch v := dst.(type) {
+	case *netip.Prefix:
+		*v = prefix
+		return nil
+	case *netip.Addr:
+		if !prefix.IsSingleIP() {
+			return errors.Errorf("cannot assign %v to %T", prefix, dst)
+		}
+		*v = prefix.Addr()
+		return nil
+	case *net.IPNet:
+		*v = net.IPNet{
+			IP:   net.IP(prefix.Addr().AsSlice()),
+			Mask: net.CIDRMask(prefix.Bits(), prefix.Addr().BitLen()),
+		}
+		return nil
+	case *net.IP:
+		if !prefix.IsSingleIP() {
+			return errors.Errorf("cannot assign %v to %T", prefix, dst)
+		}
+		*v = net.IP(prefix.Addr().AsSlice())
+		return nil
+	case *string:
+		if prefix.IsSingleIP() && !isCidr {
+			*v = prefix.Addr().String()
+		} else {
+			*v = prefix.String()
+		}
+		return nil
+	default:
+		if nextDst, retry := GetAssignToDstType(dst); retry {
+			return assignPrefixTo(prefix, nextDst, isCidr)
+		}
+		return errors.Errorf("unable to assign to %T", dst)
+	}
+}
+
+// decodeInetBinary decodes the family, bits, is_cidr, address length and
+// address layout shared by inet and cidr.
+func decodeInetBinary(src []byte) (netip.Prefix, bool, error) {
+	if len(src) != 8 && len(src) != 20 {
+		return netip.Prefix{}, false, errors.Errorf("Received an invalid size for a inet: %d", len(src))
+	}
+
+	family := src[0]
+	bits := int(src[1])
+	isCidr := src[2] == 1
+	addrLen := int(src[3])
+
+	if addrLen != len(src)-4 {
+		return netip.Prefix{}, false, errors.Errorf("invalid address length for inet: %d", addrLen)
+	}
+
+	var addr netip.Addr
+	switch family {
+	case pgAFInet:
+		if addrLen != 4 {
+			return netip.Prefix{}, false, errors.Errorf("invalid address length for IPv4 inet: %d", addrLen)
+		}
+		addr = netip.AddrFrom4([4]byte{src[4], src[5], src[6], src[7]})
+	case pgAFInet6:
+		if addrLen != 16 {
+			return netip.Prefix{}, false, errors.Errorf("invalid address length for IPv6 inet: %d", addrLen)
+		}
+		var a [16]byte
+		copy(a[:], src[4:])
+		addr = netip.AddrFrom16(a)
+	default:
+		return netip.Prefix{}, false, errors.Errorf("unknown inet address family %d", family)
+	}
+
+	if bits > addr.BitLen() {
+		return netip.Prefix{}, false, errors.Errorf("invalid netmask length for inet: %d", bits)
+	}
+
+	return netip.PrefixFrom(addr, bits), isCidr, nil
+}
+
+func appendInetBinary(buf []byte, prefix netip.Prefix, isCidr bool) ([]byte, error) {
+	if !prefix.IsValid() {
+		return nil, errors.Errorf("cannot encode invalid prefix %v", prefix)
+	}
+
+	addr := prefix.Addr()
+	if addr.Is4() {
+		buf = append(buf, pgAFInet)
+	} else {
+		buf = append(buf, pgAFInet6)
+	}
+
+	buf = append(buf, byte(prefix.Bits()))
+
+	if isCidr {
+		buf = append(buf, 1)
+	} else {
+		buf = append(buf, 0)
+	}
+
+	raw := addr.AsSlice()
+	buf = append(buf, byte(len(raw)))
+	return append(buf, raw...), nil
+}
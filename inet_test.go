@@ -0,0 +1,241 @@
+package tstype_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/tossp/tstype"
+)
+
+func TestInetText(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected string
+	}{
+		{"192.168.1.5/24", "192.168.1.5/24"},
+		{"192.168.1.5/32", "192.168.1.5"},
+		{"192.168.1.5", "192.168.1.5"},
+		{"2001:db8::1/64", "2001:db8::1/64"},
+		{"::1", "::1"},
+	}
+
+	for i, tt := range tests {
+		var inet tstype.Inet
+		if err := inet.DecodeText(nil, []byte(tt.src)); err != nil {
+			t.Errorf("%d: %v", i, err)
+			continue
+		}
+		if buf, err := inet.EncodeText(nil, nil); err != nil || string(buf) != tt.expected {
+			t.Errorf("%d: %q: expected %q, got %q, %v", i, tt.src, tt.expected, buf, err)
+		}
+	}
+
+	for i, src := range []string{"", "192.168.1.256", "192.168.1.5/33", "::1/129", "host"} {
+		var inet tstype.Inet
+		if err := inet.DecodeText(nil, []byte(src)); err == nil {
+			t.Errorf("%d: %q: expected error, got %v", i, src, inet)
+		}
+	}
+}
+
+func TestInetBinary(t *testing.T) {
+	tests := []struct {
+		src      string
+		isCidr   bool
+		expected []byte
+	}{
+		{"192.168.1.5/24", false, []byte{2, 24, 0, 4, 192, 168, 1, 5}},
+		{"10.0.0.1/32", false, []byte{2, 32, 0, 4, 10, 0, 0, 1}},
+		{"10.0.0.0/8", true, []byte{2, 8, 1, 4, 10, 0, 0, 0}},
+		{"2001:db8::1/64", false, []byte{3, 64, 0, 16, 0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1}},
+		{"2001:db8::/32", true, []byte{3, 32, 1, 16, 0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}},
+	}
+
+	for i, tt := range tests {
+		prefix := netip.MustParsePrefix(tt.src)
+
+		var buf []byte
+		var err error
+		if tt.isCidr {
+			buf, err = tstype.Cidr{Prefix: prefix, Status: tstype.Present}.EncodeBinary(nil, nil)
+		} else {
+			buf, err = tstype.Inet{Prefix: prefix, Status: tstype.Present}.EncodeBinary(nil, nil)
+		}
+		if err != nil || !bytes.Equal(buf, tt.expected) {
+			t.Errorf("%d: %q: EncodeBinary: expected %v, got %v, %v", i, tt.src, tt.expected, buf, err)
+		}
+
+		var inet tstype.Inet
+		if err := inet.DecodeBinary(nil, tt.expected); err != nil || inet.Prefix != prefix {
+			t.Errorf("%d: %q: DecodeBinary: got %v, %v", i, tt.src, inet.Prefix, err)
+		}
+	}
+
+	for i, src := range [][]byte{
+		{2, 24, 0, 4, 192, 168, 1},
+		{2, 33, 0, 4, 192, 168, 1, 5},
+		{4, 24, 0, 4, 192, 168, 1, 5},
+		{3, 24, 0, 4, 192, 168, 1, 5},
+		{2, 24, 0, 16, 192, 168, 1, 5},
+	} {
+		var inet tstype.Inet
+		if err := inet.DecodeBinary(nil, src); err == nil {
+			t.Errorf("%d: %v: expected error, got %v", i, src, inet)
+		}
+	}
+}
+
+func TestCidrRejectsHostBits(t *testing.T) {
+	original := tstype.Cidr{Prefix: netip.MustParsePrefix("172.16.0.0/12"), Status: tstype.Present}
+	cidr := original
+	if err := cidr.DecodeText(nil, []byte("192.168.1.5/24")); err == nil {
+		t.Error("DecodeText: expected error")
+	}
+	if err := cidr.DecodeBinary(nil, []byte{2, 24, 1, 4, 192, 168, 1, 5}); err == nil {
+		t.Error("DecodeBinary: expected error")
+	}
+	if err := cidr.Set("2001:db8::1/32"); err == nil {
+		t.Error("Set: expected error")
+	}
+	if err := json.Unmarshal([]byte(`"10.1.0.0/8"`), &cidr); err == nil {
+		t.Error("UnmarshalJSON: expected error")
+	}
+	if err := cidr.Scan("10.1.0.0/8"); err == nil {
+		t.Error("Scan: expected error")
+	}
+	if cidr != original {
+		t.Errorf("failed decodes changed the value to %v", cidr)
+	}
+
+	bad := tstype.Cidr{Prefix: netip.MustParsePrefix("10.1.0.0/8"), Status: tstype.Present}
+	if _, err := bad.EncodeText(nil, nil); err == nil {
+		t.Error("EncodeText: expected error")
+	}
+	if _, err := bad.EncodeBinary(nil, nil); err == nil {
+		t.Error("EncodeBinary: expected error")
+	}
+
+	if err := cidr.Set("192.168.1.0/24"); err != nil {
+		t.Fatal(err)
+	}
+	if buf, err := cidr.EncodeText(nil, nil); err != nil || string(buf) != "192.168.1.0/24" {
+		t.Errorf("EncodeText: got %q, %v", buf, err)
+	}
+	if err := cidr.Set("10.0.0.1"); err != nil {
+		t.Fatal(err)
+	}
+	if buf, err := cidr.EncodeText(nil, nil); err != nil || string(buf) != "10.0.0.1/32" {
+		t.Errorf("EncodeText single address: got %q, %v", buf, err)
+	}
+}
+
+func TestInetSet(t *testing.T) {
+	_, ipNet, _ := net.ParseCIDR("192.168.0.0/16")
+	tests := []struct {
+		src      interface{}
+		expected netip.Prefix
+	}{
+		{net.ParseIP("192.168.1.5"), netip.MustParsePrefix("192.168.1.5/32")},
+		{net.ParseIP("2001:db8::1"), netip.MustParsePrefix("2001:db8::1/128")},
+		{ipNet, netip.MustParsePrefix("192.168.0.0/16")},
+		{*ipNet, netip.MustParsePrefix("192.168.0.0/16")},
+		{netip.MustParseAddr("10.0.0.1"), netip.MustParsePrefix("10.0.0.1/32")},
+		{netip.MustParsePrefix("10.0.0.1/8"), netip.MustParsePrefix("10.0.0.1/8")},
+		{"10.0.0.1/8", netip.MustParsePrefix("10.0.0.1/8")},
+	}
+
+	for i, tt := range tests {
+		var inet tstype.Inet
+		if err := inet.Set(tt.src); err != nil || inet.Status != tstype.Present || inet.Prefix != tt.expected {
+			t.Errorf("%d: %v: expected %v, got %v, %v", i, tt.src, tt.expected, inet.Prefix, err)
+		}
+	}
+
+	var inet tstype.Inet
+	for i, src := range []interface{}{(*net.IPNet)(nil), net.IP(nil), (*netip.Prefix)(nil), (*string)(nil)} {
+		if err := inet.Set(src); err != nil || inet.Status != tstype.Null {
+			t.Errorf("%d: %T: expected Null, got %v, %v", i, src, inet, err)
+		}
+	}
+	for i, src := range []interface{}{netip.Addr{}, netip.Prefix{}, net.IP{1, 2, 3}, 42} {
+		if err := inet.Set(src); err == nil {
+			t.Errorf("%d: %#v: expected error", i, src)
+		}
+	}
+}
+
+func TestInetAssignTo(t *testing.T) {
+	host := tstype.Inet{Prefix: netip.MustParsePrefix("192.168.1.5/32"), Status: tstype.Present}
+	network := tstype.Inet{Prefix: netip.MustParsePrefix("192.168.1.5/24"), Status: tstype.Present}
+
+	var ip net.IP
+	if err := host.AssignTo(&ip); err != nil || !ip.Equal(net.ParseIP("192.168.1.5")) {
+		t.Errorf("AssignTo net.IP: got %v, %v", ip, err)
+	}
+	if err := network.AssignTo(&ip); err == nil {
+		t.Error("AssignTo net.IP with netmask: expected error")
+	}
+
+	var addr netip.Addr
+	if err := host.AssignTo(&addr); err != nil || addr != netip.MustParseAddr("192.168.1.5") {
+		t.Errorf("AssignTo netip.Addr: got %v, %v", addr, err)
+	}
+	if err := network.AssignTo(&addr); err == nil {
+		t.Error("AssignTo netip.Addr with netmask: expected error")
+	}
+
+	var ipNet net.IPNet
+	if err := network.AssignTo(&ipNet); err != nil || ipNet.String() != "192.168.1.5/24" {
+		t.Errorf("AssignTo net.IPNet: got %v, %v", ipNet.String(), err)
+	}
+	var pIPNet *net.IPNet
+	if err := network.AssignTo(&pIPNet); err != nil || pIPNet == nil || pIPNet.String() != "192.168.1.5/24" {
+		t.Errorf("AssignTo *net.IPNet: got %v, %v", pIPNet, err)
+	}
+
+	var prefix netip.Prefix
+	if err := network.AssignTo(&prefix); err != nil || prefix != network.Prefix {
+		t.Errorf("AssignTo netip.Prefix: got %v, %v", prefix, err)
+	}
+
+	var s string
+	if err := host.AssignTo(&s); err != nil || s != "192.168.1.5" {
+		t.Errorf("AssignTo string: got %q, %v", s, err)
+	}
+	cidr := tstype.Cidr{Prefix: netip.MustParsePrefix("10.0.0.1/32"), Status: tstype.Present}
+	if err := cidr.AssignTo(&s); err != nil || s != "10.0.0.1/32" {
+		t.Errorf("Cidr AssignTo string: got %q, %v", s, err)
+	}
+
+	null := tstype.Inet{Status: tstype.Null}
+	if err := null.AssignTo(&pIPNet); err != nil || pIPNet != nil {
+		t.Errorf("AssignTo *net.IPNet from Null: got %v, %v", pIPNet, err)
+	}
+}
+
+func TestInetJSON(t *testing.T) {
+	host := tstype.Inet{Prefix: netip.MustParsePrefix("192.168.1.5/32"), Status: tstype.Present}
+	js, err := json.Marshal(host)
+	if err != nil || string(js) != `"192.168.1.5/32"` {
+		t.Errorf("MarshalJSON: got %s, %v", js, err)
+	}
+
+	var back tstype.Inet
+	if err := json.Unmarshal(js, &back); err != nil || back != host {
+		t.Errorf("UnmarshalJSON: got %v, %v", back, err)
+	}
+	if err := json.Unmarshal([]byte("null"), &back); err != nil || back.Status != tstype.Null {
+		t.Errorf("UnmarshalJSON null: got %v, %v", back, err)
+	}
+
+	bad := tstype.Inet{Prefix: host.Prefix, Status: tstype.Status(2)}
+	if _, err := bad.EncodeText(nil, nil); err == nil {
+		t.Error("EncodeText invalid status: expected error")
+	}
+	if _, err := bad.EncodeBinary(nil, nil); err == nil {
+		t.Error("EncodeBinary invalid status: expected error")
+	}
+}
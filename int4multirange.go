@@ -0,0 +1,202 @@
+package tstype
+
+import (
+	"database/sql/driver"
+
+	"github.com/jackc/pgtype"
+
+	errors "golang.org/x/xerrors"
+)
+
+// Int4multirange represents the PostgreSQL int4multirange type, available since
+// PostgreSQL 14. Ranges are kept in the order they were set or decoded; use
+// Canonical to sort and merge them as the server does.
+type Int4multirange struct {
+	Ranges []Int4range
+	Status Status
+}
+
+func (dst *Int4multirange) Set(src interface{}) error {
+	if src == nil {
+		*dst = Int4multirange{Status: Null}
+		return nil
+	}
+
+	switch value := src.(type) {
+	case Int4multirange:
+		*dst = value
+	case *Int4multirange:
+		if value == nil {
+			*dst = Int4multirange{Status: Null}
+		} else {
+			*dst = *value
+		}
+	case []Int4range:
+		if value == nil {
+			*dst = Int4multirange{Status: Null}
+		} else {
+			*dst = Int4multirange{Ranges: value, Status: Present}
+		}
+	case string:
+		return dst.DecodeText(nil, []byte(value))
+	case *string:
+		if value == nil {
+			*dst = Int4multirange{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	default:
+		return errors.Errorf("cannot convert %v to Int4multirange", src)
+	}
+
+	return nil
+}
+
+func (dst Int4multirange) Get() interface{} {
+	switch dst.Status {
+	case Present:
+		return dst
+	case Null:
+		return nil
+	default:
+		return dst.Status
+	}
+}
+
+func (src *Int4multirange) AssignTo(dst interface{}) error {
+	switch src.Status {
+	case Present:
+		switch v := dst.(type) {
+		case *Int4multirange:
+			*v = *src
+			return nil
+		case *[]Int4range:
+			*v = make([]Int4range, len(src.Ranges))
+			copy(*v, src.Ranges)
+			return nil
+		case *string:
+			buf, err := src.EncodeText(nil, nil)
+			if err != nil {
+				return err
+			}
+			*v = string(buf)
+			return nil
+		default:
+			if nextDst, retry := GetAssignToDstType(dst); retry {
+				return src.AssignTo(nextDst)
+			}
+			return errors.Errorf("unable to assign to %T", dst)
+		}
+	case Null:
+		return NullAssignTo(dst)
+	}
+
+	return errors.Errorf("cannot decode %#v into %T", src, dst)
+}
+
+func (dst *Int4multirange) DecodeText(ci *pgtype.ConnInfo, src []byte) error {
+	m, err := decodeMultirangeText[Int4range, Int4](ci, src)
+	if err != nil {
+		return err
+	}
+
+	*dst = Int4multirange(m)
+	return nil
+}
+
+func (dst *Int4multirange) DecodeBinary(ci *pgtype.ConnInfo, src []byte) error {
+	m, err := decodeMultirangeBinary[Int4range, Int4](ci, src)
+	if err != nil {
+		return err
+	}
+
+	*dst = Int4multirange(m)
+	return nil
+}
+
+func (src Int4multirange) EncodeText(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	return encodeMultirangeText(ci, buf, multirangeValue[Int4range](src))
+}
+
+func (src Int4multirange) EncodeBinary(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	return encodeMultirangeBinary(ci, buf, multirangeValue[Int4range](src))
+}
+
+// Scan implements the database/sql Scanner interface.
+func (dst *Int4multirange) Scan(src interface{}) error {
+	if src == nil {
+		*dst = Int4multirange{Status: Null}
+		return nil
+	}
+
+	switch src := src.(type) {
+	case string:
+		return dst.DecodeText(nil, []byte(src))
+	case []byte:
+		srcCopy := make([]byte, len(src))
+		copy(srcCopy, src)
+		return dst.DecodeText(nil, srcCopy)
+	}
+
+	return errors.Errorf("cannot scan %T", src)
+}
+
+// Value implements the database/sql/driver Valuer interface.
+func (src Int4multirange) Value() (driver.Value, error) {
+	return EncodeValueText(src)
+}
+
+// MarshalJSON encodes src as a JSON array of its ranges, each in the form
+// written by Int4range.MarshalJSON.
+func (src Int4multirange) MarshalJSON() ([]byte, error) {
+	return marshalMultirangeJSON(multirangeValue[Int4range](src))
+}
+
+func (dst *Int4multirange) UnmarshalJSON(b []byte) error {
+	m, err := unmarshalMultirangeJSON[Int4range, Int4](b)
+	if err != nil {
+		return err
+	}
+
+	*dst = Int4multirange(m)
+	return nil
+}
+
+// Canonical returns src in the form the server stores it. Each range is
+// canonicalized, empty ranges are dropped, and the rest are sorted with
+// overlapping and adjacent ranges merged, so {[5,7),[1,3),[2,5)} becomes
+// {[1,7)}. It returns an error for a range the server would reject.
+func (src Int4multirange) Canonical() (Int4multirange, error) {
+	m, err := canonicalMultirange(int4RangeSubtype, multirangeValue[Int4range](src), true)
+	if err != nil {
+		return Int4multirange{}, err
+	}
+	return Int4multirange(m), nil
+}
+
+// IsEmpty reports whether src contains no values.
+func (src Int4multirange) IsEmpty() bool {
+	if src.Status != Present {
+		return false
+	}
+	for _, r := range src.Ranges {
+		if !r.IsEmpty() {
+			return false
+		}
+	}
+	return true
+}
+
+// Contains reports whether v is in one of the ranges of src, as the server's @>
+// operator does.
+func (src Int4multirange) Contains(v int32) bool {
+	if src.Status != Present {
+		return false
+	}
+	for _, r := range src.Ranges {
+		if r.Contains(v) {
+			return true
+		}
+	}
+	return false
+}
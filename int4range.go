@@ -0,0 +1,216 @@
+package tstype
+
+import (
+	"database/sql/driver"
+	"math"
+
+	"github.com/jackc/pgtype"
+
+	errors "golang.org/x/xerrors"
+)
+
+// Int4range represents the PostgreSQL int4range type. Lower and Upper are only
+// used when LowerType and UpperType are pgtype.Inclusive or pgtype.Exclusive.
+// An empty range has both bound types set to pgtype.Empty.
+type Int4range struct {
+	Lower     Int4
+	Upper     Int4
+	LowerType pgtype.BoundType
+	UpperType pgtype.BoundType
+	Status    Status
+}
+
+func (dst *Int4range) Set(src interface{}) error {
+	if src == nil {
+		*dst = Int4range{Status: Null}
+		return nil
+	}
+
+	switch value := src.(type) {
+	case Int4range:
+		*dst = value
+	case *Int4range:
+		if value == nil {
+			*dst = Int4range{Status: Null}
+		} else {
+			*dst = *value
+		}
+	case string:
+		return dst.DecodeText(nil, []byte(value))
+	case *string:
+		if value == nil {
+			*dst = Int4range{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	default:
+		return errors.Errorf("cannot convert %v to Int4range", src)
+	}
+
+	return nil
+}
+
+func (dst Int4range) Get() interface{} {
+	switch dst.Status {
+	case Present:
+		return dst
+	case Null:
+		return nil
+	default:
+		return dst.Status
+	}
+}
+
+func (src *Int4range) AssignTo(dst interface{}) error {
+	switch src.Status {
+	case Present:
+		switch v := dst.(type) {
+		case *Int4range:
+			*v = *src
+			return nil
+		case *string:
+			buf, err := src.EncodeText(nil, nil)
+			if err != nil {
+				return err
+			}
+			*v = string(buf)
+			return nil
+		default:
+			if nextDst, retry := GetAssignToDstType(dst); retry {
+				return src.AssignTo(nextDst)
+			}
+			return errors.Errorf("unable to assign to %T", dst)
+		}
+	case Null:
+		return NullAssignTo(dst)
+	}
+
+	return errors.Errorf("cannot decode %#v into %T", src, dst)
+}
+
+func (dst *Int4range) DecodeText(ci *pgtype.ConnInfo, src []byte) error {
+	r, err := decodeRangeText[Int4](ci, src)
+	if err != nil {
+		return err
+	}
+
+	*dst = Int4range(r)
+	return nil
+}
+
+func (dst *Int4range) DecodeBinary(ci *pgtype.ConnInfo, src []byte) error {
+	r, err := decodeRangeBinary[Int4](ci, src)
+	if err != nil {
+		return err
+	}
+
+	*dst = Int4range(r)
+	return nil
+}
+
+func (src Int4range) EncodeText(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	return encodeRangeText(ci, buf, rangeValue[Int4](src))
+}
+
+func (src Int4range) EncodeBinary(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	return encodeRangeBinary(ci, buf, rangeValue[Int4](src))
+}
+
+// Scan implements the database/sql Scanner interface.
+func (dst *Int4range) Scan(src interface{}) error {
+	if src == nil {
+		*dst = Int4range{Status: Null}
+		return nil
+	}
+
+	switch src := src.(type) {
+	case string:
+		return dst.DecodeText(nil, []byte(src))
+	case []byte:
+		srcCopy := make([]byte, len(src))
+		copy(srcCopy, src)
+		return dst.DecodeText(nil, srcCopy)
+	}
+
+	return errors.Errorf("cannot scan %T", src)
+}
+
+// Value implements the database/sql/driver Valuer interface.
+func (src Int4range) Value() (driver.Value, error) {
+	return EncodeValueText(src)
+}
+
+// MarshalJSON encodes src as {"lower": 1, "upper": 5, "bounds": "[)"}, with
+// null for an unbounded side, or as {"empty": true}.
+func (src Int4range) MarshalJSON() ([]byte, error) {
+	return marshalRangeJSON(rangeValue[Int4](src))
+}
+
+func (dst *Int4range) UnmarshalJSON(b []byte) error {
+	r, err := unmarshalRangeJSON[Int4](b)
+	if err != nil {
+		return err
+	}
+
+	*dst = Int4range(r)
+	return nil
+}
+
+// Canonical returns src in the form the server stores it. Discrete bounds are
+// made inclusive lower and exclusive upper, so (1,3] becomes [2,4). A range
+// with no values becomes empty. It returns an error for a range the server
+// would reject, such as one with a lower bound greater than its upper bound.
+func (src Int4range) Canonical() (Int4range, error) {
+	r, err := int4RangeSubtype.makeRange(rangeValue[Int4](src), true)
+	if err != nil {
+		return Int4range{}, err
+	}
+	return Int4range(r), nil
+}
+
+// IsEmpty reports whether src contains no values.
+func (src Int4range) IsEmpty() bool {
+	r := int4RangeSubtype.normalize(rangeValue[Int4](src))
+	return r.Status == Present && r.isEmpty()
+}
+
+// Contains reports whether v is in src, as the server's @> operator does.
+func (src Int4range) Contains(v int32) bool {
+	return int4RangeSubtype.contains(rangeValue[Int4](src), Int4{Int: v, Status: Present})
+}
+
+// ContainsRange reports whether other is entirely within src, as the server's
+// @> operator does. Every range contains the empty range.
+func (src Int4range) ContainsRange(other Int4range) bool {
+	return int4RangeSubtype.containsRange(rangeValue[Int4](src), rangeValue[Int4](other))
+}
+
+// Overlaps reports whether src and other have values in common, as the server's
+// && operator does.
+func (src Int4range) Overlaps(other Int4range) bool {
+	return int4RangeSubtype.overlaps(rangeValue[Int4](src), rangeValue[Int4](other))
+}
+
+// Adjacent reports whether src and other do not overlap and no value lies
+// between them, as the server's -|- operator does.
+func (src Int4range) Adjacent(other Int4range) bool {
+	return int4RangeSubtype.adjacent(rangeValue[Int4](src), rangeValue[Int4](other))
+}
+
+// Intersect returns the values src and other have in common, as the server's *
+// operator does. The result is Null if either range is Null.
+func (src Int4range) Intersect(other Int4range) Int4range {
+	return Int4range(int4RangeSubtype.intersect(rangeValue[Int4](src), rangeValue[Int4](other)))
+}
+
+var int4RangeSubtype = rangeSubtype[Int4]{
+	cmp: func(a, b Int4) int {
+		return cmpInt64(int64(a.Int), int64(b.Int))
+	},
+	next: func(v Int4) (Int4, bool, error) {
+		if v.Int == math.MaxInt32 {
+			return Int4{}, false, errors.New("integer out of range")
+		}
+		return Int4{Int: v.Int + 1, Status: Present}, true, nil
+	},
+}
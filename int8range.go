@@ -0,0 +1,216 @@
+package tstype
+
+import (
+	"database/sql/driver"
+	"math"
+
+	"github.com/jackc/pgtype"
+
+	errors "golang.org/x/xerrors"
+)
+
+// Int8range represents the PostgreSQL int8range type. Lower and Upper are only
+// used when LowerType and UpperType are pgtype.Inclusive or pgtype.Exclusive.
+// An empty range has both bound types set to pgtype.Empty.
+type Int8range struct {
+	Lower     Int8
+	Upper     Int8
+	LowerType pgtype.BoundType
+	UpperType pgtype.BoundType
+	Status    Status
+}
+
+func (dst *Int8range) Set(src interface{}) error {
+	if src == nil {
+		*dst = Int8range{Status: Null}
+		return nil
+	}
+
+	switch value := src.(type) {
+	case Int8range:
+		*dst = value
+	case *Int8range:
+		if value == nil {
+			*dst = Int8range{Status: Null}
+		} else {
+			*dst = *value
+		}
+	case string:
+		return dst.DecodeText(nil, []byte(value))
+	case *string:
+		if value == nil {
+			*dst = Int8range{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	default:
+		return errors.Errorf("cannot convert %v to Int8range", src)
+	}
+
+	return nil
+}
+
+func (dst Int8range) Get() interface{} {
+	switch dst.Status {
+	case Present:
+		return dst
+	case Null:
+		return nil
+	default:
+		return dst.Status
+	}
+}
+
+func (src *Int8range) AssignTo(dst interface{}) error {
+	switch src.Status {
+	case Present:
+		switch v := dst.(type) {
+		case *Int8range:
+			*v = *src
+			return nil
+		case *string:
+			buf, err := src.EncodeText(nil, nil)
+			if err != nil {
+				return err
+			}
+			*v = string(buf)
+			return nil
+		default:
+			if nextDst, retry := GetAssignToDstType(dst); retry {
+				return src.AssignTo(nextDst)
+			}
+			return errors.Errorf("unable to assign to %T", dst)
+		}
+	case Null:
+		return NullAssignTo(dst)
+	}
+
+	return errors.Errorf("cannot decode %#v into %T", src, dst)
+}
+
+func (dst *Int8range) DecodeText(ci *pgtype.ConnInfo, src []byte) error {
+	r, err := decodeRangeText[Int8](ci, src)
+	if err != nil {
+		return err
+	}
+
+	*dst = Int8range(r)
+	return nil
+}
+
+func (dst *Int8range) DecodeBinary(ci *pgtype.ConnInfo, src []byte) error {
+	r, err := decodeRangeBinary[Int8](ci, src)
+	if err != nil {
+		return err
+	}
+
+	*dst = Int8range(r)
+	return nil
+}
+
+func (src Int8range) EncodeText(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	return encodeRangeText(ci, buf, rangeValue[Int8](src))
+}
+
+func (src Int8range) EncodeBinary(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	return encodeRangeBinary(ci, buf, rangeValue[Int8](src))
+}
+
+// Scan implements the database/sql Scanner interface.
+func (dst *Int8range) Scan(src interface{}) error {
+	if src == nil {
+		*dst = Int8range{Status: Null}
+		return nil
+	}
+
+	switch src := src.(type) {
+	case string:
+		return dst.DecodeText(nil, []byte(src))
+	case []byte:
+		srcCopy := make([]byte, len(src))
+		copy(srcCopy, src)
+		return dst.DecodeText(nil, srcCopy)
+	}
+
+	return errors.Errorf("cannot scan %T", src)
+}
+
+// Value implements the database/sql/driver Valuer interface.
+func (src Int8range) Value() (driver.Value, error) {
+	return EncodeValueText(src)
+}
+
+// MarshalJSON encodes src as {"lower": 1, "upper": 5, "bounds": "[)"}, with
+// null for an unbounded side, or as {"empty": true}.
+func (src Int8range) MarshalJSON() ([]byte, error) {
+	return marshalRangeJSON(rangeValue[Int8](src))
+}
+
+func (dst *Int8range) UnmarshalJSON(b []byte) error {
+	r, err := unmarshalRangeJSON[Int8](b)
+	if err != nil {
+		return err
+	}
+
+	*dst = Int8range(r)
+	return nil
+}
+
+// Canonical returns src in the form the server stores it. Discrete bounds are
+// made inclusive lower and exclusive upper, so (1,3] becomes [2,4). A range
+// with no values becomes empty. It returns an error for a range the server
+// would reject, such as one with a lower bound greater than its upper bound.
+func (src Int8range) Canonical() (Int8range, error) {
+	r, err := int8RangeSubtype.makeRange(rangeValue[Int8](src), true)
+	if err != nil {
+		return Int8range{}, err
+	}
+	return Int8range(r), nil
+}
+
+// IsEmpty reports whether src contains no values.
+func (src Int8range) IsEmpty() bool {
+	r := int8RangeSubtype.normalize(rangeValue[Int8](src))
+	return r.Status == Present && r.isEmpty()
+}
+
+// Contains reports whether v is in src, as the server's @> operator does.
+func (src Int8range) Contains(v int64) bool {
+	return int8RangeSubtype.contains(rangeValue[Int8](src), Int8{Int: v, Status: Present})
+}
+
+// ContainsRange reports whether other is entirely within src, as the server's
+// @> operator does. Every range contains the empty range.
+func (src Int8range) ContainsRange(other Int8range) bool {
+	return int8RangeSubtype.containsRange(rangeValue[Int8](src), rangeValue[Int8](other))
+}
+
+// Overlaps reports whether src and other have values in common, as the server's
+// && operator does.
+func (src Int8range) Overlaps(other Int8range) bool {
+	return int8RangeSubtype.overlaps(rangeValue[Int8](src), rangeValue[Int8](other))
+}
+
+// Adjacent reports whether src and other do not overlap and no value lies
+// between them, as the server's -|- operator does.
+func (src Int8range) Adjacent(other Int8range) bool {
+	return int8RangeSubtype.adjacent(rangeValue[Int8](src), rangeValue[Int8](other))
+}
+
+// Intersect returns the values src and other have in common, as the server's *
+// operator does. The result is Null if either range is Null.
+func (src Int8range) Intersect(other Int8range) Int8range {
+	return Int8range(int8RangeSubtype.intersect(rangeValue[Int8](src), rangeValue[Int8](other)))
+}
+
+var int8RangeSubtype = rangeSubtype[Int8]{
+	cmp: func(a, b Int8) int {
+		return cmpInt64(a.Int, b.Int)
+	},
+	next: func(v Int8) (Int8, bool, error) {
+		if v.Int == math.MaxInt64 {
+			return Int8{}, false, errors.New("bigint out of range")
+		}
+		return Int8{Int: v.Int + 1, Status: Present}, true, nil
+	},
+}
@@ -0,0 +1,121 @@
+package tstype_test
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/jackc/pgtype"
+	"github.com/tossp/tstype"
+)
+
+func TestBoolArray(t *testing.T) {
+	type flag bool
+
+	var a tstype.BoolArray
+	if err := a.Set([][]flag{{true, false}, {false, true}}); err != nil {
+		t.Fatal(err)
+	}
+	if len(a.Dimensions) != 2 || !a.Elements[3].Bool {
+		t.Errorf("Set [][]flag: got %+v", a)
+	}
+	var flags [][]flag
+	if err := a.AssignTo(&flags); err != nil || !reflect.DeepEqual(flags, [][]flag{{true, false}, {false, true}}) {
+		t.Errorf("AssignTo [][]flag: got %v, %v", flags, err)
+	}
+
+	b := true
+	if err := a.Set([]*bool{&b, nil}); err != nil || a.Elements[1].Status != tstype.Null {
+		t.Errorf("Set []*bool: got %+v, %v", a, err)
+	}
+	buf, err := a.EncodeText(nil, nil)
+	if err != nil || string(buf) != `{t,NULL}` {
+		t.Errorf("EncodeText: got %s, %v", buf, err)
+	}
+
+	ci := pgtype.NewConnInfo()
+	bin, err := a.EncodeBinary(ci, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fromBinary tstype.BoolArray
+	if err := fromBinary.DecodeBinary(ci, bin); err != nil || !reflect.DeepEqual(fromBinary, a) {
+		t.Errorf("DecodeBinary: got %+v, %v", fromBinary, err)
+	}
+
+	js, err := json.Marshal(a)
+	if err != nil || string(js) != `[true,null]` {
+		t.Errorf("MarshalJSON: got %s, %v", js, err)
+	}
+}
+
+func TestInt4ArraySetAndAssignTo(t *testing.T) {
+	type id int32
+
+	var a tstype.Int4Array
+	if err := a.Set([]id{1, 2, 3}); err != nil || a.Elements[2].Int != 3 {
+		t.Errorf("Set []id: got %+v, %v", a, err)
+	}
+	var ids []id
+	if err := a.AssignTo(&ids); err != nil || !reflect.DeepEqual(ids, []id{1, 2, 3}) {
+		t.Errorf("AssignTo []id: got %v, %v", ids, err)
+	}
+
+	if err := a.Set([]int64{math.MaxInt32 + 1}); err == nil {
+		t.Error("Set out of range: expected error")
+	}
+
+	if err := a.DecodeText(nil, []byte(`{{1,-2},{NULL,4}}`)); err != nil {
+		t.Fatal(err)
+	}
+	var ptrs [][]*int
+	if err := a.AssignTo(&ptrs); err != nil || len(ptrs) != 2 || *ptrs[0][1] != -2 || ptrs[1][0] != nil {
+		t.Errorf("AssignTo [][]*int: got %v, %v", ptrs, err)
+	}
+	var uints []uint32
+	if err := a.AssignTo(&uints); err == nil {
+		t.Error("AssignTo []uint32 with NULL and negative elements: expected error")
+	}
+
+	ci := pgtype.NewConnInfo()
+	bin, err := a.EncodeBinary(ci, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fromBinary tstype.Int4Array
+	if err := fromBinary.DecodeBinary(ci, bin); err != nil || !reflect.DeepEqual(fromBinary, a) {
+		t.Errorf("DecodeBinary: got %+v, %v", fromBinary, err)
+	}
+
+	js, err := json.Marshal(a)
+	if err != nil || string(js) != `[[1,-2],[null,4]]` {
+		t.Errorf("MarshalJSON: got %s, %v", js, err)
+	}
+}
+
+func TestInt8Array(t *testing.T) {
+	var a tstype.Int8Array
+	if err := a.Set([]uint64{1, math.MaxInt64}); err != nil {
+		t.Fatal(err)
+	}
+	buf, err := a.EncodeText(nil, nil)
+	if err != nil || string(buf) != `{1,9223372036854775807}` {
+		t.Errorf("EncodeText: got %s, %v", buf, err)
+	}
+
+	var fromText tstype.Int8Array
+	if err := fromText.DecodeText(nil, buf); err != nil || !reflect.DeepEqual(fromText, a) {
+		t.Errorf("DecodeText: got %+v, %v", fromText, err)
+	}
+
+	var ints []int64
+	if err := a.AssignTo(&ints); err != nil || !reflect.DeepEqual(ints, []int64{1, math.MaxInt64}) {
+		t.Errorf("AssignTo []int64: got %v, %v", ints, err)
+	}
+
+	var back tstype.Int8Array
+	if err := json.Unmarshal([]byte(`[[1],[2]]`), &back); err != nil || len(back.Dimensions) != 2 || back.Elements[1].Int != 2 {
+		t.Errorf("UnmarshalJSON: got %+v, %v", back, err)
+	}
+}
@@ -0,0 +1,189 @@
+package tstype_test
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/tossp/tstype"
+)
+
+func TestInt2Set(t *testing.T) {
+	type id int16
+	type bigID int64
+
+	tests := []struct {
+		src      interface{}
+		expected int16
+	}{
+		{int8(-1), -1},
+		{uint8(255), 255},
+		{uint16(math.MaxInt16), math.MaxInt16},
+		{int32(math.MinInt16), math.MinInt16},
+		{uint32(7), 7},
+		{int64(-7), -7},
+		{uint64(7), 7},
+		{int(-7), -7},
+		{uint(7), 7},
+		{"-32768", math.MinInt16},
+		{float64(12), 12},
+		{id(3), 3},
+		{bigID(-3), -3},
+	}
+	for i, tt := range tests {
+		var n tstype.Int2
+		if err := n.Set(tt.src); err != nil || n.Status != tstype.Present || n.Int != tt.expected {
+			t.Errorf("%d: %#v: expected %d, got %v, %v", i, tt.src, tt.expected, n, err)
+		}
+	}
+
+	for i, src := range []interface{}{
+		int64(math.MaxInt16 + 1),
+		int64(math.MinInt16 - 1),
+		uint16(math.MaxInt16 + 1),
+		uint64(math.MaxUint64),
+		int(40000),
+		"32768",
+		float64(1.5),
+		float64(40000),
+		bigID(math.MaxInt32),
+	} {
+		var n tstype.Int2
+		if err := n.Set(src); err == nil {
+			t.Errorf("%d: %#v: expected error, got %v", i, src, n)
+		}
+	}
+}
+
+func TestInt4Set(t *testing.T) {
+	for i, src := range []interface{}{
+		int64(math.MaxInt32 + 1),
+		int64(math.MinInt32 - 1),
+		uint32(math.MaxInt32 + 1),
+		uint64(math.MaxInt32 + 1),
+		"2147483648",
+	} {
+		var n tstype.Int4
+		if err := n.Set(src); err == nil {
+			t.Errorf("%d: %#v: expected error, got %v", i, src, n)
+		}
+	}
+
+	type id uint32
+	var n tstype.Int4
+	if err := n.Set(id(math.MaxInt32)); err != nil || n.Int != math.MaxInt32 {
+		t.Errorf("Set named uint32: got %v, %v", n, err)
+	}
+	p := int64(-5)
+	if err := n.Set(&p); err != nil || n.Int != -5 {
+		t.Errorf("Set *int64: got %v, %v", n, err)
+	}
+	if err := n.Set((*int64)(nil)); err != nil || n.Status != tstype.Null {
+		t.Errorf("Set nil *int64: got %v, %v", n, err)
+	}
+}
+
+func TestInt8Set(t *testing.T) {
+	var n tstype.Int8
+	if err := n.Set(uint64(math.MaxInt64)); err != nil || n.Int != math.MaxInt64 {
+		t.Errorf("Set max uint64: got %v, %v", n, err)
+	}
+	for i, src := range []interface{}{uint64(math.MaxInt64 + 1), uint(math.MaxInt64 + 1), "9223372036854775808", float64(1e19)} {
+		if err := n.Set(src); err == nil {
+			t.Errorf("%d: %#v: expected error, got %v", i, src, n)
+		}
+	}
+}
+
+func TestIntAssignTo(t *testing.T) {
+	type count uint8
+
+	n := tstype.Int8{Int: 200, Status: tstype.Present}
+	var c count
+	if err := n.AssignTo(&c); err != nil || c != 200 {
+		t.Errorf("AssignTo named uint8: got %v, %v", c, err)
+	}
+	var i8 int8
+	if err := n.AssignTo(&i8); err == nil {
+		t.Error("AssignTo int8 out of range: expected error")
+	}
+
+	negative := tstype.Int4{Int: -1, Status: tstype.Present}
+	var u uint32
+	if err := negative.AssignTo(&u); err == nil {
+		t.Error("AssignTo uint32 from negative: expected error")
+	}
+	var pi *int
+	if err := negative.AssignTo(&pi); err != nil || pi == nil || *pi != -1 {
+		t.Errorf("AssignTo *int: got %v, %v", pi, err)
+	}
+
+	null := tstype.Int2{Status: tstype.Null}
+	if err := null.AssignTo(&pi); err != nil || pi != nil {
+		t.Errorf("AssignTo *int from Null: got %v, %v", pi, err)
+	}
+	var i int
+	if err := null.AssignTo(&i); err == nil {
+		t.Error("AssignTo int from Null: expected error")
+	}
+}
+
+func TestIntBinaryRoundTrip(t *testing.T) {
+	int2s := []struct {
+		n        int16
+		expected []byte
+	}{
+		{0, []byte{0, 0}},
+		{-1, []byte{0xff, 0xff}},
+		{math.MaxInt16, []byte{0x7f, 0xff}},
+		{math.MinInt16, []byte{0x80, 0}},
+	}
+	for i, tt := range int2s {
+		buf, err := tstype.Int2{Int: tt.n, Status: tstype.Present}.EncodeBinary(nil, nil)
+		if err != nil || !bytes.Equal(buf, tt.expected) {
+			t.Errorf("%d: Int2 EncodeBinary: expected %x, got %x, %v", i, tt.expected, buf, err)
+		}
+		var n tstype.Int2
+		if err := n.DecodeBinary(nil, buf); err != nil || n.Int != tt.n {
+			t.Errorf("%d: Int2 DecodeBinary: expected %d, got %v, %v", i, tt.n, n, err)
+		}
+	}
+
+	for i, v := range []int32{0, -1, math.MaxInt32, math.MinInt32} {
+		buf, err := tstype.Int4{Int: v, Status: tstype.Present}.EncodeBinary(nil, nil)
+		if err != nil || len(buf) != 4 {
+			t.Fatalf("%d: Int4 EncodeBinary: got %x, %v", i, buf, err)
+		}
+		var n tstype.Int4
+		if err := n.DecodeBinary(nil, buf); err != nil || n.Int != v {
+			t.Errorf("%d: Int4 DecodeBinary: expected %d, got %v, %v", i, v, n, err)
+		}
+	}
+
+	for i, v := range []int64{0, -1, math.MaxInt64, math.MinInt64} {
+		buf, err := tstype.Int8{Int: v, Status: tstype.Present}.EncodeBinary(nil, nil)
+		if err != nil || len(buf) != 8 {
+			t.Fatalf("%d: Int8 EncodeBinary: got %x, %v", i, buf, err)
+		}
+		var n tstype.Int8
+		if err := n.DecodeBinary(nil, buf); err != nil || n.Int != v {
+			t.Errorf("%d: Int8 DecodeBinary: expected %d, got %v, %v", i, v, n, err)
+		}
+	}
+
+	var n2 tstype.Int2
+	if err := n2.DecodeBinary(nil, []byte{0, 0, 0, 1}); err == nil {
+		t.Error("Int2 DecodeBinary with 4 bytes: expected error")
+	}
+	var n4 tstype.Int4
+	if err := n4.DecodeBinary(nil, []byte{0, 1}); err == nil {
+		t.Error("Int4 DecodeBinary with 2 bytes: expected error")
+	}
+	if err := n4.DecodeBinary(nil, nil); err != nil || n4.Status != tstype.Null {
+		t.Errorf("Int4 DecodeBinary nil: got %v, %v", n4, err)
+	}
+	var n8 tstype.Int8
+	if err := n8.DecodeBinary(nil, []byte{0, 0, 0, 1}); err == nil {
+		t.Error("Int8 DecodeBinary with 4 bytes: expected error")
+	}
+}
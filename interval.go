@@ -0,0 +1,680 @@
+package tstype
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgio"
+	"github.com/jackc/pgtype"
+
+	errors "golang.org/x/xerrors"
+)
+
+const (
+	microsecondsPerSecond = 1000000
+	microsecondsPerMinute = 60 * microsecondsPerSecond
+	microsecondsPerHour   = 60 * microsecondsPerMinute
+)
+
+// Interval represents the PostgreSQL interval type. Months, days and
+// microseconds are kept apart, as the server does, because neither a month nor
+// a day has a fixed length.
+type Interval struct {
+	Microseconds int64
+	Days         int32
+	Months       int32
+	Status       Status
+}
+
+func (dst *Interval) Set(src interface{}) error {
+	if src == nil {
+		*dst = Interval{Status: Null}
+		return nil
+	}
+
+	if value, ok := src.(interface{ Get() interface{} }); ok {
+		value2 := value.Get()
+		if value2 != value {
+			return dst.Set(value2)
+		}
+	}
+
+	switch value := src.(type) {
+	case Interval:
+		*dst = value
+	case time.Duration:
+		*dst = Interval{Microseconds: int64(value / time.Microsecond), Status: Present}
+	case string:
+		return dst.DecodeText(nil, []byte(value))
+	case *time.Duration:
+		if value == nil {
+			*dst = Interval{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	case *string:
+		if value == nil {
+			*dst = Interval{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	default:
+		if originalSrc, ok := underlyingPtrType(src); ok {
+			return dst.Set(originalSrc)
+		}
+		return errors.Errorf("cannot convert %v to Interval", value)
+	}
+
+	return nil
+}
+
+func (dst Interval) Get() interface{} {
+	switch dst.Status {
+	case Present:
+		return dst
+	case Null:
+		return nil
+	default:
+		return dst.Status
+	}
+}
+
+// AssignTo assigns src to dst. Assigning to a time.Duration fails when Months
+// or Days is non-zero, as those have no fixed length.
+func (src *Interval) AssignTo(dst interface{}) error {
+	switch src.Status {
+	case Present:
+		switch v := dst.(type) {
+		case *time.Duration:
+			if src.Days != 0 || src.Months != 0 {
+				return errors.Errorf("interval with months or days cannot be decoded into %T", dst)
+			}
+			*v = time.Duration(src.Microseconds) * time.Microsecond
+			return nil
+		case *string:
+			*v = formatISO8601Interval(*src)
+			return nil
+		default:
+			if nextDst, retry := GetAssignToDstType(dst); retry {
+				return src.AssignTo(nextDst)
+			}
+			return errors.Errorf("unable to assign to %T", dst)
+		}
+	case Null:
+		return NullAssignTo(dst)
+	}
+
+	return errors.Errorf("cannot decode %#v into %T", src, dst)
+}
+
+// DecodeText decodes any of the postgres, postgres_verbose, sql_standard and
+// iso_8601 IntervalStyle output formats.
+func (dst *Interval) DecodeText(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Interval{Status: Null}
+		return nil
+	}
+
+	s := strings.TrimSpace(string(src))
+
+	var (
+		interval Interval
+		err      error
+	)
+	switch {
+	case strings.HasPrefix(s, "P"):
+		interval, err = parseISO8601Interval(s)
+	case strings.HasPrefix(s, "@"):
+		interval, err = parseVerboseInterval(s)
+	default:
+		interval, err = parsePostgresInterval(s)
+	}
+	if err != nil {
+		return err
+	}
+
+	interval.Status = Present
+	*dst = interval
+	return nil
+}
+
+func (dst *Interval) DecodeBinary(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Interval{Status: Null}
+		return nil
+	}
+
+	if len(src) != 16 {
+		return errors.Errorf("Received an invalid size for a interval: %d", len(src))
+	}
+
+	microseconds := int64(binary.BigEndian.Uint64(src))
+	days := int32(binary.BigEndian.Uint32(src[8:]))
+	months := int32(binary.BigEndian.Uint32(src[12:]))
+
+	*dst = Interval{Microseconds: microseconds, Days: days, Months: months, Status: Present}
+	return nil
+}
+
+// EncodeText encodes src in the postgres IntervalStyle. The time part always
+// carries an explicit sign so that the server reads it the same way under every
+// IntervalStyle.
+func (src Interval) EncodeText(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	if src.Months != 0 {
+		buf = strconv.AppendInt(buf, int64(src.Months), 10)
+		buf = append(buf, " mon "...)
+	}
+
+	if src.Days != 0 {
+		buf = strconv.AppendInt(buf, int64(src.Days), 10)
+		buf = append(buf, " day "...)
+	}
+
+	return appendIntervalTime(buf, src.Microseconds), nil
+}
+
+func (src Interval) EncodeBinary(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	buf = pgio.AppendInt64(buf, src.Microseconds)
+	buf = pgio.AppendInt32(buf, src.Days)
+	return pgio.AppendInt32(buf, src.Months), nil
+}
+
+// Scan implements the database/sql Scanner interface.
+func (dst *Interval) Scan(src interface{}) error {
+	if src == nil {
+		*dst = Interval{Status: Null}
+		return nil
+	}
+
+	switch src := src.(type) {
+	case string:
+		return dst.DecodeText(nil, []byte(src))
+	case []byte:
+		srcCopy := make([]byte, len(src))
+		copy(srcCopy, src)
+		return dst.DecodeText(nil, srcCopy)
+	}
+
+	return errors.Errorf("cannot scan %T", src)
+}
+
+// Value implements the database/sql/driver Valuer interface.
+func (src Interval) Value() (driver.Value, error) {
+	return EncodeValueText(src)
+}
+
+// MarshalJSON encodes src as an ISO 8601 duration such as "P1Y2M3DT4H5M6.5S",
+// the same form the iso_8601 IntervalStyle produces.
+func (src Interval) MarshalJSON() ([]byte, error) {
+	switch src.Status {
+	case Present:
+		return json.Marshal(formatISO8601Interval(src))
+	case Null:
+		return []byte("null"), nil
+	}
+
+	return nil, errBadStatus
+}
+
+func (dst *Interval) UnmarshalJSON(b []byte) error {
+	var s *string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		return err
+	}
+
+	if s == nil {
+		*dst = Interval{Status: Null}
+		return nil
+	}
+
+	return dst.DecodeText(nil, []byte(*s))
+}
+
+// appendIntervalTime appends microseconds as a signed [+-]HH:MM:SS[.ffffff].
+func appendIntervalTime(buf []byte, microseconds int64) []byte {
+	if microseconds < 0 {
+		buf = append(buf, '-')
+		microseconds = -microseconds
+	} else {
+		buf = append(buf, '+')
+	}
+
+	hours := microseconds / microsecondsPerHour
+	microseconds -= hours * microsecondsPerHour
+	minutes := microseconds / microsecondsPerMinute
+	microseconds -= minutes * microsecondsPerMinute
+
+	buf = appendTwoDigits(buf, hours)
+	buf = append(buf, ':')
+	buf = appendTwoDigits(buf, minutes)
+	buf = append(buf, ':')
+	return appendSeconds(buf, microseconds, 2)
+}
+
+// appendSeconds appends microseconds as seconds with as many fractional digits
+// as needed. The integer part is zero padded to width digits.
+func appendSeconds(buf []byte, microseconds int64, width int) []byte {
+	seconds := strconv.FormatInt(microseconds/microsecondsPerSecond, 10)
+	if len(seconds) < width {
+		buf = append(buf, strings.Repeat("0", width-len(seconds))...)
+	}
+	buf = append(buf, seconds...)
+
+	if frac := microseconds % microsecondsPerSecond; frac != 0 {
+		buf = append(buf, '.')
+		buf = append(buf, strings.TrimRight(strconv.FormatInt(frac+microsecondsPerSecond, 10)[1:], "0")...)
+	}
+
+	return buf
+}
+
+// formatISO8601Interval formats an interval the way the iso_8601 IntervalStyle
+// does. Each component carries its own sign.
+func formatISO8601Interval(src Interval) string {
+	if src.Months == 0 && src.Days == 0 && src.Microseconds == 0 {
+		return "PT0S"
+	}
+
+	buf := []byte{'P'}
+
+	years := src.Months / 12
+	months := src.Months % 12
+	if years != 0 {
+		buf = strconv.AppendInt(buf, int64(years), 10)
+		buf = append(buf, 'Y')
+	}
+	if months != 0 {
+		buf = strconv.AppendInt(buf, int64(months), 10)
+		buf = append(buf, 'M')
+	}
+	if src.Days != 0 {
+		buf = strconv.AppendInt(buf, int64(src.Days), 10)
+		buf = append(buf, 'D')
+	}
+
+	if src.Microseconds != 0 {
+		buf = append(buf, 'T')
+
+		hours := src.Microseconds / microsecondsPerHour
+		rest := src.Microseconds - hours*microsecondsPerHour
+		minutes := rest / microsecondsPerMinute
+		rest -= minutes * microsecondsPerMinute
+
+		if hours != 0 {
+			buf = strconv.AppendInt(buf, hours, 10)
+			buf = append(buf, 'H')
+		}
+		if minutes != 0 {
+			buf = strconv.AppendInt(buf, minutes, 10)
+			buf = append(buf, 'M')
+		}
+		if rest != 0 {
+			if rest < 0 {
+				buf = append(buf, '-')
+				rest = -rest
+			}
+			buf = appendSeconds(buf, rest, 1)
+			buf = append(buf, 'S')
+		}
+	}
+
+	return string(buf)
+}
+
+// parseISO8601Interval parses the designator form of an ISO 8601 duration,
+// e.g. "P1Y2M3DT4H5M6.789S" or "P-1Y-2M3DT-4H-5M-6S".
+func parseISO8601Interval(s string) (Interval, error) {
+	var interval Interval
+
+	rest := s[1:]
+	if rest == "" {
+		return interval, errors.Errorf("invalid interval format: %q", s)
+	}
+
+	inTime := false
+	for rest != "" {
+		if rest[0] == 'T' {
+			if inTime {
+				return interval, errors.Errorf("invalid interval format: %q", s)
+			}
+			inTime = true
+			rest = rest[1:]
+			continue
+		}
+
+		i := strings.IndexAny(rest, "YMWDHS")
+		if i <= 0 {
+			return interval, errors.Errorf("invalid interval format: %q", s)
+		}
+		number, designator := rest[:i], rest[i]
+		rest = rest[i+1:]
+
+		var err error
+		if inTime {
+			switch designator {
+			case 'H':
+				err = addIntervalUnit(&interval, number, "hour")
+			case 'M':
+				err = addIntervalUnit(&interval, number, "minute")
+			case 'S':
+				err = addIntervalUnit(&interval, number, "second")
+			default:
+				err = errors.Errorf("invalid interval format: %q", s)
+			}
+		} else {
+			switch designator {
+			case 'Y':
+				err = addIntervalUnit(&interval, number, "year")
+			case 'M':
+				err = addIntervalUnit(&interval, number, "month")
+			case 'W':
+				err = addIntervalUnit(&interval, number, "week")
+			case 'D':
+				err = addIntervalUnit(&interval, number, "day")
+			default:
+				err = errors.Errorf("invalid interval format: %q", s)
+			}
+		}
+		if err != nil {
+			return interval, err
+		}
+	}
+
+	return interval, nil
+}
+
+// parseVerboseInterval parses the postgres_verbose IntervalStyle, e.g.
+// "@ 1 year 2 mons -3 days 4 hours 5 mins 6.5 secs ago".
+func parseVerboseInterval(s string) (Interval, error) {
+	var interval Interval
+
+	fields := strings.Fields(s[1:])
+	ago := false
+	if len(fields) > 0 && fields[len(fields)-1] == "ago" {
+		ago = true
+		fields = fields[:len(fields)-1]
+	}
+
+	// "@ 0" is the verbose form of an empty interval
+	if len(fields) == 1 && fields[0] == "0" {
+		return interval, nil
+	}
+
+	if len(fields)%2 != 0 {
+		return interval, errors.Errorf("invalid interval format: %q", s)
+	}
+
+	for i := 0; i < len(fields); i += 2 {
+		if err := addIntervalUnit(&interval, fields[i], fields[i+1]); err != nil {
+			return interval, err
+		}
+	}
+
+	if ago {
+		interval.Months = -interval.Months
+		interval.Days = -interval.Days
+		interval.Microseconds = -interval.Microseconds
+	}
+
+	return interval, nil
+}
+
+// parsePostgresInterval parses the postgres and sql_standard IntervalStyles,
+// e.g. "1 year 2 mons 3 days 04:05:06", "-1 days +02:03:00", "1-2 3 4:05:06" or
+// "-1-2 +3 -4:05:06".
+//
+// As in the server, a leading sign on an sql_standard value applies to every
+// field when no other field carries an explicit sign.
+func parsePostgresInterval(s string) (Interval, error) {
+	var interval Interval
+
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return interval, errors.Errorf("invalid interval format: %q", s)
+	}
+
+	hasUnits := false
+	explicitSigns := 0
+	for _, f := range fields {
+		if f[0] == '+' || f[0] == '-' {
+			explicitSigns++
+		} else if !isIntervalNumber(f) && !strings.ContainsAny(f, ":-") {
+			hasUnits = true
+		}
+	}
+	negateAll := !hasUnits && fields[0][0] == '-' && explicitSigns == 1
+
+	for i := 0; i < len(fields); i++ {
+		f := fields[i]
+		if negateAll && i > 0 {
+			f = "-" + f
+		}
+
+		switch {
+		case strings.Contains(f, ":"):
+			microseconds, err := parseIntervalTime(f)
+			if err != nil {
+				return interval, err
+			}
+			interval.Microseconds += microseconds
+		case isIntervalYearMonth(f):
+			sign := int32(1)
+			ym := f
+			if ym[0] == '+' || ym[0] == '-' {
+				if ym[0] == '-' {
+					sign = -1
+				}
+				ym = ym[1:]
+			}
+			dash := strings.IndexByte(ym, '-')
+			years, err := strconv.ParseInt(ym[:dash], 10, 32)
+			if err != nil {
+				return interval, errors.Errorf("invalid interval format: %q", s)
+			}
+			months, err := strconv.ParseInt(ym[dash+1:], 10, 32)
+			if err != nil {
+				return interval, errors.Errorf("invalid interval format: %q", s)
+			}
+			interval.Months += sign * int32(years*12+months)
+		case isIntervalNumber(f):
+			if i+1 < len(fields) && !isIntervalNumber(fields[i+1]) && !strings.Contains(fields[i+1], ":") && !isIntervalYearMonth(fields[i+1]) {
+				if err := addIntervalUnit(&interval, f, fields[i+1]); err != nil {
+					return interval, err
+				}
+				i++
+			} else if i+1 < len(fields) && strings.Contains(fields[i+1], ":") {
+				// sql_standard: a bare number before the time is days
+				if err := addIntervalUnit(&interval, f, "day"); err != nil {
+					return interval, err
+				}
+			} else {
+				// a bare number on its own is seconds
+				if err := addIntervalUnit(&interval, f, "second"); err != nil {
+					return interval, err
+				}
+			}
+		default:
+			return interval, errors.Errorf("invalid interval format: %q", s)
+		}
+	}
+
+	return interval, nil
+}
+
+func isIntervalNumber(s string) bool {
+	if s != "" && (s[0] == '+' || s[0] == '-') {
+		s = s[1:]
+	}
+	if s == "" {
+		return false
+	}
+	dot := false
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+		case r == '.' && !dot:
+			dot = true
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func isIntervalYearMonth(s string) bool {
+	if s != "" && (s[0] == '+' || s[0] == '-') {
+		s = s[1:]
+	}
+	dash := strings.IndexByte(s, '-')
+	if dash <= 0 || dash == len(s)-1 {
+		return false
+	}
+	for i, r := range s {
+		if i != dash && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// parseIntervalTime parses [+-]H:MM[:SS[.ffffff]] into microseconds. Hours may
+// have any number of digits.
+func parseIntervalTime(s string) (int64, error) {
+	negative := false
+	if s[0] == '+' || s[0] == '-' {
+		negative = s[0] == '-'
+		s = s[1:]
+	}
+
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, errors.Errorf("invalid interval time format: %q", s)
+	}
+
+	hours, err := strconv.ParseUint(parts[0], 10, 63)
+	if err != nil {
+		return 0, errors.Errorf("invalid interval time format: %q", s)
+	}
+	minutes, err := strconv.ParseUint(parts[1], 10, 63)
+	if err != nil || minutes > 59 {
+		return 0, errors.Errorf("invalid interval time format: %q", s)
+	}
+
+	var seconds int64
+	if len(parts) == 3 {
+		seconds, err = parseIntervalSeconds(parts[2])
+		if err != nil || seconds < 0 || seconds >= 60*microsecondsPerSecond {
+			return 0, errors.Errorf("invalid interval time format: %q", s)
+		}
+	}
+
+	microseconds := int64(hours)*microsecondsPerHour + int64(minutes)*microsecondsPerMinute + seconds
+	if negative {
+		microseconds = -microseconds
+	}
+
+	return microseconds, nil
+}
+
+// parseIntervalSeconds parses a signed decimal number of seconds into
+// microseconds. Digits beyond microsecond precision are truncated.
+func parseIntervalSeconds(s string) (int64, error) {
+	negative := false
+	if s != "" && (s[0] == '+' || s[0] == '-') {
+		negative = s[0] == '-'
+		s = s[1:]
+	}
+
+	intPart, fracPart := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, fracPart = s[:i], s[i+1:]
+	}
+	if intPart == "" && fracPart == "" {
+		return 0, errors.Errorf("invalid seconds: %q", s)
+	}
+
+	var n int64
+	if intPart != "" {
+		v, err := strconv.ParseUint(intPart, 10, 63)
+		if err != nil {
+			return 0, errors.Errorf("invalid seconds: %q", s)
+		}
+		n = int64(v) * microsecondsPerSecond
+	}
+
+	if fracPart != "" {
+		if len(fracPart) > 6 {
+			fracPart = fracPart[:6]
+		}
+		v, err := strconv.ParseUint(fracPart+strings.Repeat("0", 6-len(fracPart)), 10, 63)
+		if err != nil {
+			return 0, errors.Errorf("invalid seconds: %q", s)
+		}
+		n += int64(v)
+	}
+
+	if negative {
+		n = -n
+	}
+
+	return n, nil
+}
+
+// addIntervalUnit adds number of unit to interval. Only seconds may have a
+// fractional part.
+func addIntervalUnit(interval *Interval, number, unit string) error {
+	switch strings.ToLower(unit) {
+	case "s", "sec", "secs", "second", "seconds":
+		microseconds, err := parseIntervalSeconds(number)
+		if err != nil {
+			return err
+		}
+		interval.Microseconds += microseconds
+		return nil
+	}
+
+	n, err := strconv.ParseInt(number, 10, 32)
+	if err != nil {
+		return errors.Errorf("invalid interval %s: %q", unit, number)
+	}
+
+	switch strings.ToLower(unit) {
+	case "y", "year", "years":
+		interval.Months += int32(n) * 12
+	case "mon", "mons", "month", "months":
+		interval.Months += int32(n)
+	case "w", "week", "weeks":
+		interval.Days += int32(n) * 7
+	case "d", "day", "days":
+		interval.Days += int32(n)
+	case "h", "hour", "hours":
+		interval.Microseconds += n * microsecondsPerHour
+	case "m", "min", "mins", "minute", "minutes":
+		interval.Microseconds += n * microsecondsPerMinute
+	default:
+		return errors.Errorf("invalid interval unit: %q", unit)
+	}
+
+	return nil
+}
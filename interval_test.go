@@ -0,0 +1,106 @@
+package tstype_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tossp/tstype"
+)
+
+func TestIntervalDecodeText(t *testing.T) {
+	mixed := tstype.Interval{Months: -14, Days: 3, Microseconds: -14706000000, Status: tstype.Present}
+
+	tests := []struct {
+		src      string
+		expected tstype.Interval
+	}{
+		// postgres
+		{src: "-1 years -2 mons +3 days -04:05:06", expected: mixed},
+		{src: "-00:00:01.5", expected: tstype.Interval{Microseconds: -1500000, Status: tstype.Present}},
+		// postgres_verbose
+		{src: "@ 1 year 2 mons -3 days 4 hours 5 mins 6 secs ago", expected: mixed},
+		{src: "@ 0", expected: tstype.Interval{Status: tstype.Present}},
+		// sql_standard
+		{src: "-1-2 +3 -4:05:06", expected: mixed},
+		{src: "-1 2:03:04", expected: tstype.Interval{Days: -1, Microseconds: -7384000000, Status: tstype.Present}},
+		{src: "1-2", expected: tstype.Interval{Months: 14, Status: tstype.Present}},
+		// iso_8601
+		{src: "P-1Y-2M3DT-4H-5M-6S", expected: mixed},
+		{src: "PT0S", expected: tstype.Interval{Status: tstype.Present}},
+	}
+
+	for i, tt := range tests {
+		var r tstype.Interval
+		if err := r.DecodeText(nil, []byte(tt.src)); err != nil {
+			t.Errorf("%d: %v", i, err)
+			continue
+		}
+		if r != tt.expected {
+			t.Errorf("%d: %q: expected %+v, got %+v", i, tt.src, tt.expected, r)
+		}
+	}
+}
+
+func TestIntervalEncodeTextRoundTrip(t *testing.T) {
+	src := tstype.Interval{Months: -14, Days: 3, Microseconds: 1, Status: tstype.Present}
+
+	buf, err := src.EncodeText(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var dst tstype.Interval
+	if err := dst.DecodeText(nil, buf); err != nil {
+		t.Fatal(err)
+	}
+	if dst != src {
+		t.Errorf("expected %+v, got %+v from %q", src, dst, buf)
+	}
+}
+
+func TestIntervalMarshalJSON(t *testing.T) {
+	src := tstype.Interval{Months: 14, Days: 3, Microseconds: 14706500000, Status: tstype.Present}
+
+	buf, err := src.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(buf) != `"P1Y2M3DT4H5M6.5S"` {
+		t.Errorf("unexpected JSON %s", buf)
+	}
+
+	var dst tstype.Interval
+	if err := dst.UnmarshalJSON(buf); err != nil {
+		t.Fatal(err)
+	}
+	if dst != src {
+		t.Errorf("expected %+v, got %+v", src, dst)
+	}
+}
+
+func TestIntervalAssignToDuration(t *testing.T) {
+	var d time.Duration
+
+	src := tstype.Interval{Microseconds: 1500000, Status: tstype.Present}
+	if err := src.AssignTo(&d); err != nil {
+		t.Fatal(err)
+	}
+	if d != 1500*time.Millisecond {
+		t.Errorf("expected 1.5s, got %v", d)
+	}
+
+	src = tstype.Interval{Days: 1, Status: tstype.Present}
+	if err := src.AssignTo(&d); err == nil {
+		t.Error("expected error assigning interval with days to time.Duration")
+	}
+}
+
+func TestIntervalEncodeInvalidStatus(t *testing.T) {
+	i := tstype.Interval{Months: 1, Status: tstype.Status(2)}
+	if _, err := i.EncodeText(nil, nil); err == nil {
+		t.Error("EncodeText: expected error")
+	}
+	if _, err := i.EncodeBinary(nil, nil); err == nil {
+		t.Error("EncodeBinary: expected error")
+	}
+}
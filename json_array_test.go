@@ -0,0 +1,125 @@
+package tstype_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/jackc/pgtype"
+	"github.com/tossp/tstype"
+)
+
+func TestJSONBArraySet(t *testing.T) {
+	var a tstype.JSONBArray
+	if err := a.Set([]json.RawMessage{json.RawMessage(`{"id": 1}`), nil}); err != nil {
+		t.Fatal(err)
+	}
+	if string(a.Elements[0].Bytes) != `{"id":1}` || a.Elements[1].Status != tstype.Null {
+		t.Errorf("Set []json.RawMessage: got %+v", a)
+	}
+	if err := a.Set([]json.RawMessage{json.RawMessage(`{`)}); err == nil {
+		t.Error("Set invalid json.RawMessage: expected error")
+	}
+
+	if err := a.Set([]map[string]interface{}{{"kind": "click"}, {"kind": "view"}}); err != nil || string(a.Elements[1].Bytes) != `{"kind":"view"}` {
+		t.Errorf("Set []map[string]interface{}: got %+v, %v", a, err)
+	}
+
+	if err := a.Set([]interface{}{"hello", []int{1, 2}, nil}); err != nil {
+		t.Fatal(err)
+	}
+	if len(a.Dimensions) != 1 || a.Dimensions[0].Length != 3 || string(a.Elements[0].Bytes) != `"hello"` || string(a.Elements[1].Bytes) != `[1,2]` || a.Elements[2].Status != tstype.Null {
+		t.Errorf("Set []interface{}: got %+v", a)
+	}
+
+	var docs []interface{}
+	if err := a.AssignTo(&docs); err != nil || !reflect.DeepEqual(docs, []interface{}{"hello", []interface{}{1.0, 2.0}, nil}) {
+		t.Errorf("AssignTo []interface{}: got %v, %v", docs, err)
+	}
+
+	type event struct {
+		Kind string `json:"kind"`
+	}
+	if err := a.Set([]event{{Kind: "click"}}); err != nil {
+		t.Fatal(err)
+	}
+	var events []event
+	if err := a.AssignTo(&events); err != nil || !reflect.DeepEqual(events, []event{{Kind: "click"}}) {
+		t.Errorf("AssignTo []event: got %v, %v", events, err)
+	}
+}
+
+func TestJSONBArrayCodecs(t *testing.T) {
+	a := tstype.JSONBArray{
+		Elements: []tstype.JSONB{
+			{Bytes: []byte(`{"a":"b c"}`), Status: tstype.Present},
+			{Bytes: []byte(`[1,2]`), Status: tstype.Present},
+			{Status: tstype.Null},
+		},
+		Dimensions: []pgtype.ArrayDimension{{Length: 3, LowerBound: 1}},
+		Status:     tstype.Present,
+	}
+
+	buf, err := a.EncodeText(nil, nil)
+	if err != nil || string(buf) != `{"{\"a\":\"b c\"}","[1,2]",NULL}` {
+		t.Errorf("EncodeText: got %s, %v", buf, err)
+	}
+	var fromText tstype.JSONBArray
+	if err := fromText.DecodeText(nil, buf); err != nil || !reflect.DeepEqual(fromText, a) {
+		t.Errorf("DecodeText: got %+v, %v", fromText, err)
+	}
+
+	ci := pgtype.NewConnInfo()
+	bin, err := a.EncodeBinary(ci, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fromBinary tstype.JSONBArray
+	if err := fromBinary.DecodeBinary(ci, bin); err != nil || !reflect.DeepEqual(fromBinary, a) {
+		t.Errorf("DecodeBinary: got %+v, %v", fromBinary, err)
+	}
+
+	// Each jsonb element carries its own version byte.
+	var j tstype.JSONArray
+	if err := j.Set([]string{`{"x":1}`}); err != nil {
+		t.Fatal(err)
+	}
+	jsonBin, err := j.EncodeBinary(ci, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.Elements = a.Elements[:1]
+	a.Elements[0].Bytes = []byte(`{"x":1}`)
+	a.Dimensions[0].Length = 1
+	if bin, _ = a.EncodeBinary(ci, nil); len(bin) != len(jsonBin)+1 || bin[len(bin)-8] != 1 {
+		t.Errorf("EncodeBinary: expected a version byte before the element, got %v", bin)
+	}
+}
+
+func TestJSONArrayJSON(t *testing.T) {
+	var a tstype.JSONArray
+	if err := a.Set([]interface{}{map[string]interface{}{"id": 1}, []string{"x"}, nil}); err != nil {
+		t.Fatal(err)
+	}
+
+	js, err := json.Marshal(a)
+	if err != nil || string(js) != `[{"id":1},["x"],null]` {
+		t.Errorf("MarshalJSON: got %s, %v", js, err)
+	}
+
+	var back tstype.JSONArray
+	if err := json.Unmarshal(js, &back); err != nil {
+		t.Fatal(err)
+	}
+	if len(back.Dimensions) != 1 || back.Dimensions[0].Length != 3 || string(back.Elements[1].Bytes) != `["x"]` || back.Elements[2].Status != tstype.Null {
+		t.Errorf("UnmarshalJSON: got %+v", back)
+	}
+
+	var empty tstype.JSONBArray
+	if err := json.Unmarshal([]byte(`[]`), &empty); err != nil || empty.Status != tstype.Present || len(empty.Elements) != 0 {
+		t.Errorf("UnmarshalJSON empty: got %+v, %v", empty, err)
+	}
+	if js, _ := json.Marshal(empty); string(js) != `[]` {
+		t.Errorf("MarshalJSON empty: got %s", js)
+	}
+}
@@ -0,0 +1,281 @@
+package tstype
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"math"
+
+	"github.com/jackc/pgtype"
+
+	errors "golang.org/x/xerrors"
+)
+
+// Line represents the PostgreSQL line type, the infinite line Ax + By + C = 0.
+// JSON is a string of the text form, as GeoJSON has no infinite lines.
+type Line struct {
+	A, B, C float64
+	Status  Status
+}
+
+func validateLine(a, b float64) error {
+	if fpZero(a) && fpZero(b) {
+		return errors.New("invalid line specification: A and B cannot both be zero")
+	}
+	return nil
+}
+
+// lineThrough returns the line through two distinct points, normalized the
+// same way the server's line_construct does.
+func lineThrough(p0, p1 pgtype.Vec2) Line {
+	switch {
+	case fpEq(p0.X, p1.X):
+		return Line{A: -1, B: 0, C: p0.X, Status: Present}
+	case fpEq(p0.Y, p1.Y):
+		return Line{A: 0, B: -1, C: p0.Y, Status: Present}
+	}
+
+	m := (p1.Y - p0.Y) / (p1.X - p0.X)
+	c := p0.Y - m*p0.X
+	if c == 0 {
+		// Avoid -0.
+		c = 0
+	}
+	return Line{A: m, B: -1, C: c, Status: Present}
+}
+
+func (dst *Line) Set(src interface{}) error {
+	if src == nil {
+		*dst = Line{Status: Null}
+		return nil
+	}
+
+	switch value := src.(type) {
+	case Line:
+		*dst = value
+	case *Line:
+		if value == nil {
+			*dst = Line{Status: Null}
+		} else {
+			*dst = *value
+		}
+	case string:
+		return dst.DecodeText(nil, []byte(value))
+	case *string:
+		if value == nil {
+			*dst = Line{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	default:
+		if originalSrc, ok := underlyingStringType(src); ok {
+			return dst.Set(originalSrc)
+		}
+		return errors.Errorf("cannot convert %v to Line", value)
+	}
+
+	return nil
+}
+
+func (dst Line) Get() interface{} {
+	switch dst.Status {
+	case Present:
+		return dst
+	case Null:
+		return nil
+	default:
+		return dst.Status
+	}
+}
+
+func (src *Line) AssignTo(dst interface{}) error {
+	switch src.Status {
+	case Present:
+		switch v := dst.(type) {
+		case *Line:
+			*v = *src
+			return nil
+		case *string:
+			buf, err := src.EncodeText(nil, nil)
+			if err != nil {
+				return err
+			}
+			*v = string(buf)
+			return nil
+		default:
+			if nextDst, retry := GetAssignToDstType(dst); retry {
+				return src.AssignTo(nextDst)
+			}
+			return errors.Errorf("unable to assign to %T", dst)
+		}
+	case Null:
+		return NullAssignTo(dst)
+	}
+
+	return errors.Errorf("cannot decode %#v into %T", src, dst)
+}
+
+// DecodeText accepts {A,B,C} and, like the server, two distinct points on the
+// line in any of the lseg formats.
+func (dst *Line) DecodeText(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Line{Status: Null}
+		return nil
+	}
+
+	p := geoParser{typeName: "line", s: string(src)}
+	if !p.consume('{') {
+		points, _, err := p.parsePoints(2, true)
+		if err != nil {
+			return err
+		}
+		if err := p.end(); err != nil {
+			return err
+		}
+		if vec2Eq(points[0], points[1]) {
+			return errors.New("invalid line specification: must be two distinct points")
+		}
+		*dst = lineThrough(points[0], points[1])
+		return nil
+	}
+
+	var abc [3]float64
+	for i := range abc {
+		if i > 0 && !p.consume(',') {
+			return p.syntaxError()
+		}
+		f, err := p.parseFloat()
+		if err != nil {
+			return err
+		}
+		abc[i] = f
+	}
+	if !p.consume('}') {
+		return p.syntaxError()
+	}
+	if err := p.end(); err != nil {
+		return err
+	}
+	if err := validateLine(abc[0], abc[1]); err != nil {
+		return err
+	}
+
+	*dst = Line{A: abc[0], B: abc[1], C: abc[2], Status: Present}
+	return nil
+}
+
+func (dst *Line) DecodeBinary(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Line{Status: Null}
+		return nil
+	}
+
+	fs, err := readFloat8s("line", src, 3)
+	if err != nil {
+		return err
+	}
+	if err := validateLine(fs[0], fs[1]); err != nil {
+		return err
+	}
+
+	*dst = Line{A: fs[0], B: fs[1], C: fs[2], Status: Present}
+	return nil
+}
+
+func (src Line) EncodeText(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	if err := validateLine(src.A, src.B); err != nil {
+		return nil, err
+	}
+
+	buf = append(buf, '{')
+	buf = append(buf, formatPgFloat(src.A, 64)...)
+	buf = append(buf, ',')
+	buf = append(buf, formatPgFloat(src.B, 64)...)
+	buf = append(buf, ',')
+	buf = append(buf, formatPgFloat(src.C, 64)...)
+	return append(buf, '}'), nil
+}
+
+func (src Line) EncodeBinary(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	if err := validateLine(src.A, src.B); err != nil {
+		return nil, err
+	}
+
+	buf = appendFloat8Binary(buf, src.A)
+	buf = appendFloat8Binary(buf, src.B)
+	return appendFloat8Binary(buf, src.C), nil
+}
+
+// Scan implements the database/sql Scanner interface.
+func (dst *Line) Scan(src interface{}) error {
+	if src == nil {
+		*dst = Line{Status: Null}
+		return nil
+	}
+
+	switch src := src.(type) {
+	case string:
+		return dst.DecodeText(nil, []byte(src))
+	case []byte:
+		srcCopy := make([]byte, len(src))
+		copy(srcCopy, src)
+		return dst.DecodeText(nil, srcCopy)
+	}
+
+	return errors.Errorf("cannot scan %T", src)
+}
+
+// Value implements the database/sql/driver Valuer interface.
+func (src Line) Value() (driver.Value, error) {
+	return EncodeValueText(src)
+}
+
+// MarshalJSON encodes src as a JSON string of its text form.
+func (src Line) MarshalJSON() ([]byte, error) {
+	switch src.Status {
+	case Present:
+		buf, err := src.EncodeText(nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(string(buf))
+	case Null:
+		return []byte("null"), nil
+	}
+
+	return nil, errBadStatus
+}
+
+func (dst *Line) UnmarshalJSON(b []byte) error {
+	var s *string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		return err
+	}
+
+	return dst.Set(s)
+}
+
+// Distance returns the distance from p to src, as the server's <-> operator
+// does. It is NaN if either is not Present.
+func (src Line) Distance(p Point) float64 {
+	if src.Status != Present || p.Status != Present {
+		return math.NaN()
+	}
+	return math.Abs(src.A*p.P.X+src.B*p.P.Y+src.C) / math.Hypot(src.A, src.B)
+}
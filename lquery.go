@@ -0,0 +1,490 @@
+package tstype
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jackc/pgtype"
+
+	errors "golang.org/x/xerrors"
+)
+
+// LqueryVariant is a label pattern of an lquery level or an ltxtquery operand.
+// Prefix (written *) matches labels that start with Label. CaseInsensitive
+// (written @) ignores case. Words (written %) matches labels that contain
+// each underscore-separated word of Label as a word.
+type LqueryVariant struct {
+	Label           string
+	Prefix          bool
+	CaseInsensitive bool
+	Words           bool
+}
+
+// Match reports whether label matches v, as the server's checkLevel does for
+// a single variant.
+func (v LqueryVariant) Match(label string) bool {
+	pattern := v.Label
+	if v.CaseInsensitive {
+		pattern, label = strings.ToLower(pattern), strings.ToLower(label)
+	}
+
+	if !v.Words {
+		return label == pattern || (v.Prefix && strings.HasPrefix(label, pattern))
+	}
+
+	labelWords := strings.FieldsFunc(label, isLtreeWordSeparator)
+	for _, pw := range strings.FieldsFunc(pattern, isLtreeWordSeparator) {
+		found := false
+		for _, lw := range labelWords {
+			if lw == pw || (v.Prefix && strings.HasPrefix(lw, pw)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func isLtreeWordSeparator(r rune) bool {
+	return r == '_'
+}
+
+func (v LqueryVariant) appendText(buf []byte) []byte {
+	buf = append(buf, v.Label...)
+	if v.Words {
+		buf = append(buf, '%')
+	}
+	if v.CaseInsensitive {
+		buf = append(buf, '@')
+	}
+	if v.Prefix {
+		buf = append(buf, '*')
+	}
+	return buf
+}
+
+// scanLqueryVariant reads a label and its flags starting at rp.
+func scanLqueryVariant(s string, rp int) (LqueryVariant, int, error) {
+	start := rp
+	for rp < len(s) {
+		r, size := utf8.DecodeRuneInString(s[rp:])
+		if !isLtreeLabelChar(r) {
+			break
+		}
+		rp += size
+	}
+
+	v := LqueryVariant{Label: s[start:rp]}
+	if err := validateLtreeLabel(v.Label); err != nil {
+		return LqueryVariant{}, 0, err
+	}
+
+	for ; rp < len(s); rp++ {
+		switch s[rp] {
+		case '*':
+			v.Prefix = true
+		case '@':
+			v.CaseInsensitive = true
+		case '%':
+			v.Words = true
+		default:
+			return v, rp, nil
+		}
+	}
+	return v, rp, nil
+}
+
+// LqueryLevel is a level of an lquery. A level without Variants is written *
+// and matches any labels. A level with Variants matches a label that matches
+// one of them, or when Not is set, a label that matches none of them. The
+// level matches from Min to Max consecutive labels. Max is at most 65535.
+type LqueryLevel struct {
+	Variants []LqueryVariant
+	Not      bool
+	Min      int
+	Max      int
+}
+
+func (l LqueryLevel) matchLabel(label string) bool {
+	if len(l.Variants) == 0 {
+		return true
+	}
+	for _, v := range l.Variants {
+		if v.Match(label) {
+			return !l.Not
+		}
+	}
+	return l.Not
+}
+
+// Lquery represents a path pattern of the ltree extension, such as
+// *.Science.!Astronomy@.*{1,2}.
+type Lquery struct {
+	Levels []LqueryLevel
+	Status Status
+}
+
+func (dst *Lquery) Set(src interface{}) error {
+	if src == nil {
+		*dst = Lquery{Status: Null}
+		return nil
+	}
+
+	switch value := src.(type) {
+	case Lquery:
+		*dst = value
+	case *Lquery:
+		if value == nil {
+			*dst = Lquery{Status: Null}
+		} else {
+			*dst = *value
+		}
+	case string:
+		return dst.DecodeText(nil, []byte(value))
+	case *string:
+		if value == nil {
+			*dst = Lquery{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	default:
+		if originalSrc, ok := underlyingStringType(src); ok {
+			return dst.Set(originalSrc)
+		}
+		return errors.Errorf("cannot convert %v to Lquery", value)
+	}
+
+	return nil
+}
+
+func (dst Lquery) Get() interface{} {
+	switch dst.Status {
+	case Present:
+		return dst
+	case Null:
+		return nil
+	default:
+		return dst.Status
+	}
+}
+
+func (src *Lquery) AssignTo(dst interface{}) error {
+	switch src.Status {
+	case Present:
+		switch v := dst.(type) {
+		case *Lquery:
+			*v = *src
+			return nil
+		case *string:
+			buf, err := src.EncodeText(nil, nil)
+			if err != nil {
+				return err
+			}
+			*v = string(buf)
+			return nil
+		default:
+			if nextDst, retry := GetAssignToDstType(dst); retry {
+				return src.AssignTo(nextDst)
+			}
+			return errors.Errorf("unable to assign to %T", dst)
+		}
+	case Null:
+		return NullAssignTo(dst)
+	}
+
+	return errors.Errorf("cannot decode %#v into %T", src, dst)
+}
+
+func (dst *Lquery) DecodeText(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Lquery{Status: Null}
+		return nil
+	}
+
+	s := string(src)
+	var levels []LqueryLevel
+	for _, part := range strings.Split(s, ".") {
+		level, err := parseLqueryLevel(part)
+		if err != nil {
+			return errors.Errorf("lquery syntax error: %q: %w", s, err)
+		}
+		levels = append(levels, level)
+	}
+	if len(levels) > ltreeMaxLevels {
+		return errors.Errorf("number of lquery items (%d) exceeds the maximum allowed (%d)", len(levels), ltreeMaxLevels)
+	}
+
+	*dst = Lquery{Levels: levels, Status: Present}
+	return nil
+}
+
+func parseLqueryLevel(s string) (LqueryLevel, error) {
+	if s == "" {
+		return LqueryLevel{}, errors.New("empty level")
+	}
+
+	level := LqueryLevel{Min: 1, Max: 1}
+	rp := 0
+
+	if s[0] == '*' {
+		level = LqueryLevel{Min: 0, Max: ltreeMaxLevels}
+		rp = 1
+	} else {
+		if s[0] == '!' {
+			level.Not = true
+			rp = 1
+		}
+		for {
+			if rp >= len(s) {
+				return LqueryLevel{}, errors.New("missing label")
+			}
+			v, next, err := scanLqueryVariant(s, rp)
+			if err != nil {
+				return LqueryLevel{}, err
+			}
+			level.Variants = append(level.Variants, v)
+			rp = next
+			if rp >= len(s) || s[rp] != '|' {
+				break
+			}
+			rp++
+		}
+	}
+
+	if rp < len(s) && s[rp] == '{' {
+		end := strings.IndexByte(s[rp:], '}')
+		if end < 0 {
+			return LqueryLevel{}, errors.New("unterminated quantifier")
+		}
+		var err error
+		level.Min, level.Max, err = parseLqueryQuantifier(s[rp+1 : rp+end])
+		if err != nil {
+			return LqueryLevel{}, err
+		}
+		rp += end + 1
+	}
+
+	if rp != len(s) {
+		return LqueryLevel{}, errors.Errorf("unexpected character %q", s[rp])
+	}
+
+	return level, nil
+}
+
+// parseLqueryQuantifier parses n, n,, ,m or n,m.
+func parseLqueryQuantifier(s string) (int, int, error) {
+	parse := func(s string, empty int) (int, error) {
+		if s == "" {
+			return empty, nil
+		}
+		n, err := strconv.ParseUint(s, 10, 32)
+		if err != nil || n > ltreeMaxLevels {
+			return 0, errors.Errorf("quantifier %q must be between 0 and %d", s, ltreeMaxLevels)
+		}
+		return int(n), nil
+	}
+
+	lowText, highText, isRange := strings.Cut(s, ",")
+	if !isRange {
+		if lowText == "" {
+			return 0, 0, errors.New("empty quantifier")
+		}
+		n, err := parse(lowText, 0)
+		return n, n, err
+	}
+
+	low, err := parse(lowText, 0)
+	if err != nil {
+		return 0, 0, err
+	}
+	high, err := parse(highText, ltreeMaxLevels)
+	if err != nil {
+		return 0, 0, err
+	}
+	if low > high {
+		return 0, 0, errors.New("low limit is greater than high limit")
+	}
+	return low, high, nil
+}
+
+func (dst *Lquery) DecodeBinary(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Lquery{Status: Null}
+		return nil
+	}
+
+	text, err := decodeLtreeBinary("lquery", src)
+	if err != nil {
+		return err
+	}
+	return dst.DecodeText(ci, text)
+}
+
+// EncodeText writes src as the server's lquery output does. A quantifier is
+// written for a * level other than {0,} and for a level with variants other
+// than {1}.
+func (src Lquery) EncodeText(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	if len(src.Levels) == 0 {
+		return nil, errors.New("lquery must have at least one level")
+	}
+
+	for i, l := range src.Levels {
+		if l.Min < 0 || l.Max > ltreeMaxLevels || l.Min > l.Max {
+			return nil, errors.Errorf("invalid lquery quantifier {%d,%d}", l.Min, l.Max)
+		}
+		if i > 0 {
+			buf = append(buf, '.')
+		}
+
+		if len(l.Variants) == 0 {
+			buf = append(buf, '*')
+		} else {
+			if l.Not {
+				buf = append(buf, '!')
+			}
+			for j, v := range l.Variants {
+				if err := validateLtreeLabel(v.Label); err != nil {
+					return nil, err
+				}
+				if j > 0 {
+					buf = append(buf, '|')
+				}
+				buf = v.appendText(buf)
+			}
+		}
+
+		switch {
+		case len(l.Variants) == 0 && l.Min == 0 && l.Max == ltreeMaxLevels:
+		case len(l.Variants) > 0 && l.Min == 1 && l.Max == 1:
+		case l.Min == l.Max:
+			buf = append(buf, '{')
+			buf = strconv.AppendInt(buf, int64(l.Min), 10)
+			buf = append(buf, '}')
+		default:
+			buf = append(buf, '{')
+			if l.Min > 0 {
+				buf = strconv.AppendInt(buf, int64(l.Min), 10)
+			}
+			buf = append(buf, ',')
+			if l.Max < ltreeMaxLevels {
+				buf = strconv.AppendInt(buf, int64(l.Max), 10)
+			}
+			buf = append(buf, '}')
+		}
+	}
+
+	return buf, nil
+}
+
+func (src Lquery) EncodeBinary(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	return src.EncodeText(ci, append(buf, ltreeBinaryVersion))
+}
+
+// Scan implements the database/sql Scanner interface.
+func (dst *Lquery) Scan(src interface{}) error {
+	if src == nil {
+		*dst = Lquery{Status: Null}
+		return nil
+	}
+
+	switch src := src.(type) {
+	case string:
+		return dst.DecodeText(nil, []byte(src))
+	case []byte:
+		srcCopy := make([]byte, len(src))
+		copy(srcCopy, src)
+		return dst.DecodeText(nil, srcCopy)
+	}
+
+	return errors.Errorf("cannot scan %T", src)
+}
+
+// Value implements the database/sql/driver Valuer interface.
+func (src Lquery) Value() (driver.Value, error) {
+	return EncodeValueText(src)
+}
+
+// MarshalJSON encodes src as a JSON string of its text form.
+func (src Lquery) MarshalJSON() ([]byte, error) {
+	switch src.Status {
+	case Present:
+		buf, err := src.EncodeText(nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(string(buf))
+	case Null:
+		return []byte("null"), nil
+	}
+
+	return nil, errBadStatus
+}
+
+func (dst *Lquery) UnmarshalJSON(b []byte) error {
+	var s *string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		return err
+	}
+
+	return dst.Set(s)
+}
+
+// Match reports whether path matches src, as the server's ~ operator does.
+func (src Lquery) Match(path Ltree) bool {
+	if src.Status != Present || path.Status != Present {
+		return false
+	}
+	return matchLqueryLevels(src.Levels, path.Labels)
+}
+
+// matchLqueryLevels matches levels against labels with backtracking, as the
+// server's checkCond does.
+func matchLqueryLevels(levels []LqueryLevel, labels []string) bool {
+	for len(levels) > 0 {
+		l := levels[0]
+		levels = levels[1:]
+
+		high := l.Max
+		if high > len(labels) {
+			high = len(labels)
+		}
+		if high < l.Min {
+			return false
+		}
+
+		for matched := 0; matched < high; matched++ {
+			if matched >= l.Min && matchLqueryLevels(levels, labels) {
+				return true
+			}
+			if !l.matchLabel(labels[0]) {
+				return false
+			}
+			labels = labels[1:]
+		}
+	}
+
+	return len(labels) == 0
+}
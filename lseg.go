@@ -0,0 +1,232 @@
+package tstype
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"math"
+
+	"github.com/jackc/pgtype"
+
+	errors "golang.org/x/xerrors"
+)
+
+// Lseg represents the PostgreSQL lseg type, the line segment from P[0] to
+// P[1]. JSON is a GeoJSON LineString.
+type Lseg struct {
+	P      [2]pgtype.Vec2
+	Status Status
+}
+
+func (dst *Lseg) Set(src interface{}) error {
+	if src == nil {
+		*dst = Lseg{Status: Null}
+		return nil
+	}
+
+	switch value := src.(type) {
+	case Lseg:
+		*dst = value
+	case *Lseg:
+		if value == nil {
+			*dst = Lseg{Status: Null}
+		} else {
+			*dst = *value
+		}
+	case [2]pgtype.Vec2:
+		*dst = Lseg{P: value, Status: Present}
+	case string:
+		return dst.DecodeText(nil, []byte(value))
+	case *string:
+		if value == nil {
+			*dst = Lseg{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	default:
+		if originalSrc, ok := underlyingStringType(src); ok {
+			return dst.Set(originalSrc)
+		}
+		return errors.Errorf("cannot convert %v to Lseg", value)
+	}
+
+	return nil
+}
+
+func (dst Lseg) Get() interface{} {
+	switch dst.Status {
+	case Present:
+		return dst
+	case Null:
+		return nil
+	default:
+		return dst.Status
+	}
+}
+
+func (src *Lseg) AssignTo(dst interface{}) error {
+	switch src.Status {
+	case Present:
+		switch v := dst.(type) {
+		case *Lseg:
+			*v = *src
+			return nil
+		case *[2]pgtype.Vec2:
+			*v = src.P
+			return nil
+		case *string:
+			buf, err := src.EncodeText(nil, nil)
+			if err != nil {
+				return err
+			}
+			*v = string(buf)
+			return nil
+		default:
+			if nextDst, retry := GetAssignToDstType(dst); retry {
+				return src.AssignTo(nextDst)
+			}
+			return errors.Errorf("unable to assign to %T", dst)
+		}
+	case Null:
+		return NullAssignTo(dst)
+	}
+
+	return errors.Errorf("cannot decode %#v into %T", src, dst)
+}
+
+// DecodeText accepts [(x1,y1),(x2,y2)], ((x1,y1),(x2,y2)), (x1,y1),(x2,y2)
+// and x1,y1,x2,y2.
+func (dst *Lseg) DecodeText(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Lseg{Status: Null}
+		return nil
+	}
+
+	p := geoParser{typeName: "lseg", s: string(src)}
+	points, _, err := p.parsePoints(2, true)
+	if err != nil {
+		return err
+	}
+	if err := p.end(); err != nil {
+		return err
+	}
+
+	*dst = Lseg{P: [2]pgtype.Vec2{points[0], points[1]}, Status: Present}
+	return nil
+}
+
+func (dst *Lseg) DecodeBinary(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Lseg{Status: Null}
+		return nil
+	}
+
+	fs, err := readFloat8s("lseg", src, 4)
+	if err != nil {
+		return err
+	}
+
+	*dst = Lseg{
+		P:      [2]pgtype.Vec2{{X: fs[0], Y: fs[1]}, {X: fs[2], Y: fs[3]}},
+		Status: Present,
+	}
+	return nil
+}
+
+func (src Lseg) EncodeText(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	buf = append(buf, '[')
+	buf = appendVec2sText(buf, src.P[:])
+	return append(buf, ']'), nil
+}
+
+func (src Lseg) EncodeBinary(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	buf = appendVec2Binary(buf, src.P[0])
+	return appendVec2Binary(buf, src.P[1]), nil
+}
+
+// Scan implements the database/sql Scanner interface.
+func (dst *Lseg) Scan(src interface{}) error {
+	if src == nil {
+		*dst = Lseg{Status: Null}
+		return nil
+	}
+
+	switch src := src.(type) {
+	case string:
+		return dst.DecodeText(nil, []byte(src))
+	case []byte:
+		srcCopy := make([]byte, len(src))
+		copy(srcCopy, src)
+		return dst.DecodeText(nil, srcCopy)
+	}
+
+	return errors.Errorf("cannot scan %T", src)
+}
+
+// Value implements the database/sql/driver Valuer interface.
+func (src Lseg) Value() (driver.Value, error) {
+	return EncodeValueText(src)
+}
+
+func (src Lseg) MarshalJSON() ([]byte, error) {
+	switch src.Status {
+	case Present:
+		return marshalGeoJSON("LineString", geoJSONPositions(src.P[:]))
+	case Null:
+		return []byte("null"), nil
+	}
+
+	return nil, errBadStatus
+}
+
+func (dst *Lseg) UnmarshalJSON(b []byte) error {
+	g, ok, err := unmarshalGeoJSON(b)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		*dst = Lseg{Status: Null}
+		return nil
+	}
+	if g.Type != "LineString" {
+		return unsupportedGeoJSON("Lseg", g)
+	}
+
+	var positions []geoJSONPosition
+	if err := json.Unmarshal(g.Coordinates, &positions); err != nil {
+		return err
+	}
+	if len(positions) != 2 {
+		return errors.Errorf("cannot unmarshal GeoJSON LineString of %d positions into Lseg", len(positions))
+	}
+
+	*dst = Lseg{
+		P:      [2]pgtype.Vec2{pgtype.Vec2(positions[0]), pgtype.Vec2(positions[1])},
+		Status: Present,
+	}
+	return nil
+}
+
+// Distance returns the distance from p to the nearest point of src, as the
+// server's <-> operator does. It is NaN if either is not Present.
+func (src Lseg) Distance(p Point) float64 {
+	if src.Status != Present || p.Status != Present {
+		return math.NaN()
+	}
+	return lsegDistance(src.P[0], src.P[1], p.P)
+}
@@ -0,0 +1,346 @@
+package tstype
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/jackc/pgtype"
+
+	errors "golang.org/x/xerrors"
+)
+
+// Limits of the ltree extension.
+const (
+	ltreeMaxLevels        = 65535
+	ltreeLabelMaxChars    = 1000
+	ltreeBinaryVersion    = 1
+	ltreeLabelPunctuation = "_-"
+)
+
+// isLtreeLabelChar reports whether r may appear in an ltree label: a letter,
+// a digit, an underscore or a hyphen.
+func isLtreeLabelChar(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune(ltreeLabelPunctuation, r)
+}
+
+func validateLtreeLabel(label string) error {
+	if label == "" {
+		return errors.New("ltree label cannot be empty")
+	}
+	if n := utf8.RuneCountInString(label); n > ltreeLabelMaxChars {
+		return errors.Errorf("ltree label is too long: %d characters, maximum is %d", n, ltreeLabelMaxChars)
+	}
+	for _, r := range label {
+		if !isLtreeLabelChar(r) {
+			return errors.Errorf("ltree label %q contains invalid character %q", label, r)
+		}
+	}
+	return nil
+}
+
+// decodeLtreeBinary returns the text of the binary format shared by ltree,
+// lquery and ltxtquery: a version byte followed by the text format.
+func decodeLtreeBinary(typeName string, src []byte) ([]byte, error) {
+	if len(src) == 0 {
+		return nil, errors.Errorf("%s too short", typeName)
+	}
+	if src[0] != ltreeBinaryVersion {
+		return nil, errors.Errorf("unknown %s version number %d", typeName, src[0])
+	}
+	return src[1:], nil
+}
+
+// Ltree represents a label path of the ltree extension, such as
+// Top.Science.Astronomy. An empty path has no labels.
+type Ltree struct {
+	Labels []string
+	Status Status
+}
+
+func (dst *Ltree) Set(src interface{}) error {
+	if src == nil {
+		*dst = Ltree{Status: Null}
+		return nil
+	}
+
+	switch value := src.(type) {
+	case Ltree:
+		*dst = value
+	case *Ltree:
+		if value == nil {
+			*dst = Ltree{Status: Null}
+		} else {
+			*dst = *value
+		}
+	case []string:
+		if value == nil {
+			*dst = Ltree{Status: Null}
+			return nil
+		}
+		if len(value) > ltreeMaxLevels {
+			return errors.Errorf("number of ltree labels (%d) exceeds the maximum allowed (%d)", len(value), ltreeMaxLevels)
+		}
+		for _, label := range value {
+			if err := validateLtreeLabel(label); err != nil {
+				return err
+			}
+		}
+		labels := make([]string, len(value))
+		copy(labels, value)
+		*dst = Ltree{Labels: labels, Status: Present}
+	case string:
+		return dst.DecodeText(nil, []byte(value))
+	case *string:
+		if value == nil {
+			*dst = Ltree{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	default:
+		if originalSrc, ok := underlyingStringType(src); ok {
+			return dst.Set(originalSrc)
+		}
+		return errors.Errorf("cannot convert %v to Ltree", value)
+	}
+
+	return nil
+}
+
+func (dst Ltree) Get() interface{} {
+	switch dst.Status {
+	case Present:
+		return dst
+	case Null:
+		return nil
+	default:
+		return dst.Status
+	}
+}
+
+func (src *Ltree) AssignTo(dst interface{}) error {
+	switch src.Status {
+	case Present:
+		switch v := dst.(type) {
+		case *Ltree:
+			*v = *src
+			return nil
+		case *[]string:
+			*v = make([]string, len(src.Labels))
+			copy(*v, src.Labels)
+			return nil
+		case *string:
+			*v = strings.Join(src.Labels, ".")
+			return nil
+		default:
+			if nextDst, retry := GetAssignToDstType(dst); retry {
+				return src.AssignTo(nextDst)
+			}
+			return errors.Errorf("unable to assign to %T", dst)
+		}
+	case Null:
+		return NullAssignTo(dst)
+	}
+
+	return errors.Errorf("cannot decode %#v into %T", src, dst)
+}
+
+func (dst *Ltree) DecodeText(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Ltree{Status: Null}
+		return nil
+	}
+
+	if len(src) == 0 {
+		*dst = Ltree{Status: Present}
+		return nil
+	}
+
+	return dst.Set(strings.Split(string(src), "."))
+}
+
+func (dst *Ltree) DecodeBinary(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Ltree{Status: Null}
+		return nil
+	}
+
+	text, err := decodeLtreeBinary("ltree", src)
+	if err != nil {
+		return err
+	}
+	return dst.DecodeText(ci, text)
+}
+
+func (src Ltree) EncodeText(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	if buf == nil {
+		buf = []byte{}
+	}
+	for i, label := range src.Labels {
+		if err := validateLtreeLabel(label); err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			buf = append(buf, '.')
+		}
+		buf = append(buf, label...)
+	}
+
+	return buf, nil
+}
+
+func (src Ltree) EncodeBinary(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	return src.EncodeText(ci, append(buf, ltreeBinaryVersion))
+}
+
+// Scan implements the database/sql Scanner interface.
+func (dst *Ltree) Scan(src interface{}) error {
+	if src == nil {
+		*dst = Ltree{Status: Null}
+		return nil
+	}
+
+	switch src := src.(type) {
+	case string:
+		return dst.DecodeText(nil, []byte(src))
+	case []byte:
+		srcCopy := make([]byte, len(src))
+		copy(srcCopy, src)
+		return dst.DecodeText(nil, srcCopy)
+	}
+
+	return errors.Errorf("cannot scan %T", src)
+}
+
+// Value implements the database/sql/driver Valuer interface.
+func (src Ltree) Value() (driver.Value, error) {
+	return EncodeValueText(src)
+}
+
+// MarshalJSON encodes src as a JSON string of its text form.
+func (src Ltree) MarshalJSON() ([]byte, error) {
+	switch src.Status {
+	case Present:
+		return json.Marshal(strings.Join(src.Labels, "."))
+	case Null:
+		return []byte("null"), nil
+	}
+
+	return nil, errBadStatus
+}
+
+func (dst *Ltree) UnmarshalJSON(b []byte) error {
+	var s *string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		return err
+	}
+
+	return dst.Set(s)
+}
+
+// NLevel returns the number of labels in src, as the server's nlevel does.
+func (src Ltree) NLevel() int {
+	return len(src.Labels)
+}
+
+// IsAncestorOf reports whether src is an ancestor of other or equal to it, as
+// the server's @> operator does.
+func (src Ltree) IsAncestorOf(other Ltree) bool {
+	if src.Status != Present || other.Status != Present || len(src.Labels) > len(other.Labels) {
+		return false
+	}
+	for i := range src.Labels {
+		if src.Labels[i] != other.Labels[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// IsDescendantOf reports whether src is a descendant of other or equal to it,
+// as the server's <@ operator does.
+func (src Ltree) IsDescendantOf(other Ltree) bool {
+	return other.IsAncestorOf(src)
+}
+
+// Subltree returns the labels of src from position start up to but not
+// including end, counting from 0, as the server's subltree does. end is
+// lowered to NLevel. It returns an error if start is not a position of src or
+// is greater than end.
+func (src Ltree) Subltree(start, end int) (Ltree, error) {
+	if src.Status != Present {
+		return Ltree{Status: Null}, nil
+	}
+
+	if start < 0 || end < 0 || start >= len(src.Labels) || start > end {
+		return Ltree{}, errors.New("invalid positions")
+	}
+	if end > len(src.Labels) {
+		end = len(src.Labels)
+	}
+
+	labels := make([]string, end-start)
+	copy(labels, src.Labels[start:end])
+	return Ltree{Labels: labels, Status: Present}, nil
+}
+
+// Subpath returns length labels of src starting at offset, as the server's
+// subpath does. A negative offset counts from the end of src and a negative
+// length leaves that many labels off the end.
+func (src Ltree) Subpath(offset, length int) (Ltree, error) {
+	if offset < 0 {
+		offset += len(src.Labels)
+	}
+
+	end := offset + length
+	if length < 0 {
+		end = len(src.Labels) + length
+	}
+
+	return src.Subltree(offset, end)
+}
+
+// Lca returns the longest common ancestor of src and others, as the server's
+// lca does. The result is always shorter than each path, so the lca of A.B
+// and A.B.C is A. It is Null if any path is Null or empty.
+func (src Ltree) Lca(others ...Ltree) Ltree {
+	if src.Status != Present || len(src.Labels) == 0 {
+		return Ltree{Status: Null}
+	}
+
+	n := len(src.Labels) - 1
+	for _, other := range others {
+		if other.Status != Present || len(other.Labels) == 0 {
+			return Ltree{Status: Null}
+		}
+
+		common := 0
+		for common < n && common < len(other.Labels)-1 && src.Labels[common] == other.Labels[common] {
+			common++
+		}
+		n = common
+	}
+
+	labels := make([]string, n)
+	copy(labels, src.Labels[:n])
+	return Ltree{Labels: labels, Status: Present}
+}
@@ -0,0 +1,174 @@
+package tstype_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/tossp/tstype"
+)
+
+func mustLtree(t *testing.T, s string) tstype.Ltree {
+	t.Helper()
+	var l tstype.Ltree
+	if err := l.DecodeText(nil, []byte(s)); err != nil {
+		t.Fatalf("%q: %v", s, err)
+	}
+	return l
+}
+
+func ltreeText(l tstype.Ltree) string {
+	buf, _ := l.EncodeText(nil, nil)
+	return string(buf)
+}
+
+func TestLtreeCodecs(t *testing.T) {
+	src := mustLtree(t, "Top.Science.Astro_nomy")
+	buf, err := src.EncodeBinary(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(buf) != "\x01Top.Science.Astro_nomy" {
+		t.Errorf("EncodeBinary: got %q", buf)
+	}
+	var dst tstype.Ltree
+	if err := dst.DecodeBinary(nil, buf); err != nil || dst.NLevel() != 3 {
+		t.Errorf("DecodeBinary: got %+v, %v", dst, err)
+	}
+	if err := dst.DecodeBinary(nil, []byte("\x02Top")); err == nil {
+		t.Error("DecodeBinary: expected error for unknown version")
+	}
+
+	if empty := mustLtree(t, ""); empty.Status != tstype.Present || empty.NLevel() != 0 {
+		t.Errorf("empty: got %+v", empty)
+	}
+
+	for i, s := range []string{"Top..Science", "Top.", "a b", "Top.Sci$ence"} {
+		if err := dst.DecodeText(nil, []byte(s)); err == nil {
+			t.Errorf("%d: %q: expected error", i, s)
+		}
+	}
+
+	js, _ := json.Marshal(src)
+	if string(js) != `"Top.Science.Astro_nomy"` {
+		t.Errorf("MarshalJSON: got %s", js)
+	}
+}
+
+func TestLtreeOperators(t *testing.T) {
+	l := func(s string) tstype.Ltree { return mustLtree(t, s) }
+
+	if !l("Top.Science").IsAncestorOf(l("Top.Science.Astronomy")) || !l("Top").IsAncestorOf(l("Top")) || l("Top.Sci").IsAncestorOf(l("Top.Science")) {
+		t.Error("IsAncestorOf")
+	}
+	if !l("Top.Science.Astronomy").IsDescendantOf(l("Top")) || l("Top").IsDescendantOf(l("Top.Science")) {
+		t.Error("IsDescendantOf")
+	}
+
+	subpaths := []struct {
+		offset, length int
+		expected       string
+	}{
+		{0, 2, "Top.Child1"},
+		{1, 2, "Child1.Child2"},
+		{-2, 1, "Child1"},
+		{0, -1, "Top.Child1"},
+		{2, 10, "Child2"},
+	}
+	for i, tt := range subpaths {
+		sub, err := l("Top.Child1.Child2").Subpath(tt.offset, tt.length)
+		if err != nil || ltreeText(sub) != tt.expected {
+			t.Errorf("%d: Subpath(%d, %d): expected %q, got %q, %v", i, tt.offset, tt.length, tt.expected, ltreeText(sub), err)
+		}
+	}
+	if _, err := l("Top.Child1").Subpath(5, 1); err == nil {
+		t.Error("Subpath: expected error")
+	}
+
+	if lca := l("1.2.3").Lca(l("1.2.3.4.5.6")); ltreeText(lca) != "1.2" {
+		t.Errorf("Lca: got %q", ltreeText(lca))
+	}
+	if lca := l("1.2.2.3").Lca(l("1.2.3.4.5.6"), l("1.2.9")); ltreeText(lca) != "1.2" {
+		t.Errorf("Lca: got %q", ltreeText(lca))
+	}
+	if lca := l("1").Lca(l("1.2")); lca.Status != tstype.Present || lca.NLevel() != 0 {
+		t.Errorf("Lca: got %+v", lca)
+	}
+	if lca := l("").Lca(l("1.2")); lca.Status != tstype.Null {
+		t.Errorf("Lca: expected Null, got %+v", lca)
+	}
+}
+
+func TestLqueryMatch(t *testing.T) {
+	tests := []struct {
+		query    string
+		text     string
+		path     string
+		expected bool
+	}{
+		{query: "*.Astronomy.*", text: "*.Astronomy.*", path: "Top.Science.Astronomy", expected: true},
+		{query: "*.Astronomy.*", text: "*.Astronomy.*", path: "Top.Science.Astronomy.Stars", expected: true},
+		{query: "*.!Astronomy.*", text: "*.!Astronomy.*", path: "Astronomy", expected: false},
+		{query: "Top.*{0,1}.Astronomy", text: "Top.*{,1}.Astronomy", path: "Top.Science.Astronomy", expected: true},
+		{query: "Top.*{2}", text: "Top.*{2}", path: "Top.Science.Astronomy", expected: true},
+		{query: "Top.*{2}", text: "Top.*{2}", path: "Top.Science", expected: false},
+		{query: "top@.Sci*.*", text: "top@.Sci*.*", path: "Top.Science.Astronomy", expected: true},
+		{query: "*.Astro*|Hobbies", text: "*.Astro*|Hobbies", path: "Top.Hobbies", expected: true},
+		{query: "*.star_galaxy%", text: "*.star_galaxy%", path: "Top.galaxy_dwarf_star", expected: true},
+		{query: "*.star_gal%*", text: "*.star_gal%*", path: "Top.galaxy_dwarf_star", expected: true},
+		{query: "*.star_gal%", text: "*.star_gal%", path: "Top.galaxy_dwarf_star", expected: false},
+		{query: "Top.Science{1,}", text: "Top.Science{1,}", path: "Top.Science.Science", expected: true},
+		{query: "*{3,}", text: "*{3,}", path: "a.b", expected: false},
+	}
+
+	for i, tt := range tests {
+		var q tstype.Lquery
+		if err := q.DecodeText(nil, []byte(tt.query)); err != nil {
+			t.Errorf("%d: %q: %v", i, tt.query, err)
+			continue
+		}
+		if got := q.Match(mustLtree(t, tt.path)); got != tt.expected {
+			t.Errorf("%d: %q ~ %q: expected %v", i, tt.path, tt.query, tt.expected)
+		}
+		buf, err := q.EncodeText(nil, nil)
+		if err != nil || string(buf) != tt.text {
+			t.Errorf("%d: EncodeText: expected %q, got %q, %v", i, tt.text, buf, err)
+		}
+	}
+
+	for i, s := range []string{"", "a..b", "*{2,1}", "a{", "!", "a|", "*x"} {
+		var q tstype.Lquery
+		if err := q.DecodeText(nil, []byte(s)); err == nil {
+			t.Errorf("%d: %q: expected error", i, s)
+		}
+	}
+}
+
+func TestLtxtqueryMatch(t *testing.T) {
+	tests := []struct {
+		query    string
+		text     string
+		path     string
+		expected bool
+	}{
+		{query: "Europe & Russia*@ & !Transportation", text: "Europe & Russia@* & !Transportation", path: "Top.Europe.RUSSIAN_federation", expected: true},
+		{query: "Europe & Russia*@ & !Transportation", text: "Europe & Russia@* & !Transportation", path: "Top.Europe.Russia.Transportation", expected: false},
+		{query: "a & (b | c)", text: "a & ( b | c )", path: "x.a.c", expected: true},
+		{query: "!(a | b)", text: "!( a | b )", path: "x.b", expected: false},
+		{query: "a | b & c", text: "a | b & c", path: "x.a", expected: true},
+	}
+
+	for i, tt := range tests {
+		var q tstype.Ltxtquery
+		if err := q.DecodeText(nil, []byte(tt.query)); err != nil {
+			t.Errorf("%d: %q: %v", i, tt.query, err)
+			continue
+		}
+		if got := q.Match(mustLtree(t, tt.path)); got != tt.expected {
+			t.Errorf("%d: %q @ %q: expected %v", i, tt.path, tt.query, tt.expected)
+		}
+		buf, err := q.EncodeText(nil, nil)
+		if err != nil || string(buf) != tt.text {
+			t.Errorf("%d: EncodeText: expected %q, got %q, %v", i, tt.text, buf, err)
+		}
+	}
+}
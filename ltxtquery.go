@@ -0,0 +1,390 @@
+package tstype
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+
+	"github.com/jackc/pgtype"
+
+	errors "golang.org/x/xerrors"
+)
+
+// LtxtqueryOperator is the kind of an LtxtqueryNode.
+type LtxtqueryOperator byte
+
+const (
+	LtxtqueryOperand LtxtqueryOperator = iota
+	LtxtqueryNot
+	LtxtqueryAnd
+	LtxtqueryOr
+)
+
+// LtxtqueryNode is a node of an ltxtquery. An operand has Operand set and
+// matches a path with any label that matches it. LtxtqueryNot has its operand
+// in Left. LtxtqueryAnd and LtxtqueryOr have both Left and Right.
+type LtxtqueryNode struct {
+	Operator LtxtqueryOperator
+	Operand  LqueryVariant
+	Left     *LtxtqueryNode
+	Right    *LtxtqueryNode
+}
+
+func (n *LtxtqueryNode) validate() error {
+	switch n.Operator {
+	case LtxtqueryOperand:
+		return validateLtreeLabel(n.Operand.Label)
+	case LtxtqueryNot:
+		if n.Left == nil {
+			return errors.New("ltxtquery NOT requires an operand")
+		}
+		return n.Left.validate()
+	case LtxtqueryAnd, LtxtqueryOr:
+		if n.Left == nil || n.Right == nil {
+			return errors.New("ltxtquery operator requires two operands")
+		}
+		if err := n.Left.validate(); err != nil {
+			return err
+		}
+		return n.Right.validate()
+	}
+	return errors.Errorf("unknown ltxtquery operator %d", n.Operator)
+}
+
+func (n *LtxtqueryNode) match(labels []string) bool {
+	switch n.Operator {
+	case LtxtqueryOperand:
+		for _, label := range labels {
+			if n.Operand.Match(label) {
+				return true
+			}
+		}
+		return false
+	case LtxtqueryNot:
+		return !n.Left.match(labels)
+	case LtxtqueryAnd:
+		return n.Left.match(labels) && n.Right.match(labels)
+	case LtxtqueryOr:
+		return n.Left.match(labels) || n.Right.match(labels)
+	}
+	return false
+}
+
+// Ltxtquery represents a full-text-like label query of the ltree extension,
+// such as Europe & Russia*@ & !Transportation.
+type Ltxtquery struct {
+	Root   *LtxtqueryNode
+	Status Status
+}
+
+func (dst *Ltxtquery) Set(src interface{}) error {
+	if src == nil {
+		*dst = Ltxtquery{Status: Null}
+		return nil
+	}
+
+	switch value := src.(type) {
+	case Ltxtquery:
+		*dst = value
+	case *Ltxtquery:
+		if value == nil {
+			*dst = Ltxtquery{Status: Null}
+		} else {
+			*dst = *value
+		}
+	case *LtxtqueryNode:
+		if value == nil {
+			*dst = Ltxtquery{Status: Null}
+			return nil
+		}
+		if err := value.validate(); err != nil {
+			return err
+		}
+		*dst = Ltxtquery{Root: value, Status: Present}
+	case string:
+		return dst.DecodeText(nil, []byte(value))
+	case *string:
+		if value == nil {
+			*dst = Ltxtquery{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	default:
+		if originalSrc, ok := underlyingStringType(src); ok {
+			return dst.Set(originalSrc)
+		}
+		return errors.Errorf("cannot convert %v to Ltxtquery", value)
+	}
+
+	return nil
+}
+
+func (dst Ltxtquery) Get() interface{} {
+	switch dst.Status {
+	case Present:
+		return dst
+	case Null:
+		return nil
+	default:
+		return dst.Status
+	}
+}
+
+func (src *Ltxtquery) AssignTo(dst interface{}) error {
+	switch src.Status {
+	case Present:
+		switch v := dst.(type) {
+		case *Ltxtquery:
+			*v = *src
+			return nil
+		case *string:
+			buf, err := src.EncodeText(nil, nil)
+			if err != nil {
+				return err
+			}
+			*v = string(buf)
+			return nil
+		default:
+			if nextDst, retry := GetAssignToDstType(dst); retry {
+				return src.AssignTo(nextDst)
+			}
+			return errors.Errorf("unable to assign to %T", dst)
+		}
+	case Null:
+		return NullAssignTo(dst)
+	}
+
+	return errors.Errorf("cannot decode %#v into %T", src, dst)
+}
+
+// DecodeText parses the ltxtquery text format. ! binds tighter than &, which
+// binds tighter than |.
+func (dst *Ltxtquery) DecodeText(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Ltxtquery{Status: Null}
+		return nil
+	}
+
+	p := ltxtqueryParser{s: string(src)}
+	root, err := p.parseOr()
+	if err != nil {
+		return errors.Errorf("ltxtquery syntax error: %q: %w", p.s, err)
+	}
+	if p.skipSpace(); p.rp != len(p.s) {
+		return errors.Errorf("ltxtquery syntax error: %q", p.s)
+	}
+
+	*dst = Ltxtquery{Root: root, Status: Present}
+	return nil
+}
+
+type ltxtqueryParser struct {
+	s  string
+	rp int
+}
+
+func (p *ltxtqueryParser) skipSpace() {
+	p.rp = skipTsSpace(p.s, p.rp)
+}
+
+func (p *ltxtqueryParser) parseOr() (*LtxtqueryNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.skipSpace(); p.rp < len(p.s) && p.s[p.rp] == '|'; p.skipSpace() {
+		p.rp++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &LtxtqueryNode{Operator: LtxtqueryOr, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *ltxtqueryParser) parseAnd() (*LtxtqueryNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.skipSpace(); p.rp < len(p.s) && p.s[p.rp] == '&'; p.skipSpace() {
+		p.rp++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &LtxtqueryNode{Operator: LtxtqueryAnd, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *ltxtqueryParser) parseUnary() (*LtxtqueryNode, error) {
+	p.skipSpace()
+	if p.rp >= len(p.s) {
+		return nil, errors.New("unexpected end of input")
+	}
+
+	switch p.s[p.rp] {
+	case '!':
+		p.rp++
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &LtxtqueryNode{Operator: LtxtqueryNot, Left: operand}, nil
+	case '(':
+		p.rp++
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if p.rp >= len(p.s) || p.s[p.rp] != ')' {
+			return nil, errors.New("missing closing parenthesis")
+		}
+		p.rp++
+		return n, nil
+	}
+
+	v, next, err := scanLqueryVariant(p.s, p.rp)
+	if err != nil {
+		return nil, err
+	}
+	p.rp = next
+	return &LtxtqueryNode{Operator: LtxtqueryOperand, Operand: v}, nil
+}
+
+func (dst *Ltxtquery) DecodeBinary(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Ltxtquery{Status: Null}
+		return nil
+	}
+
+	text, err := decodeLtreeBinary("ltxtquery", src)
+	if err != nil {
+		return err
+	}
+	return dst.DecodeText(ci, text)
+}
+
+// EncodeText writes src as the server's ltxtquery output does, which
+// parenthesizes every | below the top of the query.
+func (src Ltxtquery) EncodeText(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	if src.Root == nil {
+		return nil, errors.New("ltxtquery cannot be empty")
+	}
+	if err := src.Root.validate(); err != nil {
+		return nil, err
+	}
+
+	return appendLtxtqueryNodeText(buf, src.Root, true), nil
+}
+
+func appendLtxtqueryNodeText(buf []byte, n *LtxtqueryNode, first bool) []byte {
+	switch n.Operator {
+	case LtxtqueryOperand:
+		return n.Operand.appendText(buf)
+	case LtxtqueryNot:
+		buf = append(buf, '!')
+		if n.Left.Operator == LtxtqueryOperand {
+			return appendLtxtqueryNodeText(buf, n.Left, false)
+		}
+		buf = append(buf, "( "...)
+		buf = appendLtxtqueryNodeText(buf, n.Left, true)
+		return append(buf, " )"...)
+	}
+
+	parens := n.Operator == LtxtqueryOr && !first
+	if parens {
+		buf = append(buf, "( "...)
+	}
+	buf = appendLtxtqueryNodeText(buf, n.Left, false)
+	if n.Operator == LtxtqueryOr {
+		buf = append(buf, " | "...)
+	} else {
+		buf = append(buf, " & "...)
+	}
+	buf = appendLtxtqueryNodeText(buf, n.Right, false)
+	if parens {
+		buf = append(buf, " )"...)
+	}
+	return buf
+}
+
+func (src Ltxtquery) EncodeBinary(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	return src.EncodeText(ci, append(buf, ltreeBinaryVersion))
+}
+
+// Scan implements the database/sql Scanner interface.
+func (dst *Ltxtquery) Scan(src interface{}) error {
+	if src == nil {
+		*dst = Ltxtquery{Status: Null}
+		return nil
+	}
+
+	switch src := src.(type) {
+	case string:
+		return dst.DecodeText(nil, []byte(src))
+	case []byte:
+		srcCopy := make([]byte, len(src))
+		copy(srcCopy, src)
+		return dst.DecodeText(nil, srcCopy)
+	}
+
+	return errors.Errorf("cannot scan %T", src)
+}
+
+// Value implements the database/sql/driver Valuer interface.
+func (src Ltxtquery) Value() (driver.Value, error) {
+	return EncodeValueText(src)
+}
+
+// MarshalJSON encodes src as a JSON string of its text form.
+func (src Ltxtquery) MarshalJSON() ([]byte, error) {
+	switch src.Status {
+	case Present:
+		buf, err := src.EncodeText(nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(string(buf))
+	case Null:
+		return []byte("null"), nil
+	}
+
+	return nil, errBadStatus
+}
+
+func (dst *Ltxtquery) UnmarshalJSON(b []byte) error {
+	var s *string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		return err
+	}
+
+	return dst.Set(s)
+}
+
+// Match reports whether path matches src, as the server's @ operator does.
+func (src Ltxtquery) Match(path Ltree) bool {
+	if src.Status != Present || path.Status != Present || src.Root == nil {
+		return false
+	}
+	return src.Root.match(path.Labels)
+}
@@ -0,0 +1,327 @@
+package tstype
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgtype"
+
+	errors "golang.org/x/xerrors"
+)
+
+// Macaddr represents the PostgreSQL macaddr type, a 6 byte MAC address.
+type Macaddr struct {
+	Addr   net.HardwareAddr
+	Status Status
+}
+
+func (dst *Macaddr) Set(src interface{}) error {
+	if src == nil {
+		*dst = Macaddr{Status: Null}
+		return nil
+	}
+
+	if value, ok := src.(interface{ Get() interface{} }); ok {
+		value2 := value.Get()
+		if value2 != value {
+			return dst.Set(value2)
+		}
+	}
+
+	switch value := src.(type) {
+	case net.HardwareAddr:
+		if value == nil {
+			*dst = Macaddr{Status: Null}
+			return nil
+		}
+		if len(value) != 6 {
+			return errors.Errorf("cannot convert %v to Macaddr: must be 6 bytes", value)
+		}
+		addr := make(net.HardwareAddr, len(value))
+		copy(addr, value)
+		*dst = Macaddr{Addr: addr, Status: Present}
+	case string:
+		return dst.DecodeText(nil, []byte(value))
+	case *net.HardwareAddr:
+		if value == nil {
+			*dst = Macaddr{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	case *string:
+		if value == nil {
+			*dst = Macaddr{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	default:
+		if originalSrc, ok := underlyingPtrType(src); ok {
+			return dst.Set(originalSrc)
+		}
+		return errors.Errorf("cannot convert %v to Macaddr", value)
+	}
+
+	return nil
+}
+
+func (dst Macaddr) Get() interface{} {
+	switch dst.Status {
+	case Present:
+		return dst.Addr
+	case Null:
+		return nil
+	default:
+		return dst.Status
+	}
+}
+
+func (src *Macaddr) AssignTo(dst interface{}) error {
+	switch src.Status {
+	case Present:
+		return assignHardwareAddrTo(src.Addr, dst)
+	case Null:
+		return NullAssignTo(dst)
+	}
+
+	return errors.Errorf("cannot decode %#v into %T", src, dst)
+}
+
+// DecodeText accepts the input formats the server accepts for macaddr:
+// "08:00:2b:01:02:03", "08-00-2b-01-02-03", "08002b:010203", "08002b-010203",
+// "0800.2b01.0203", "0800-2b01-0203" and "08002b010203".
+func (dst *Macaddr) DecodeText(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Macaddr{Status: Null}
+		return nil
+	}
+
+	addr, err := parseMacaddr6(string(src))
+	if err != nil {
+		return err
+	}
+
+	*dst = Macaddr{Addr: addr, Status: Present}
+	return nil
+}
+
+func (dst *Macaddr) DecodeBinary(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Macaddr{Status: Null}
+		return nil
+	}
+
+	if len(src) != 6 {
+		return errors.Errorf("Received an invalid size for a macaddr: %d", len(src))
+	}
+
+	addr := make(net.HardwareAddr, 6)
+	copy(addr, src)
+
+	*dst = Macaddr{Addr: addr, Status: Present}
+	return nil
+}
+
+func (src Macaddr) EncodeText(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	if len(src.Addr) != 6 {
+		return nil, errors.Errorf("cannot encode %v as macaddr: must be 6 bytes", src.Addr)
+	}
+
+	return append(buf, src.Addr.String()...), nil
+}
+
+func (src Macaddr) EncodeBinary(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	if len(src.Addr) != 6 {
+		return nil, errors.Errorf("cannot encode %v as macaddr: must be 6 bytes", src.Addr)
+	}
+
+	return append(buf, src.Addr...), nil
+}
+
+// Scan implements the database/sql Scanner interface.
+func (dst *Macaddr) Scan(src interface{}) error {
+	if src == nil {
+		*dst = Macaddr{Status: Null}
+		return nil
+	}
+
+	switch src := src.(type) {
+	case string:
+		return dst.DecodeText(nil, []byte(src))
+	case []byte:
+		srcCopy := make([]byte, len(src))
+		copy(srcCopy, src)
+		return dst.DecodeText(nil, srcCopy)
+	}
+
+	return errors.Errorf("cannot scan %T", src)
+}
+
+// Value implements the database/sql/driver Valuer interface.
+func (src Macaddr) Value() (driver.Value, error) {
+	return EncodeValueText(src)
+}
+
+func (src Macaddr) MarshalJSON() ([]byte, error) {
+	switch src.Status {
+	case Present:
+		return json.Marshal(src.Addr.String())
+	case Null:
+		return []byte("null"), nil
+	}
+
+	return nil, errBadStatus
+}
+
+func (dst *Macaddr) UnmarshalJSON(b []byte) error {
+	var s *string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		return err
+	}
+
+	if s == nil {
+		*dst = Macaddr{Status: Null}
+		return nil
+	}
+
+	return dst.DecodeText(nil, []byte(*s))
+}
+
+func assignHardwareAddrTo(addr net.HardwareAddr, dst interface{}) error {
+	switch v := dst.(type) {
+	case *net.HardwareAddr:
+		*v = make(net.HardwareAddr, len(addr))
+		copy(*v, addr)
+		return nil
+	case *string:
+		*v = addr.String()
+		return nil
+	default:
+		if nextDst, retry := GetAssignToDstType(dst); retry {
+			return assignHardwareAddrTo(addr, nextDst)
+		}
+		return errors.Errorf("unable to assign to %T", dst)
+	}
+}
+
+// parseMacaddr6 parses the formats listed at Macaddr.DecodeText. As with the
+// server's sscanf patterns, the groups of the first two formats may also be a
+// single hex digit.
+func parseMacaddr6(s string) (net.HardwareAddr, error) {
+	s = strings.TrimSpace(s)
+
+	for _, sep := range []string{":", "-"} {
+		parts := strings.Split(s, sep)
+		if len(parts) != 6 {
+			continue
+		}
+		addr := make(net.HardwareAddr, 6)
+		for i, p := range parts {
+			n, err := strconv.ParseUint(p, 16, 8)
+			if err != nil || len(p) > 2 {
+				return nil, errors.Errorf("invalid input syntax for type macaddr: %q", s)
+			}
+			addr[i] = byte(n)
+		}
+		return addr, nil
+	}
+
+	var groups []string
+	switch {
+	case strings.Count(s, ".") == 2:
+		groups = strings.Split(s, ".")
+	case strings.Count(s, "-") == 2:
+		groups = strings.Split(s, "-")
+	case strings.Count(s, ":") == 1:
+		groups = strings.Split(s, ":")
+	case strings.Count(s, "-") == 1:
+		groups = strings.Split(s, "-")
+	default:
+		groups = []string{s}
+	}
+	for _, g := range groups {
+		if len(g) != 12/len(groups) {
+			return nil, errors.Errorf("invalid input syntax for type macaddr: %q", s)
+		}
+	}
+
+	addr, err := hex.DecodeString(strings.Join(groups, ""))
+	if err != nil || len(addr) != 6 {
+		return nil, errors.Errorf("invalid input syntax for type macaddr: %q", s)
+	}
+	return net.HardwareAddr(addr), nil
+}
+
+// parseMacaddr parses pairs of hex digits optionally separated by ':', '-' or
+// '.'. As in the server's macaddr8 input function, a separator may only appear
+// between two bytes and every separator must be the same character. The length
+// of the result is not checked.
+func parseMacaddr(s string) (net.HardwareAddr, error) {
+	s = strings.TrimSpace(s)
+
+	addr := make(net.HardwareAddr, 0, 8)
+	var spacer byte
+	for i := 0; i < len(s); {
+		if len(addr) > 0 && (s[i] == ':' || s[i] == '-' || s[i] == '.') {
+			if spacer == 0 {
+				spacer = s[i]
+			} else if spacer != s[i] {
+				return nil, errors.Errorf("invalid MAC address: %q", s)
+			}
+			i++
+			if i == len(s) {
+				return nil, errors.Errorf("invalid MAC address: %q", s)
+			}
+		}
+
+		if i+2 > len(s) {
+			return nil, errors.Errorf("invalid MAC address: %q", s)
+		}
+		hi, ok1 := fromHexChar(s[i])
+		lo, ok2 := fromHexChar(s[i+1])
+		if !ok1 || !ok2 {
+			return nil, errors.Errorf("invalid MAC address: %q", s)
+		}
+		addr = append(addr, hi<<4|lo)
+		i += 2
+
+		if len(addr) > 8 {
+			return nil, errors.Errorf("invalid MAC address: %q", s)
+		}
+	}
+
+	return addr, nil
+}
+
+func fromHexChar(c byte) (byte, bool) {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0', true
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10, true
+	case 'A' <= c && c <= 'F':
+		return c - 'A' + 10, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,227 @@
+package tstype
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"net"
+
+	"github.com/jackc/pgtype"
+
+	errors "golang.org/x/xerrors"
+)
+
+// Macaddr8 represents the PostgreSQL macaddr8 type, an 8 byte EUI-64 MAC
+// address. As in the server, a 6 byte address is converted to EUI-64 by
+// inserting FF:FE in the middle.
+type Macaddr8 struct {
+	Addr   net.HardwareAddr
+	Status Status
+}
+
+func (dst *Macaddr8) Set(src interface{}) error {
+	if src == nil {
+		*dst = Macaddr8{Status: Null}
+		return nil
+	}
+
+	if value, ok := src.(interface{ Get() interface{} }); ok {
+		value2 := value.Get()
+		if value2 != value {
+			return dst.Set(value2)
+		}
+	}
+
+	switch value := src.(type) {
+	case net.HardwareAddr:
+		if value == nil {
+			*dst = Macaddr8{Status: Null}
+			return nil
+		}
+		addr, err := toMacaddr8(value)
+		if err != nil {
+			return err
+		}
+		*dst = Macaddr8{Addr: addr, Status: Present}
+	case string:
+		return dst.DecodeText(nil, []byte(value))
+	case *net.HardwareAddr:
+		if value == nil {
+			*dst = Macaddr8{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	case *string:
+		if value == nil {
+			*dst = Macaddr8{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	default:
+		if originalSrc, ok := underlyingPtrType(src); ok {
+			return dst.Set(originalSrc)
+		}
+		return errors.Errorf("cannot convert %v to Macaddr8", value)
+	}
+
+	return nil
+}
+
+func (dst Macaddr8) Get() interface{} {
+	switch dst.Status {
+	case Present:
+		return dst.Addr
+	case Null:
+		return nil
+	default:
+		return dst.Status
+	}
+}
+
+func (src *Macaddr8) AssignTo(dst interface{}) error {
+	switch src.Status {
+	case Present:
+		return assignHardwareAddrTo(src.Addr, dst)
+	case Null:
+		return NullAssignTo(dst)
+	}
+
+	return errors.Errorf("cannot decode %#v into %T", src, dst)
+}
+
+// DecodeText accepts every input format the server accepts for macaddr8,
+// including 6 byte addresses, which are converted to EUI-64.
+func (dst *Macaddr8) DecodeText(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Macaddr8{Status: Null}
+		return nil
+	}
+
+	addr, err := parseMacaddr(string(src))
+	if err != nil {
+		return err
+	}
+
+	addr, err = toMacaddr8(addr)
+	if err != nil {
+		return err
+	}
+
+	*dst = Macaddr8{Addr: addr, Status: Present}
+	return nil
+}
+
+func (dst *Macaddr8) DecodeBinary(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Macaddr8{Status: Null}
+		return nil
+	}
+
+	if len(src) != 8 {
+		return errors.Errorf("Received an invalid size for a macaddr8: %d", len(src))
+	}
+
+	addr := make(net.HardwareAddr, 8)
+	copy(addr, src)
+
+	*dst = Macaddr8{Addr: addr, Status: Present}
+	return nil
+}
+
+func (src Macaddr8) EncodeText(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	addr, err := toMacaddr8(src.Addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(buf, addr.String()...), nil
+}
+
+func (src Macaddr8) EncodeBinary(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	addr, err := toMacaddr8(src.Addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(buf, addr...), nil
+}
+
+// Scan implements the database/sql Scanner interface.
+func (dst *Macaddr8) Scan(src interface{}) error {
+	if src == nil {
+		*dst = Macaddr8{Status: Null}
+		return nil
+	}
+
+	switch src := src.(type) {
+	case string:
+		return dst.DecodeText(nil, []byte(src))
+	case []byte:
+		srcCopy := make([]byte, len(src))
+		copy(srcCopy, src)
+		return dst.DecodeText(nil, srcCopy)
+	}
+
+	return errors.Errorf("cannot scan %T", src)
+}
+
+// Value implements the database/sql/driver Valuer interface.
+func (src Macaddr8) Value() (driver.Value, error) {
+	return EncodeValueText(src)
+}
+
+func (src Macaddr8) MarshalJSON() ([]byte, error) {
+	switch src.Status {
+	case Present:
+		return json.Marshal(src.Addr.String())
+	case Null:
+		return []byte("null"), nil
+	}
+
+	return nil, errBadStatus
+}
+
+func (dst *Macaddr8) UnmarshalJSON(b []byte) error {
+	var s *string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		return err
+	}
+
+	if s == nil {
+		*dst = Macaddr8{Status: Null}
+		return nil
+	}
+
+	return dst.DecodeText(nil, []byte(*s))
+}
+
+// toMacaddr8 returns a copy of addr as an 8 byte address, inserting FF:FE
+// into a 6 byte address as the server's macaddr8 conversion does.
+func toMacaddr8(addr net.HardwareAddr) (net.HardwareAddr, error) {
+	switch len(addr) {
+	case 6:
+		return net.HardwareAddr{addr[0], addr[1], addr[2], 0xff, 0xfe, addr[3], addr[4], addr[5]}, nil
+	case 8:
+		addr8 := make(net.HardwareAddr, 8)
+		copy(addr8, addr)
+		return addr8, nil
+	}
+
+	return nil, errors.Errorf("invalid MAC address length for macaddr8: %d", len(addr))
+}
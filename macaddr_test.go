@@ -0,0 +1,95 @@
+package tstype_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/jackc/pgtype"
+	"github.com/tossp/tstype"
+)
+
+func TestMacaddrDecodeText(t *testing.T) {
+	for i, src := range []string{
+		"08:00:2b:01:02:03",
+		"08-00-2b-01-02-03",
+		"08002b:010203",
+		"08002b-010203",
+		"0800.2b01.0203",
+		"0800-2b01-0203",
+		"08002B010203",
+		"8:0:2b:1:2:3",
+		" 08:00:2b:01:02:03 ",
+	} {
+		var r tstype.Macaddr
+		if err := r.DecodeText(nil, []byte(src)); err != nil {
+			t.Errorf("%d: %v", i, err)
+			continue
+		}
+		if r.Addr.String() != "08:00:2b:01:02:03" {
+			t.Errorf("%d: %q: got %v", i, src, r.Addr)
+		}
+	}
+
+	for i, src := range []string{
+		"08:00-2b:01:02:03",
+		"08:00:2b:01:02",
+		"08002b01:02030405",
+		"0800:2b01:020g",
+		"08:00:2b:01:02:03:04:05",
+		"08002b0102030405",
+		"08:002b:01:0203",
+		"0800:2b01:0203",
+		"0800.2b01-0203",
+		"08.00.2b.01.02.03",
+		"008:00:2b:01:02:03",
+		"08::2b:01:02:03",
+	} {
+		var r tstype.Macaddr
+		if err := r.DecodeText(nil, []byte(src)); err == nil {
+			t.Errorf("%d: %q: expected error, got %v", i, src, r.Addr)
+		}
+	}
+}
+
+func TestMacaddr8DecodeText(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected string
+	}{
+		{src: "08:00:2b:01:02:03:04:05", expected: "08:00:2b:01:02:03:04:05"},
+		{src: "08002b:0102030405", expected: "08:00:2b:01:02:03:04:05"},
+		{src: "08002b01:02030405", expected: "08:00:2b:01:02:03:04:05"},
+		{src: "0800.2b01.0203.0405", expected: "08:00:2b:01:02:03:04:05"},
+		{src: "08002b0102030405", expected: "08:00:2b:01:02:03:04:05"},
+		{src: "08:00:2b:01:02:03", expected: "08:00:2b:ff:fe:01:02:03"},
+	}
+
+	for i, tt := range tests {
+		var r tstype.Macaddr8
+		if err := r.DecodeText(nil, []byte(tt.src)); err != nil {
+			t.Errorf("%d: %v", i, err)
+			continue
+		}
+		if r.Addr.String() != tt.expected {
+			t.Errorf("%d: %q: expected %s, got %v", i, tt.src, tt.expected, r.Addr)
+		}
+	}
+}
+
+func TestMacaddrEncodeInvalidStatus(t *testing.T) {
+	addr := net.HardwareAddr{8, 0, 0x2b, 1, 2, 3}
+	for i, v := range []interface {
+		EncodeText(*pgtype.ConnInfo, []byte) ([]byte, error)
+		EncodeBinary(*pgtype.ConnInfo, []byte) ([]byte, error)
+	}{
+		tstype.Macaddr{Addr: addr, Status: tstype.Status(2)},
+		tstype.Macaddr8{Addr: append(addr, 4, 5), Status: tstype.Status(2)},
+	} {
+		if _, err := v.EncodeText(nil, nil); err == nil {
+			t.Errorf("%d: EncodeText: expected error", i)
+		}
+		if _, err := v.EncodeBinary(nil, nil); err == nil {
+			t.Errorf("%d: EncodeBinary: expected error", i)
+		}
+	}
+}
@@ -0,0 +1,402 @@
+package tstype
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/json"
+	"strings"
+	"unicode"
+
+	"github.com/jackc/pgio"
+	"github.com/jackc/pgtype"
+	"github.com/shopspring/decimal"
+
+	errors "golang.org/x/xerrors"
+)
+
+// MoneyFractionalDigits is the number of fractional digits of a Money without
+// a Type, the frac_digits of most currencies.
+const MoneyFractionalDigits int32 = 2
+
+// MoneyType describes the lc_monetary of a server. The binary format is an
+// int64 count of the smallest currency unit, so a Money must know how many
+// fractional digits the server uses to decode it, e.g. 0 for ¥ or 3 for the
+// Kuwaiti dinar.
+type MoneyType struct {
+	// FracDigits is frac_digits of lc_monetary. As on the server, a value
+	// outside 0 to 10 is taken as 2.
+	FracDigits int32
+}
+
+// Money represents the PostgreSQL money type. Values are rounded to the
+// fractional digits of Type when set or decoded, as the server does. When Type
+// is nil, MoneyFractionalDigits is used. Set and the decoders keep Type.
+//
+// Only DecodeText reads the locale dependent format of the server. Strings
+// passed to Set and UnmarshalJSON are plain decimals such as "-1234.56".
+type Money struct {
+	Decimal decimal.Decimal
+	Type    *MoneyType
+	Status  Status
+}
+
+func (src Money) fracDigits() int32 {
+	if src.Type == nil || src.Type.FracDigits < 0 || src.Type.FracDigits > 10 {
+		return MoneyFractionalDigits
+	}
+	return src.Type.FracDigits
+}
+
+func (dst *Money) Set(src interface{}) error {
+	if src == nil {
+		*dst = Money{Type: dst.Type, Status: Null}
+		return nil
+	}
+
+	if value, ok := src.(interface{ Get() interface{} }); ok {
+		value2 := value.Get()
+		if value2 != value {
+			return dst.Set(value2)
+		}
+	}
+
+	switch value := src.(type) {
+	case decimal.Decimal:
+		d := value.Round(dst.fracDigits())
+		if _, err := moneyToInt64(d, dst.fracDigits()); err != nil {
+			return err
+		}
+		*dst = Money{Decimal: d, Type: dst.Type, Status: Present}
+	case string:
+		d, err := decimal.NewFromString(value)
+		if err != nil {
+			return errors.Errorf("invalid input syntax for type money: %q", value)
+		}
+		return dst.Set(d)
+	case *decimal.Decimal:
+		if value == nil {
+			*dst = Money{Type: dst.Type, Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	case *string:
+		if value == nil {
+			*dst = Money{Type: dst.Type, Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	default:
+		var num Numeric
+		if err := num.Set(value); err != nil {
+			return errors.Errorf("cannot convert %v to Money", value)
+		}
+		if num.Status != Present {
+			*dst = Money{Type: dst.Type, Status: Null}
+			return nil
+		}
+		return dst.Set(num.Decimal)
+	}
+
+	return nil
+}
+
+func (dst Money) Get() interface{} {
+	switch dst.Status {
+	case Present:
+		return dst.Decimal
+	case Null:
+		return nil
+	default:
+		return dst.Status
+	}
+}
+
+// AssignTo assigns src to dst. Besides *Numeric and *string, it accepts every
+// destination Numeric.AssignTo accepts.
+func (src *Money) AssignTo(dst interface{}) error {
+	switch src.Status {
+	case Present:
+		switch v := dst.(type) {
+		case *Numeric:
+			*v = Numeric{Decimal: src.Decimal, Status: Present}
+			return nil
+		case *string:
+			*v = src.Decimal.StringFixed(src.fracDigits())
+			return nil
+		default:
+			num := Numeric{Decimal: src.Decimal, Status: Present}
+			return num.AssignTo(dst)
+		}
+	case Null:
+		return NullAssignTo(dst)
+	}
+
+	return errors.Errorf("cannot decode %#v into %T", src, dst)
+}
+
+// DecodeText parses the locale dependent output of the server, e.g.
+// "$1,234.56", "-¥1,235", "1.234,56 €" or "($5.00)". See parseMoney.
+func (dst *Money) DecodeText(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Money{Type: dst.Type, Status: Null}
+		return nil
+	}
+
+	digits := dst.fracDigits()
+	d, err := parseMoney(string(src), digits)
+	if err != nil {
+		return err
+	}
+	if _, err := moneyToInt64(d, digits); err != nil {
+		return err
+	}
+
+	*dst = Money{Decimal: d, Type: dst.Type, Status: Present}
+	return nil
+}
+
+func (dst *Money) DecodeBinary(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Money{Type: dst.Type, Status: Null}
+		return nil
+	}
+
+	if len(src) != 8 {
+		return errors.Errorf("invalid length for money: %v", len(src))
+	}
+
+	n := int64(binary.BigEndian.Uint64(src))
+	*dst = Money{Decimal: decimal.New(n, -dst.fracDigits()), Type: dst.Type, Status: Present}
+	return nil
+}
+
+// PreferredParamFormat returns the binary format, as the server interprets
+// money text input according to lc_monetary.
+func (Money) PreferredParamFormat() int16 {
+	return pgtype.BinaryFormatCode
+}
+
+// EncodeText encodes src as a plain decimal with the fractional digits of its
+// Type and no currency symbol or separators. The server only
+// reads this correctly when lc_monetary uses '.' as the decimal point.
+func (src Money) EncodeText(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Present:
+		return append(buf, src.Decimal.StringFixed(src.fracDigits())...), nil
+	case Null:
+		return nil, nil
+	default:
+		return nil, errBadStatus
+	}
+}
+
+func (src Money) EncodeBinary(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Present:
+		n, err := moneyToInt64(src.Decimal, src.fracDigits())
+		if err != nil {
+			return nil, err
+		}
+		return pgio.AppendInt64(buf, n), nil
+	case Null:
+		return nil, nil
+	default:
+		return nil, errBadStatus
+	}
+}
+
+// Scan implements the database/sql Scanner interface.
+func (dst *Money) Scan(src interface{}) error {
+	if src == nil {
+		*dst = Money{Type: dst.Type, Status: Null}
+		return nil
+	}
+
+	switch src := src.(type) {
+	case int64:
+		return dst.Set(src)
+	case float64:
+		return dst.Set(src)
+	case string:
+		return dst.DecodeText(nil, []byte(src))
+	case []byte:
+		return dst.DecodeText(nil, src)
+	}
+
+	return errors.Errorf("cannot scan %T", src)
+}
+
+// Value implements the database/sql/driver Valuer interface.
+func (src Money) Value() (driver.Value, error) {
+	return EncodeValueText(src)
+}
+
+func (src Money) MarshalJSON() ([]byte, error) {
+	switch src.Status {
+	case Present:
+		return src.Decimal.MarshalJSON()
+	case Null:
+		return []byte("null"), nil
+	}
+	return nil, errBadStatus
+}
+
+// UnmarshalJSON accepts a JSON number or a string holding a plain decimal.
+func (dst *Money) UnmarshalJSON(b []byte) error {
+	var v interface{}
+	d := json.NewDecoder(strings.NewReader(string(b)))
+	d.UseNumber()
+	if err := d.Decode(&v); err != nil {
+		return err
+	}
+
+	switch v := v.(type) {
+	case nil:
+		*dst = Money{Type: dst.Type, Status: Null}
+		return nil
+	case json.Number:
+		return dst.Set(string(v))
+	case string:
+		return dst.Set(v)
+	}
+
+	return errors.Errorf("cannot unmarshal %s into Money", b)
+}
+
+// moneyToInt64 returns d as a count of the smallest currency unit, of which
+// there are 10^digits in one.
+func moneyToInt64(d decimal.Decimal, digits int32) (int64, error) {
+	n := d.Round(digits).Shift(digits).BigInt()
+	if !n.IsInt64() {
+		return 0, errors.Errorf("%v is out of range for money", d)
+	}
+	return n.Int64(), nil
+}
+
+// parseMoney parses a money value in the text format of any lc_monetary, and
+// rounds it to digits fractional digits. Currency symbols and codes (including
+// ¥ and ￥), spaces and apostrophes are ignored. A value is negative when it
+// has a minus sign anywhere or is enclosed in parentheses.
+//
+// Either '.' or ',' may be the decimal point. When both appear, the last one
+// is the decimal point and the other is the thousands separator. When only one
+// of them appears, it is the thousands separator if it appears more than once,
+// or if it appears once between a group of one to three digits not starting
+// with 0 and exactly three digits, unless digits is 3. Otherwise it is the
+// decimal point. Thousands separators must separate groups of three digits.
+func parseMoney(s string, digits int32) (decimal.Decimal, error) {
+	var num []byte
+	negative := false
+	parens := 0
+	hasDigit := false
+
+	for _, r := range s {
+		switch {
+		case '0' <= r && r <= '9':
+			if parens == 2 {
+				return decimal.Decimal{}, errors.Errorf("invalid input syntax for type money: %q", s)
+			}
+			num = append(num, byte(r))
+			hasDigit = true
+		case r == '.' || r == ',':
+			num = append(num, byte(r))
+		case r == '-':
+			if negative {
+				return decimal.Decimal{}, errors.Errorf("invalid input syntax for type money: %q", s)
+			}
+			negative = true
+		case r == '(':
+			if parens != 0 || hasDigit {
+				return decimal.Decimal{}, errors.Errorf("invalid input syntax for type money: %q", s)
+			}
+			parens = 1
+		case r == ')':
+			if parens != 1 || !hasDigit {
+				return decimal.Decimal{}, errors.Errorf("invalid input syntax for type money: %q", s)
+			}
+			parens = 2
+		case r == '+', r == '\'', r == '’',
+			unicode.IsSpace(r), unicode.IsLetter(r), unicode.Is(unicode.Sc, r):
+		default:
+			return decimal.Decimal{}, errors.Errorf("invalid input syntax for type money: %q", s)
+		}
+	}
+
+	if !hasDigit || parens == 1 {
+		return decimal.Decimal{}, errors.Errorf("invalid input syntax for type money: %q", s)
+	}
+	if parens == 2 {
+		if negative {
+			return decimal.Decimal{}, errors.Errorf("invalid input syntax for type money: %q", s)
+		}
+		negative = true
+	}
+
+	str := string(num)
+	point := byte(0)
+	lastDot := strings.LastIndexByte(str, '.')
+	lastComma := strings.LastIndexByte(str, ',')
+	switch {
+	case lastDot >= 0 && lastComma >= 0:
+		if lastDot > lastComma {
+			point = '.'
+		} else {
+			point = ','
+		}
+	case lastDot >= 0 || lastComma >= 0:
+		sep, last := byte('.'), lastDot
+		if lastComma >= 0 {
+			sep, last = ',', lastComma
+		}
+		if strings.IndexByte(str, sep) == last && !(digits != 3 && isThousandsGroup(str[:last], str[last+1:])) {
+			point = sep
+		}
+	}
+
+	intPart, fracPart := str, ""
+	if point != 0 {
+		i := strings.LastIndexByte(str, point)
+		intPart, fracPart = str[:i], str[i+1:]
+	}
+	if strings.ContainsAny(fracPart, ".,") {
+		return decimal.Decimal{}, errors.Errorf("invalid input syntax for type money: %q", s)
+	}
+	if strings.Contains(intPart, ".") && strings.Contains(intPart, ",") {
+		return decimal.Decimal{}, errors.Errorf("invalid input syntax for type money: %q", s)
+	}
+	if strings.ContainsAny(intPart, ".,") {
+		groups := strings.FieldsFunc(intPart, func(r rune) bool { return r == '.' || r == ',' })
+		if len(groups) != strings.Count(intPart, ".")+strings.Count(intPart, ",")+1 ||
+			len(groups[0]) > 3 {
+			return decimal.Decimal{}, errors.Errorf("invalid input syntax for type money: %q", s)
+		}
+		for _, g := range groups[1:] {
+			if len(g) != 3 {
+				return decimal.Decimal{}, errors.Errorf("invalid input syntax for type money: %q", s)
+			}
+		}
+	}
+
+	var sb strings.Builder
+	if negative {
+		sb.WriteByte('-')
+	}
+	sb.WriteString(strings.NewReplacer(".", "", ",", "").Replace(intPart))
+	if fracPart != "" {
+		sb.WriteByte('.')
+		sb.WriteString(fracPart)
+	}
+
+	d, err := decimal.NewFromString(sb.String())
+	if err != nil {
+		return decimal.Decimal{}, errors.Errorf("invalid input syntax for type money: %q", s)
+	}
+
+	return d.Round(digits), nil
+}
+
+// isThousandsGroup reports whether a single separator between head and tail
+// can be a thousands separator, as in "1,235".
+func isThousandsGroup(head, tail string) bool {
+	return len(tail) == 3 && len(head) >= 1 && len(head) <= 3 && head[0] != '0'
+}
@@ -0,0 +1,169 @@
+package tstype_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/tossp/tstype"
+)
+
+func TestMoneyDecodeText(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected string
+	}{
+		{src: "$1,234.56", expected: "1234.56"},
+		{src: "-$1,234.56", expected: "-1234.56"},
+		{src: "($5.00)", expected: "-5"},
+		{src: "1.234,56 €", expected: "1234.56"},
+		{src: "1 234,56 €", expected: "1234.56"},
+		{src: "Fr 1'234.56", expected: "1234.56"},
+		{src: "1,235.00 kr", expected: "1235"},
+		{src: "￥-1,234,567", expected: "-1234567"},
+		{src: "1,234,567.891", expected: "1234567.89"},
+		{src: "12.345", expected: "12345"},
+		{src: "-¥1,235", expected: "-1235"},
+		{src: "￥1,235", expected: "1235"},
+		{src: "$1,000", expected: "1000"},
+		{src: "1,5", expected: "1.5"},
+		{src: "0,125", expected: "0.13"},
+		{src: "1234,567", expected: "1234.57"},
+		{src: "12.5", expected: "12.5"},
+		{src: "0.005", expected: "0.01"},
+		{src: "USD 7", expected: "7"},
+	}
+
+	for i, tt := range tests {
+		var r tstype.Money
+		if err := r.DecodeText(nil, []byte(tt.src)); err != nil {
+			t.Errorf("%d: %v", i, err)
+			continue
+		}
+		if !r.Decimal.Equal(decimal.RequireFromString(tt.expected)) {
+			t.Errorf("%d: %q: expected %s, got %s", i, tt.src, tt.expected, r.Decimal)
+		}
+	}
+
+	for i, src := range []string{"", "$", "--1", "(1", "(-1)", "1*2", "1.000.00,5"} {
+		var r tstype.Money
+		if err := r.DecodeText(nil, []byte(src)); err == nil {
+			t.Errorf("%d: %q: expected error, got %s", i, src, r.Decimal)
+		}
+	}
+}
+
+func TestMoneyFracDigits(t *testing.T) {
+	yen := &tstype.MoneyType{FracDigits: 0}
+	dinar := &tstype.MoneyType{FracDigits: 3}
+
+	tests := []struct {
+		typ      *tstype.MoneyType
+		text     string
+		binary   []byte
+		expected string
+	}{
+		{yen, "-¥1,235", []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xfb, 0x2d}, "-1235"},
+		{yen, "￥1,234,567", []byte{0, 0, 0, 0, 0, 0x12, 0xd6, 0x87}, "1234567"},
+		{dinar, "KD 1,235", []byte{0, 0, 0, 0, 0, 0, 0x04, 0xd3}, "1.235"},
+		{dinar, "KD 1,234.567", []byte{0, 0, 0, 0, 0, 0x12, 0xd6, 0x87}, "1234.567"},
+		{nil, "$1,235", []byte{0, 0, 0, 0, 0, 0x01, 0xe2, 0x6c}, "1235"},
+	}
+
+	for i, tt := range tests {
+		expected := decimal.RequireFromString(tt.expected)
+
+		fromText := tstype.Money{Type: tt.typ}
+		if err := fromText.DecodeText(nil, []byte(tt.text)); err != nil || !fromText.Decimal.Equal(expected) {
+			t.Errorf("%d: DecodeText(%q): expected %s, got %s, %v", i, tt.text, expected, fromText.Decimal, err)
+		}
+		if fromText.Type != tt.typ {
+			t.Errorf("%d: DecodeText did not keep Type", i)
+		}
+
+		fromBinary := tstype.Money{Type: tt.typ}
+		if err := fromBinary.DecodeBinary(nil, tt.binary); err != nil || !fromBinary.Decimal.Equal(expected) {
+			t.Errorf("%d: DecodeBinary(%x): expected %s, got %s, %v", i, tt.binary, expected, fromBinary.Decimal, err)
+		}
+
+		if buf, err := fromBinary.EncodeBinary(nil, nil); err != nil || !bytes.Equal(buf, tt.binary) {
+			t.Errorf("%d: EncodeBinary: expected %x, got %x, %v", i, tt.binary, buf, err)
+		}
+	}
+
+	m := tstype.Money{Type: yen}
+	if err := m.Set("12.5"); err != nil || !m.Decimal.Equal(decimal.NewFromInt(13)) {
+		t.Errorf("Set with 0 fractional digits: got %s, %v", m.Decimal, err)
+	}
+	if buf, err := m.EncodeText(nil, nil); err != nil || string(buf) != "13" {
+		t.Errorf("EncodeText with 0 fractional digits: got %q, %v", buf, err)
+	}
+}
+
+func TestMoneyBinaryRoundTrip(t *testing.T) {
+	var src tstype.Money
+	if err := src.Set(tstype.Numeric{Decimal: decimal.RequireFromString("-92233720368547758.08"), Status: tstype.Present}); err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := src.EncodeBinary(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var dst tstype.Money
+	if err := dst.DecodeBinary(nil, buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var num tstype.Numeric
+	if err := dst.AssignTo(&num); err != nil {
+		t.Fatal(err)
+	}
+	if !num.Decimal.Equal(src.Decimal) {
+		t.Errorf("expected %s, got %s", src.Decimal, num.Decimal)
+	}
+
+	if err := src.Set("92233720368547758.08"); err == nil {
+		t.Error("expected out of range error")
+	}
+}
+
+func TestMoneySetAndUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected string
+	}{
+		{src: "1.125", expected: "1.13"},
+		{src: "12.345", expected: "12.35"},
+		{src: "-1234.5", expected: "-1234.5"},
+		{src: "7", expected: "7"},
+	}
+
+	for i, tt := range tests {
+		var r tstype.Money
+		if err := r.Set(tt.src); err != nil || !r.Decimal.Equal(decimal.RequireFromString(tt.expected)) {
+			t.Errorf("%d: Set(%q): expected %s, got %s, %v", i, tt.src, tt.expected, r.Decimal, err)
+		}
+
+		for _, js := range []string{tt.src, `"` + tt.src + `"`} {
+			var r tstype.Money
+			if err := json.Unmarshal([]byte(js), &r); err != nil || !r.Decimal.Equal(decimal.RequireFromString(tt.expected)) {
+				t.Errorf("%d: UnmarshalJSON(%s): expected %s, got %s, %v", i, js, tt.expected, r.Decimal, err)
+			}
+		}
+	}
+
+	for i, src := range []string{"1,5", "$1,234.56", "1.234,56", ""} {
+		var r tstype.Money
+		if err := r.Set(src); err == nil {
+			t.Errorf("%d: Set(%q): expected error, got %s", i, src, r.Decimal)
+		}
+	}
+
+	var r tstype.Money
+	if err := json.Unmarshal([]byte("null"), &r); err != nil || r.Status != tstype.Null {
+		t.Errorf("UnmarshalJSON null: got %+v, %v", r, err)
+	}
+}
@@ -0,0 +1,318 @@
+package tstype
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgio"
+	"github.com/jackc/pgtype"
+
+	errors "golang.org/x/xerrors"
+)
+
+// rangeType is satisfied by the range types, which all have the layout of
+// rangeValue[T].
+type rangeType[T any] interface {
+	~struct {
+		Lower     T
+		Upper     T
+		LowerType pgtype.BoundType
+		UpperType pgtype.BoundType
+		Status    Status
+	}
+}
+
+// multirangeValue has the same layout as the multirange types, which convert
+// to and from it to share the codecs below.
+type multirangeValue[R any] struct {
+	Ranges []R
+	Status Status
+}
+
+func decodeMultirangeText[R rangeType[T], T any, PT rangeBoundPtr[T]](ci *pgtype.ConnInfo, src []byte) (multirangeValue[R], error) {
+	if src == nil {
+		return multirangeValue[R]{Status: Null}, nil
+	}
+
+	elems, err := splitMultirangeText(string(src))
+	if err != nil {
+		return multirangeValue[R]{}, err
+	}
+
+	m := multirangeValue[R]{Ranges: make([]R, 0, len(elems)), Status: Present}
+	for _, elem := range elems {
+		r, err := decodeRangeText[T, PT](ci, []byte(elem))
+		if err != nil {
+			return multirangeValue[R]{}, err
+		}
+		m.Ranges = append(m.Ranges, R(r))
+	}
+
+	return m, nil
+}
+
+// splitMultirangeText splits the text form of a multirange, such as
+// {[1,3),[5,7)}, into the text forms of its ranges.
+func splitMultirangeText(src string) ([]string, error) {
+	s := strings.TrimSpace(src)
+	if len(s) < 2 || s[0] != '{' || s[len(s)-1] != '}' {
+		return nil, errors.Errorf("invalid multirange: %q", src)
+	}
+	s = strings.TrimSpace(s[1 : len(s)-1])
+
+	var elems []string
+	for len(s) > 0 {
+		var n int
+		switch {
+		case len(s) >= 5 && strings.EqualFold(s[:5], "empty"):
+			n = 5
+		case s[0] == '[' || s[0] == '(':
+			n = rangeTextLen(s)
+			if n < 0 {
+				return nil, errors.Errorf("invalid multirange: %q", src)
+			}
+		default:
+			return nil, errors.Errorf("invalid multirange: %q", src)
+		}
+
+		elems = append(elems, s[:n])
+
+		s = strings.TrimSpace(s[n:])
+		if len(s) == 0 {
+			break
+		}
+		if s[0] != ',' {
+			return nil, errors.Errorf("invalid multirange: %q", src)
+		}
+		s = strings.TrimSpace(s[1:])
+		if len(s) == 0 {
+			return nil, errors.Errorf("invalid multirange: %q", src)
+		}
+	}
+
+	return elems, nil
+}
+
+// rangeTextLen returns the length of the range at the start of s, or -1 if it
+// is not terminated. Brackets inside quoted or backslash-escaped bounds do not
+// end the range.
+func rangeTextLen(s string) int {
+	inQuote := false
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\':
+			i++
+		case c == '"':
+			if inQuote && i+1 < len(s) && s[i+1] == '"' {
+				i++
+			} else {
+				inQuote = !inQuote
+			}
+		case !inQuote && (c == ']' || c == ')'):
+			return i + 1
+		}
+	}
+	return -1
+}
+
+func decodeMultirangeBinary[R rangeType[T], T any, PT rangeBoundPtr[T]](ci *pgtype.ConnInfo, src []byte) (multirangeValue[R], error) {
+	if src == nil {
+		return multirangeValue[R]{Status: Null}, nil
+	}
+
+	if len(src) < 4 {
+		return multirangeValue[R]{}, errors.Errorf("multirange too short: %v", len(src))
+	}
+
+	count := int(int32(binary.BigEndian.Uint32(src)))
+	rp := 4
+	if count < 0 {
+		return multirangeValue[R]{}, errors.Errorf("invalid multirange range count: %d", count)
+	}
+
+	m := multirangeValue[R]{Ranges: make([]R, 0, count), Status: Present}
+	for i := 0; i < count; i++ {
+		if len(src[rp:]) < 4 {
+			return multirangeValue[R]{}, errors.Errorf("multirange too short for range %d", i)
+		}
+		rangeLen := int(int32(binary.BigEndian.Uint32(src[rp:])))
+		rp += 4
+		if rangeLen < 0 || len(src[rp:]) < rangeLen {
+			return multirangeValue[R]{}, errors.Errorf("invalid multirange range %d length: %d", i, rangeLen)
+		}
+
+		r, err := decodeRangeBinary[T, PT](ci, src[rp:rp+rangeLen])
+		if err != nil {
+			return multirangeValue[R]{}, err
+		}
+		rp += rangeLen
+
+		m.Ranges = append(m.Ranges, R(r))
+	}
+
+	if rp != len(src) {
+		return multirangeValue[R]{}, errors.Errorf("multirange has %d trailing bytes", len(src)-rp)
+	}
+
+	return m, nil
+}
+
+func encodeMultirangeText[R rangeType[T], T rangeBound](ci *pgtype.ConnInfo, buf []byte, m multirangeValue[R]) ([]byte, error) {
+	switch m.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	buf = append(buf, '{')
+	for i, r := range m.Ranges {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+
+		var err error
+		buf, err = encodeRangeText(ci, buf, rangeValue[T](r))
+		if err != nil {
+			return nil, err
+		}
+		if buf == nil {
+			return nil, errors.Errorf("multirange cannot contain a null range")
+		}
+	}
+
+	return append(buf, '}'), nil
+}
+
+func encodeMultirangeBinary[R rangeType[T], T rangeBound](ci *pgtype.ConnInfo, buf []byte, m multirangeValue[R]) ([]byte, error) {
+	switch m.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	buf = pgio.AppendInt32(buf, int32(len(m.Ranges)))
+	for _, r := range m.Ranges {
+		sp := len(buf)
+		buf = pgio.AppendInt32(buf, -1)
+
+		var err error
+		buf, err = encodeRangeBinary(ci, buf, rangeValue[T](r))
+		if err != nil {
+			return nil, err
+		}
+		if buf == nil {
+			return nil, errors.Errorf("multirange cannot contain a null range")
+		}
+
+		pgio.SetInt32(buf[sp:], int32(len(buf[sp:])-4))
+	}
+
+	return buf, nil
+}
+
+// marshalMultirangeJSON encodes a multirange as a JSON array of its ranges in
+// the form written by marshalRangeJSON.
+func marshalMultirangeJSON[R rangeType[T], T rangeBound](m multirangeValue[R]) ([]byte, error) {
+	switch m.Status {
+	case Null:
+		return []byte("null"), nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	elems := make([]json.RawMessage, len(m.Ranges))
+	for i, r := range m.Ranges {
+		b, err := marshalRangeJSON(rangeValue[T](r))
+		if err != nil {
+			return nil, err
+		}
+		elems[i] = b
+	}
+
+	return json.Marshal(elems)
+}
+
+func unmarshalMultirangeJSON[R rangeType[T], T any, PT rangeBoundPtr[T]](b []byte) (multirangeValue[R], error) {
+	var elems []json.RawMessage
+	if err := json.Unmarshal(b, &elems); err != nil {
+		return multirangeValue[R]{}, err
+	}
+
+	if elems == nil {
+		return multirangeValue[R]{Status: Null}, nil
+	}
+
+	m := multirangeValue[R]{Ranges: make([]R, 0, len(elems)), Status: Present}
+	for _, elem := range elems {
+		r, err := unmarshalRangeJSON[T, PT](elem)
+		if err != nil {
+			return multirangeValue[R]{}, err
+		}
+		if r.Status != Present {
+			return multirangeValue[R]{}, errors.Errorf("multirange cannot contain a null range")
+		}
+		m.Ranges = append(m.Ranges, R(r))
+	}
+
+	return m, nil
+}
+
+// canonicalMultirange returns m in the form the server stores it, as its
+// multirange_canonicalize does. Each range is canonicalized, empty ranges are
+// dropped, and the rest are sorted by lower bound, with overlapping and
+// adjacent ranges merged. strict is passed to makeRange for each range.
+func canonicalMultirange[R rangeType[T], T any](s rangeSubtype[T], m multirangeValue[R], strict bool) (multirangeValue[R], error) {
+	if m.Status != Present {
+		return m, nil
+	}
+
+	ranges := make([]rangeValue[T], 0, len(m.Ranges))
+	for _, r := range m.Ranges {
+		if rangeValue[T](r).Status != Present {
+			return multirangeValue[R]{}, errors.Errorf("multirange cannot contain a null range")
+		}
+
+		r, err := s.makeRange(rangeValue[T](r), strict)
+		if err != nil {
+			return multirangeValue[R]{}, err
+		}
+		if !r.isEmpty() {
+			ranges = append(ranges, r)
+		}
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		if c := s.cmpBounds(ranges[i].lowerBound(), ranges[j].lowerBound()); c != 0 {
+			return c < 0
+		}
+		return s.cmpBounds(ranges[i].upperBound(), ranges[j].upperBound()) < 0
+	})
+
+	merged := ranges[:0]
+	for _, r := range ranges {
+		if n := len(merged); n > 0 {
+			last := &merged[n-1]
+			if s.cmpBounds(last.upperBound(), r.lowerBound()) >= 0 || s.adjacent(*last, r) {
+				if s.cmpBounds(r.upperBound(), last.upperBound()) > 0 {
+					last.Upper, last.UpperType = r.Upper, r.UpperType
+				}
+				continue
+			}
+		}
+		merged = append(merged, r)
+	}
+
+	result := multirangeValue[R]{Ranges: make([]R, len(merged)), Status: Present}
+	for i, r := range merged {
+		result.Ranges[i] = R(r)
+	}
+
+	return result, nil
+}
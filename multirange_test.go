@@ -0,0 +1,111 @@
+package tstype_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/tossp/tstype"
+)
+
+func TestInt4multirangeCanonical(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected string
+	}{
+		{src: "{}", expected: "{}"},
+		{src: " { empty , [1,1) } ", expected: "{}"},
+		{src: "{[5,7),[1,3),[2,5)}", expected: "{[1,7)}"},
+		{src: "{[1,3],(3,5)}", expected: "{[1,5)}"},
+		{src: "{[1,3),[4,5)}", expected: "{[1,3),[4,5)}"},
+		{src: "{[10,),(,2]}", expected: "{(,3),[10,)}"},
+		{src: "{(,),[1,2)}", expected: "{(,)}"},
+	}
+
+	for i, tt := range tests {
+		var m tstype.Int4multirange
+		if err := m.DecodeText(nil, []byte(tt.src)); err != nil {
+			t.Errorf("%d: %q: %v", i, tt.src, err)
+			continue
+		}
+		c, err := m.Canonical()
+		if err != nil {
+			t.Errorf("%d: %v", i, err)
+			continue
+		}
+		buf, err := c.EncodeText(nil, nil)
+		if err != nil {
+			t.Errorf("%d: %v", i, err)
+			continue
+		}
+		if string(buf) != tt.expected {
+			t.Errorf("%d: %q: expected %q, got %q", i, tt.src, tt.expected, buf)
+		}
+	}
+
+	for i, src := range []string{"", "{", "[1,2)", "{[1,2)", "{[1,2),}", "{[1,2) [3,4)}", "{[3,2)}x"} {
+		var m tstype.Int4multirange
+		if err := m.DecodeText(nil, []byte(src)); err == nil {
+			if _, err := m.Canonical(); err == nil {
+				t.Errorf("%d: %q: expected error", i, src)
+			}
+		}
+	}
+}
+
+func TestInt4multirangeCodecs(t *testing.T) {
+	var src tstype.Int4multirange
+	if err := src.Set("{[1,3),[5,)}"); err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := src.EncodeBinary(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dst tstype.Int4multirange
+	if err := dst.DecodeBinary(nil, buf); err != nil {
+		t.Fatal(err)
+	}
+	text, _ := dst.EncodeText(nil, nil)
+	if string(text) != "{[1,3),[5,)}" {
+		t.Errorf("binary: got %q", text)
+	}
+	if err := dst.DecodeBinary(nil, buf[:len(buf)-1]); err == nil {
+		t.Error("binary: expected error for truncated input")
+	}
+
+	js, err := json.Marshal(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(js) != `[{"lower":1,"upper":3,"bounds":"[)"},{"lower":5,"bounds":"[)"}]` {
+		t.Errorf("json: got %s", js)
+	}
+	dst = tstype.Int4multirange{}
+	if err := json.Unmarshal(js, &dst); err != nil {
+		t.Fatal(err)
+	}
+	text, _ = dst.EncodeText(nil, nil)
+	if string(text) != "{[1,3),[5,)}" {
+		t.Errorf("json: got %q", text)
+	}
+
+	if !src.Contains(2) || src.Contains(4) || !src.Contains(100) || src.IsEmpty() {
+		t.Error("Contains")
+	}
+}
+
+func TestTstzmultirangeQuotedBounds(t *testing.T) {
+	var m tstype.Tstzmultirange
+	if err := m.DecodeText(nil, []byte(`{["2020-01-01 00:00:00+00","2020-01-02 00:00:00+00"),["2020-01-01 12:00:00+00",infinity]}`)); err != nil {
+		t.Fatal(err)
+	}
+	c, err := m.Canonical()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Ranges) != 1 || !c.Contains(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)) || c.Contains(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("got %+v", c)
+	}
+}
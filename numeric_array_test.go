@@ -0,0 +1,88 @@
+package tstype_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/jackc/pgtype"
+	"github.com/shopspring/decimal"
+	"github.com/tossp/tstype"
+)
+
+func TestNumericArraySetAndAssignTo(t *testing.T) {
+	precise := "12345678901234567890.000000000000000001"
+
+	var a tstype.NumericArray
+	if err := a.Set([]string{"1.10", precise}); err != nil {
+		t.Fatal(err)
+	}
+	var strs []string
+	if err := a.AssignTo(&strs); err != nil || !reflect.DeepEqual(strs, []string{"1.1", precise}) {
+		t.Errorf("AssignTo []string: got %v, %v", strs, err)
+	}
+
+	var decs []decimal.Decimal
+	if err := a.AssignTo(&decs); err != nil || len(decs) != 2 || decs[1].String() != precise {
+		t.Errorf("AssignTo []decimal.Decimal: got %v, %v", decs, err)
+	}
+
+	if err := a.Set([][]float64{{1.5, 2}, {3, 4.25}}); err != nil {
+		t.Fatal(err)
+	}
+	if len(a.Dimensions) != 2 || a.Elements[3].Decimal.String() != "4.25" {
+		t.Errorf("Set [][]float64: got %+v", a)
+	}
+	var floats [][]float64
+	if err := a.AssignTo(&floats); err != nil || !reflect.DeepEqual(floats, [][]float64{{1.5, 2}, {3, 4.25}}) {
+		t.Errorf("AssignTo [][]float64: got %v, %v", floats, err)
+	}
+
+	if err := a.Set([][]decimal.Decimal{{decimal.New(1, -2)}, {decimal.New(3, 0)}}); err != nil {
+		t.Fatal(err)
+	}
+	var decMatrix [][]decimal.Decimal
+	if err := a.AssignTo(&decMatrix); err != nil || len(decMatrix) != 2 || decMatrix[0][0].String() != "0.01" {
+		t.Errorf("AssignTo [][]decimal.Decimal: got %v, %v", decMatrix, err)
+	}
+}
+
+func TestNumericArrayCodecs(t *testing.T) {
+	var a tstype.NumericArray
+	if err := a.DecodeText(nil, []byte(`{{1.25,NULL},{-3,100000000000000000000.5}}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := a.EncodeText(nil, nil)
+	if err != nil || string(buf) != `{{1.25,NULL},{-3,100000000000000000000.5}}` {
+		t.Errorf("EncodeText: got %s, %v", buf, err)
+	}
+
+	ci := pgtype.NewConnInfo()
+	bin, err := a.EncodeBinary(ci, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fromBinary tstype.NumericArray
+	if err := fromBinary.DecodeBinary(ci, bin); err != nil {
+		t.Fatal(err)
+	}
+	if buf, _ := fromBinary.EncodeText(nil, nil); string(buf) != `{{1.25,NULL},{-3,100000000000000000000.5}}` {
+		t.Errorf("binary round trip: got %s", buf)
+	}
+
+	js, err := json.Marshal(a)
+	if err != nil || string(js) != `[["1.25",null],["-3","100000000000000000000.5"]]` {
+		t.Errorf("MarshalJSON: got %s, %v", js, err)
+	}
+	var back tstype.NumericArray
+	if err := json.Unmarshal(js, &back); err != nil {
+		t.Fatal(err)
+	}
+	if buf, _ := back.EncodeText(nil, nil); string(buf) != `{{1.25,NULL},{-3,100000000000000000000.5}}` {
+		t.Errorf("UnmarshalJSON: got %s", buf)
+	}
+	if err := json.Unmarshal([]byte(`[0.1, 2]`), &back); err != nil || back.Elements[0].Decimal.String() != "0.1" {
+		t.Errorf("UnmarshalJSON numbers: got %+v, %v", back, err)
+	}
+}
@@ -0,0 +1,203 @@
+package tstype
+
+import (
+	"database/sql/driver"
+
+	"github.com/jackc/pgtype"
+	"github.com/shopspring/decimal"
+
+	errors "golang.org/x/xerrors"
+)
+
+// Nummultirange represents the PostgreSQL nummultirange type, available since
+// PostgreSQL 14. Ranges are kept in the order they were set or decoded; use
+// Canonical to sort and merge them as the server does.
+type Nummultirange struct {
+	Ranges []Numrange
+	Status Status
+}
+
+func (dst *Nummultirange) Set(src interface{}) error {
+	if src == nil {
+		*dst = Nummultirange{Status: Null}
+		return nil
+	}
+
+	switch value := src.(type) {
+	case Nummultirange:
+		*dst = value
+	case *Nummultirange:
+		if value == nil {
+			*dst = Nummultirange{Status: Null}
+		} else {
+			*dst = *value
+		}
+	case []Numrange:
+		if value == nil {
+			*dst = Nummultirange{Status: Null}
+		} else {
+			*dst = Nummultirange{Ranges: value, Status: Present}
+		}
+	case string:
+		return dst.DecodeText(nil, []byte(value))
+	case *string:
+		if value == nil {
+			*dst = Nummultirange{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	default:
+		return errors.Errorf("cannot convert %v to Nummultirange", src)
+	}
+
+	return nil
+}
+
+func (dst Nummultirange) Get() interface{} {
+	switch dst.Status {
+	case Present:
+		return dst
+	case Null:
+		return nil
+	default:
+		return dst.Status
+	}
+}
+
+func (src *Nummultirange) AssignTo(dst interface{}) error {
+	switch src.Status {
+	case Present:
+		switch v := dst.(type) {
+		case *Nummultirange:
+			*v = *src
+			return nil
+		case *[]Numrange:
+			*v = make([]Numrange, len(src.Ranges))
+			copy(*v, src.Ranges)
+			return nil
+		case *string:
+			buf, err := src.EncodeText(nil, nil)
+			if err != nil {
+				return err
+			}
+			*v = string(buf)
+			return nil
+		default:
+			if nextDst, retry := GetAssignToDstType(dst); retry {
+				return src.AssignTo(nextDst)
+			}
+			return errors.Errorf("unable to assign to %T", dst)
+		}
+	case Null:
+		return NullAssignTo(dst)
+	}
+
+	return errors.Errorf("cannot decode %#v into %T", src, dst)
+}
+
+func (dst *Nummultirange) DecodeText(ci *pgtype.ConnInfo, src []byte) error {
+	m, err := decodeMultirangeText[Numrange, Numeric](ci, src)
+	if err != nil {
+		return err
+	}
+
+	*dst = Nummultirange(m)
+	return nil
+}
+
+func (dst *Nummultirange) DecodeBinary(ci *pgtype.ConnInfo, src []byte) error {
+	m, err := decodeMultirangeBinary[Numrange, Numeric](ci, src)
+	if err != nil {
+		return err
+	}
+
+	*dst = Nummultirange(m)
+	return nil
+}
+
+func (src Nummultirange) EncodeText(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	return encodeMultirangeText(ci, buf, multirangeValue[Numrange](src))
+}
+
+func (src Nummultirange) EncodeBinary(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	return encodeMultirangeBinary(ci, buf, multirangeValue[Numrange](src))
+}
+
+// Scan implements the database/sql Scanner interface.
+func (dst *Nummultirange) Scan(src interface{}) error {
+	if src == nil {
+		*dst = Nummultirange{Status: Null}
+		return nil
+	}
+
+	switch src := src.(type) {
+	case string:
+		return dst.DecodeText(nil, []byte(src))
+	case []byte:
+		srcCopy := make([]byte, len(src))
+		copy(srcCopy, src)
+		return dst.DecodeText(nil, srcCopy)
+	}
+
+	return errors.Errorf("cannot scan %T", src)
+}
+
+// Value implements the database/sql/driver Valuer interface.
+func (src Nummultirange) Value() (driver.Value, error) {
+	return EncodeValueText(src)
+}
+
+// MarshalJSON encodes src as a JSON array of its ranges, each in the form
+// written by Numrange.MarshalJSON.
+func (src Nummultirange) MarshalJSON() ([]byte, error) {
+	return marshalMultirangeJSON(multirangeValue[Numrange](src))
+}
+
+func (dst *Nummultirange) UnmarshalJSON(b []byte) error {
+	m, err := unmarshalMultirangeJSON[Numrange, Numeric](b)
+	if err != nil {
+		return err
+	}
+
+	*dst = Nummultirange(m)
+	return nil
+}
+
+// Canonical returns src in the form the server stores it. Each range is
+// canonicalized, empty ranges are dropped, and the rest are sorted with
+// overlapping and adjacent ranges merged. It returns an error for a range the
+// server would reject.
+func (src Nummultirange) Canonical() (Nummultirange, error) {
+	m, err := canonicalMultirange(numRangeSubtype, multirangeValue[Numrange](src), true)
+	if err != nil {
+		return Nummultirange{}, err
+	}
+	return Nummultirange(m), nil
+}
+
+// IsEmpty reports whether src contains no values.
+func (src Nummultirange) IsEmpty() bool {
+	if src.Status != Present {
+		return false
+	}
+	for _, r := range src.Ranges {
+		if !r.IsEmpty() {
+			return false
+		}
+	}
+	return true
+}
+
+// Contains reports whether v is in one of the ranges of src, as the server's @>
+// operator does.
+func (src Nummultirange) Contains(v decimal.Decimal) bool {
+	if src.Status != Present {
+		return false
+	}
+	for _, r := range src.Ranges {
+		if r.Contains(v) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,209 @@
+package tstype
+
+import (
+	"database/sql/driver"
+
+	"github.com/jackc/pgtype"
+	"github.com/shopspring/decimal"
+
+	errors "golang.org/x/xerrors"
+)
+
+// Numrange represents the PostgreSQL numrange type. Lower and Upper are only
+// used when LowerType and UpperType are pgtype.Inclusive or pgtype.Exclusive.
+// An empty range has both bound types set to pgtype.Empty.
+type Numrange struct {
+	Lower     Numeric
+	Upper     Numeric
+	LowerType pgtype.BoundType
+	UpperType pgtype.BoundType
+	Status    Status
+}
+
+func (dst *Numrange) Set(src interface{}) error {
+	if src == nil {
+		*dst = Numrange{Status: Null}
+		return nil
+	}
+
+	switch value := src.(type) {
+	case Numrange:
+		*dst = value
+	case *Numrange:
+		if value == nil {
+			*dst = Numrange{Status: Null}
+		} else {
+			*dst = *value
+		}
+	case string:
+		return dst.DecodeText(nil, []byte(value))
+	case *string:
+		if value == nil {
+			*dst = Numrange{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	default:
+		return errors.Errorf("cannot convert %v to Numrange", src)
+	}
+
+	return nil
+}
+
+func (dst Numrange) Get() interface{} {
+	switch dst.Status {
+	case Present:
+		return dst
+	case Null:
+		return nil
+	default:
+		return dst.Status
+	}
+}
+
+func (src *Numrange) AssignTo(dst interface{}) error {
+	switch src.Status {
+	case Present:
+		switch v := dst.(type) {
+		case *Numrange:
+			*v = *src
+			return nil
+		case *string:
+			buf, err := src.EncodeText(nil, nil)
+			if err != nil {
+				return err
+			}
+			*v = string(buf)
+			return nil
+		default:
+			if nextDst, retry := GetAssignToDstType(dst); retry {
+				return src.AssignTo(nextDst)
+			}
+			return errors.Errorf("unable to assign to %T", dst)
+		}
+	case Null:
+		return NullAssignTo(dst)
+	}
+
+	return errors.Errorf("cannot decode %#v into %T", src, dst)
+}
+
+func (dst *Numrange) DecodeText(ci *pgtype.ConnInfo, src []byte) error {
+	r, err := decodeRangeText[Numeric](ci, src)
+	if err != nil {
+		return err
+	}
+
+	*dst = Numrange(r)
+	return nil
+}
+
+func (dst *Numrange) DecodeBinary(ci *pgtype.ConnInfo, src []byte) error {
+	r, err := decodeRangeBinary[Numeric](ci, src)
+	if err != nil {
+		return err
+	}
+
+	*dst = Numrange(r)
+	return nil
+}
+
+func (src Numrange) EncodeText(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	return encodeRangeText(ci, buf, rangeValue[Numeric](src))
+}
+
+func (src Numrange) EncodeBinary(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	return encodeRangeBinary(ci, buf, rangeValue[Numeric](src))
+}
+
+// Scan implements the database/sql Scanner interface.
+func (dst *Numrange) Scan(src interface{}) error {
+	if src == nil {
+		*dst = Numrange{Status: Null}
+		return nil
+	}
+
+	switch src := src.(type) {
+	case string:
+		return dst.DecodeText(nil, []byte(src))
+	case []byte:
+		srcCopy := make([]byte, len(src))
+		copy(srcCopy, src)
+		return dst.DecodeText(nil, srcCopy)
+	}
+
+	return errors.Errorf("cannot scan %T", src)
+}
+
+// Value implements the database/sql/driver Valuer interface.
+func (src Numrange) Value() (driver.Value, error) {
+	return EncodeValueText(src)
+}
+
+// MarshalJSON encodes src as {"lower": "1.5", "upper": "5", "bounds": "[)"},
+// with null for an unbounded side, or as {"empty": true}.
+func (src Numrange) MarshalJSON() ([]byte, error) {
+	return marshalRangeJSON(rangeValue[Numeric](src))
+}
+
+func (dst *Numrange) UnmarshalJSON(b []byte) error {
+	r, err := unmarshalRangeJSON[Numeric](b)
+	if err != nil {
+		return err
+	}
+
+	*dst = Numrange(r)
+	return nil
+}
+
+// Canonical returns src in the form the server stores it. A range with no
+// values becomes empty. It returns an error for a range the server would
+// reject, such as one with a lower bound greater than its upper bound.
+func (src Numrange) Canonical() (Numrange, error) {
+	r, err := numRangeSubtype.makeRange(rangeValue[Numeric](src), true)
+	if err != nil {
+		return Numrange{}, err
+	}
+	return Numrange(r), nil
+}
+
+// IsEmpty reports whether src contains no values.
+func (src Numrange) IsEmpty() bool {
+	r := numRangeSubtype.normalize(rangeValue[Numeric](src))
+	return r.Status == Present && r.isEmpty()
+}
+
+// Contains reports whether v is in src, as the server's @> operator does.
+func (src Numrange) Contains(v decimal.Decimal) bool {
+	return numRangeSubtype.contains(rangeValue[Numeric](src), Numeric{Decimal: v, Status: Present})
+}
+
+// ContainsRange reports whether other is entirely within src, as the server's
+// @> operator does. Every range contains the empty range.
+func (src Numrange) ContainsRange(other Numrange) bool {
+	return numRangeSubtype.containsRange(rangeValue[Numeric](src), rangeValue[Numeric](other))
+}
+
+// Overlaps reports whether src and other have values in common, as the server's
+// && operator does.
+func (src Numrange) Overlaps(other Numrange) bool {
+	return numRangeSubtype.overlaps(rangeValue[Numeric](src), rangeValue[Numeric](other))
+}
+
+// Adjacent reports whether src and other do not overlap and no value lies
+// between them, as the server's -|- operator does.
+func (src Numrange) Adjacent(other Numrange) bool {
+	return numRangeSubtype.adjacent(rangeValue[Numeric](src), rangeValue[Numeric](other))
+}
+
+// Intersect returns the values src and other have in common, as the server's *
+// operator does. The result is Null if either range is Null.
+func (src Numrange) Intersect(other Numrange) Numrange {
+	return Numrange(numRangeSubtype.intersect(rangeValue[Numeric](src), rangeValue[Numeric](other)))
+}
+
+var numRangeSubtype = rangeSubtype[Numeric]{
+	cmp: func(a, b Numeric) int {
+		return a.Decimal.Cmp(b.Decimal)
+	},
+}
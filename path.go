@@ -0,0 +1,258 @@
+package tstype
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"math"
+
+	"github.com/jackc/pgtype"
+
+	errors "golang.org/x/xerrors"
+)
+
+// Path represents the PostgreSQL path type. A closed path connects its last
+// point back to the first. JSON is a GeoJSON LineString for an open path and
+// a Polygon for a closed one.
+type Path struct {
+	P      []pgtype.Vec2
+	Closed bool
+	Status Status
+}
+
+func (dst *Path) Set(src interface{}) error {
+	if src == nil {
+		*dst = Path{Status: Null}
+		return nil
+	}
+
+	switch value := src.(type) {
+	case Path:
+		*dst = value
+	case *Path:
+		if value == nil {
+			*dst = Path{Status: Null}
+		} else {
+			*dst = *value
+		}
+	case string:
+		return dst.DecodeText(nil, []byte(value))
+	case *string:
+		if value == nil {
+			*dst = Path{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	default:
+		if originalSrc, ok := underlyingStringType(src); ok {
+			return dst.Set(originalSrc)
+		}
+		return errors.Errorf("cannot convert %v to Path", value)
+	}
+
+	return nil
+}
+
+func (dst Path) Get() interface{} {
+	switch dst.Status {
+	case Present:
+		return dst
+	case Null:
+		return nil
+	default:
+		return dst.Status
+	}
+}
+
+func (src *Path) AssignTo(dst interface{}) error {
+	switch src.Status {
+	case Present:
+		switch v := dst.(type) {
+		case *Path:
+			*v = *src
+			return nil
+		case *string:
+			buf, err := src.EncodeText(nil, nil)
+			if err != nil {
+				return err
+			}
+			*v = string(buf)
+			return nil
+		default:
+			if nextDst, retry := GetAssignToDstType(dst); retry {
+				return src.AssignTo(nextDst)
+			}
+			return errors.Errorf("unable to assign to %T", dst)
+		}
+	case Null:
+		return NullAssignTo(dst)
+	}
+
+	return errors.Errorf("cannot decode %#v into %T", src, dst)
+}
+
+// DecodeText accepts [(x1,y1),...] for an open path, and ((x1,y1),...) or
+// (x1,y1),... for a closed one.
+func (dst *Path) DecodeText(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Path{Status: Null}
+		return nil
+	}
+
+	p := geoParser{typeName: "path", s: string(src)}
+	points, open, err := p.parsePoints(-1, true)
+	if err != nil {
+		return err
+	}
+	if err := p.end(); err != nil {
+		return err
+	}
+
+	*dst = Path{P: points, Closed: !open, Status: Present}
+	return nil
+}
+
+// DecodeBinary decodes a closed flag byte followed by the points.
+func (dst *Path) DecodeBinary(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Path{Status: Null}
+		return nil
+	}
+
+	if len(src) < 1 {
+		return errors.Errorf("invalid length for path: %v", len(src))
+	}
+	points, err := decodeVec2sBinary("path", src[1:])
+	if err != nil {
+		return err
+	}
+
+	*dst = Path{P: points, Closed: src[0] != 0, Status: Present}
+	return nil
+}
+
+func (src Path) EncodeText(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	if len(src.P) == 0 {
+		return nil, errors.New("path must have at least one point")
+	}
+
+	if src.Closed {
+		buf = append(buf, '(')
+		buf = appendVec2sText(buf, src.P)
+		return append(buf, ')'), nil
+	}
+	buf = append(buf, '[')
+	buf = appendVec2sText(buf, src.P)
+	return append(buf, ']'), nil
+}
+
+func (src Path) EncodeBinary(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	if len(src.P) == 0 {
+		return nil, errors.New("path must have at least one point")
+	}
+
+	var closed byte
+	if src.Closed {
+		closed = 1
+	}
+	return appendVec2sBinary(append(buf, closed), src.P), nil
+}
+
+// Scan implements the database/sql Scanner interface.
+func (dst *Path) Scan(src interface{}) error {
+	if src == nil {
+		*dst = Path{Status: Null}
+		return nil
+	}
+
+	switch src := src.(type) {
+	case string:
+		return dst.DecodeText(nil, []byte(src))
+	case []byte:
+		srcCopy := make([]byte, len(src))
+		copy(srcCopy, src)
+		return dst.DecodeText(nil, srcCopy)
+	}
+
+	return errors.Errorf("cannot scan %T", src)
+}
+
+// Value implements the database/sql/driver Valuer interface.
+func (src Path) Value() (driver.Value, error) {
+	return EncodeValueText(src)
+}
+
+func (src Path) MarshalJSON() ([]byte, error) {
+	switch src.Status {
+	case Present:
+		if src.Closed {
+			return marshalGeoJSON("Polygon", geoJSONRing(src.P))
+		}
+		return marshalGeoJSON("LineString", geoJSONPositions(src.P))
+	case Null:
+		return []byte("null"), nil
+	}
+
+	return nil, errBadStatus
+}
+
+func (dst *Path) UnmarshalJSON(b []byte) error {
+	g, ok, err := unmarshalGeoJSON(b)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		*dst = Path{Status: Null}
+		return nil
+	}
+
+	switch g.Type {
+	case "LineString":
+		var positions []geoJSONPosition
+		if err := json.Unmarshal(g.Coordinates, &positions); err != nil {
+			return err
+		}
+		if len(positions) == 0 {
+			return errors.New("GeoJSON LineString cannot be empty")
+		}
+		*dst = Path{P: vec2sFromGeoJSON(positions), Status: Present}
+	case "Polygon":
+		var rings [][]geoJSONPosition
+		if err := json.Unmarshal(g.Coordinates, &rings); err != nil {
+			return err
+		}
+		points, err := vec2sFromGeoJSONRing(rings)
+		if err != nil {
+			return err
+		}
+		*dst = Path{P: points, Closed: true, Status: Present}
+	default:
+		return unsupportedGeoJSON("Path", g)
+	}
+
+	return nil
+}
+
+// Distance returns the distance from p to the nearest segment of src, as the
+// server's <-> operator does. It is NaN if either is not Present.
+func (src Path) Distance(p Point) float64 {
+	if src.Status != Present || p.Status != Present {
+		return math.NaN()
+	}
+	return pathDistance(p.P, src.P, src.Closed)
+}
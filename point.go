@@ -0,0 +1,223 @@
+package tstype
+
+import (
+	"database/sql/driver"
+	"math"
+
+	"github.com/jackc/pgtype"
+
+	errors "golang.org/x/xerrors"
+)
+
+// Point represents the PostgreSQL point type. JSON is a GeoJSON Point.
+type Point struct {
+	P      pgtype.Vec2
+	Status Status
+}
+
+func (dst *Point) Set(src interface{}) error {
+	if src == nil {
+		*dst = Point{Status: Null}
+		return nil
+	}
+
+	switch value := src.(type) {
+	case Point:
+		*dst = value
+	case *Point:
+		if value == nil {
+			*dst = Point{Status: Null}
+		} else {
+			*dst = *value
+		}
+	case pgtype.Vec2:
+		*dst = Point{P: value, Status: Present}
+	case *pgtype.Vec2:
+		if value == nil {
+			*dst = Point{Status: Null}
+		} else {
+			*dst = Point{P: *value, Status: Present}
+		}
+	case string:
+		return dst.DecodeText(nil, []byte(value))
+	case *string:
+		if value == nil {
+			*dst = Point{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	default:
+		if originalSrc, ok := underlyingStringType(src); ok {
+			return dst.Set(originalSrc)
+		}
+		return errors.Errorf("cannot convert %v to Point", value)
+	}
+
+	return nil
+}
+
+func (dst Point) Get() interface{} {
+	switch dst.Status {
+	case Present:
+		return dst
+	case Null:
+		return nil
+	default:
+		return dst.Status
+	}
+}
+
+func (src *Point) AssignTo(dst interface{}) error {
+	switch src.Status {
+	case Present:
+		switch v := dst.(type) {
+		case *Point:
+			*v = *src
+			return nil
+		case *pgtype.Vec2:
+			*v = src.P
+			return nil
+		case *string:
+			buf, err := src.EncodeText(nil, nil)
+			if err != nil {
+				return err
+			}
+			*v = string(buf)
+			return nil
+		default:
+			if nextDst, retry := GetAssignToDstType(dst); retry {
+				return src.AssignTo(nextDst)
+			}
+			return errors.Errorf("unable to assign to %T", dst)
+		}
+	case Null:
+		return NullAssignTo(dst)
+	}
+
+	return errors.Errorf("cannot decode %#v into %T", src, dst)
+}
+
+// DecodeText accepts (x,y) and x,y.
+func (dst *Point) DecodeText(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Point{Status: Null}
+		return nil
+	}
+
+	p := geoParser{typeName: "point", s: string(src)}
+	v, err := p.parsePoint()
+	if err != nil {
+		return err
+	}
+	if err := p.end(); err != nil {
+		return err
+	}
+
+	*dst = Point{P: v, Status: Present}
+	return nil
+}
+
+func (dst *Point) DecodeBinary(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Point{Status: Null}
+		return nil
+	}
+
+	fs, err := readFloat8s("point", src, 2)
+	if err != nil {
+		return err
+	}
+
+	*dst = Point{P: pgtype.Vec2{X: fs[0], Y: fs[1]}, Status: Present}
+	return nil
+}
+
+func (src Point) EncodeText(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	return appendVec2Text(buf, src.P), nil
+}
+
+func (src Point) EncodeBinary(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	return appendVec2Binary(buf, src.P), nil
+}
+
+// Scan implements the database/sql Scanner interface.
+func (dst *Point) Scan(src interface{}) error {
+	if src == nil {
+		*dst = Point{Status: Null}
+		return nil
+	}
+
+	switch src := src.(type) {
+	case string:
+		return dst.DecodeText(nil, []byte(src))
+	case []byte:
+		srcCopy := make([]byte, len(src))
+		copy(srcCopy, src)
+		return dst.DecodeText(nil, srcCopy)
+	}
+
+	return errors.Errorf("cannot scan %T", src)
+}
+
+// Value implements the database/sql/driver Valuer interface.
+func (src Point) Value() (driver.Value, error) {
+	return EncodeValueText(src)
+}
+
+func (src Point) MarshalJSON() ([]byte, error) {
+	switch src.Status {
+	case Present:
+		return marshalGeoJSON("Point", geoJSONPosition(src.P))
+	case Null:
+		return []byte("null"), nil
+	}
+
+	return nil, errBadStatus
+}
+
+func (dst *Point) UnmarshalJSON(b []byte) error {
+	g, ok, err := unmarshalGeoJSON(b)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		*dst = Point{Status: Null}
+		return nil
+	}
+	if g.Type != "Point" {
+		return unsupportedGeoJSON("Point", g)
+	}
+
+	var position geoJSONPosition
+	if err := position.UnmarshalJSON(g.Coordinates); err != nil {
+		return err
+	}
+
+	*dst = Point{P: pgtype.Vec2(position), Status: Present}
+	return nil
+}
+
+// Distance returns the distance between src and other, as the server's <->
+// operator does. It is NaN if either is not Present.
+func (src Point) Distance(other Point) float64 {
+	if src.Status != Present || other.Status != Present {
+		return math.NaN()
+	}
+	return vec2Distance(src.P, other.P)
+}
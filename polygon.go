@@ -0,0 +1,254 @@
+package tstype
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"math"
+
+	"github.com/jackc/pgtype"
+
+	errors "golang.org/x/xerrors"
+)
+
+// Polygon represents the PostgreSQL polygon type. P holds the vertices; the
+// last one connects back to the first. JSON is a GeoJSON Polygon.
+type Polygon struct {
+	P      []pgtype.Vec2
+	Status Status
+}
+
+func (dst *Polygon) Set(src interface{}) error {
+	if src == nil {
+		*dst = Polygon{Status: Null}
+		return nil
+	}
+
+	switch value := src.(type) {
+	case Polygon:
+		*dst = value
+	case *Polygon:
+		if value == nil {
+			*dst = Polygon{Status: Null}
+		} else {
+			*dst = *value
+		}
+	case []pgtype.Vec2:
+		if value == nil {
+			*dst = Polygon{Status: Null}
+			return nil
+		}
+		if len(value) == 0 {
+			return errors.New("polygon must have at least one point")
+		}
+		points := make([]pgtype.Vec2, len(value))
+		copy(points, value)
+		*dst = Polygon{P: points, Status: Present}
+	case string:
+		return dst.DecodeText(nil, []byte(value))
+	case *string:
+		if value == nil {
+			*dst = Polygon{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	default:
+		if originalSrc, ok := underlyingStringType(src); ok {
+			return dst.Set(originalSrc)
+		}
+		return errors.Errorf("cannot convert %v to Polygon", value)
+	}
+
+	return nil
+}
+
+func (dst Polygon) Get() interface{} {
+	switch dst.Status {
+	case Present:
+		return dst
+	case Null:
+		return nil
+	default:
+		return dst.Status
+	}
+}
+
+func (src *Polygon) AssignTo(dst interface{}) error {
+	switch src.Status {
+	case Present:
+		switch v := dst.(type) {
+		case *Polygon:
+			*v = *src
+			return nil
+		case *[]pgtype.Vec2:
+			*v = make([]pgtype.Vec2, len(src.P))
+			copy(*v, src.P)
+			return nil
+		case *string:
+			buf, err := src.EncodeText(nil, nil)
+			if err != nil {
+				return err
+			}
+			*v = string(buf)
+			return nil
+		default:
+			if nextDst, retry := GetAssignToDstType(dst); retry {
+				return src.AssignTo(nextDst)
+			}
+			return errors.Errorf("unable to assign to %T", dst)
+		}
+	case Null:
+		return NullAssignTo(dst)
+	}
+
+	return errors.Errorf("cannot decode %#v into %T", src, dst)
+}
+
+// DecodeText accepts ((x1,y1),...), (x1,y1),... and x1,y1,....
+func (dst *Polygon) DecodeText(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Polygon{Status: Null}
+		return nil
+	}
+
+	p := geoParser{typeName: "polygon", s: string(src)}
+	points, _, err := p.parsePoints(-1, false)
+	if err != nil {
+		return err
+	}
+	if err := p.end(); err != nil {
+		return err
+	}
+
+	*dst = Polygon{P: points, Status: Present}
+	return nil
+}
+
+func (dst *Polygon) DecodeBinary(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Polygon{Status: Null}
+		return nil
+	}
+
+	points, err := decodeVec2sBinary("polygon", src)
+	if err != nil {
+		return err
+	}
+
+	*dst = Polygon{P: points, Status: Present}
+	return nil
+}
+
+func (src Polygon) EncodeText(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	if len(src.P) == 0 {
+		return nil, errors.New("polygon must have at least one point")
+	}
+
+	buf = append(buf, '(')
+	buf = appendVec2sText(buf, src.P)
+	return append(buf, ')'), nil
+}
+
+func (src Polygon) EncodeBinary(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	if len(src.P) == 0 {
+		return nil, errors.New("polygon must have at least one point")
+	}
+
+	return appendVec2sBinary(buf, src.P), nil
+}
+
+// Scan implements the database/sql Scanner interface.
+func (dst *Polygon) Scan(src interface{}) error {
+	if src == nil {
+		*dst = Polygon{Status: Null}
+		return nil
+	}
+
+	switch src := src.(type) {
+	case string:
+		return dst.DecodeText(nil, []byte(src))
+	case []byte:
+		srcCopy := make([]byte, len(src))
+		copy(srcCopy, src)
+		return dst.DecodeText(nil, srcCopy)
+	}
+
+	return errors.Errorf("cannot scan %T", src)
+}
+
+// Value implements the database/sql/driver Valuer interface.
+func (src Polygon) Value() (driver.Value, error) {
+	return EncodeValueText(src)
+}
+
+// MarshalJSON encodes src as a GeoJSON Polygon with a single ring. The ring
+// keeps the order of the vertices and repeats the first one at the end.
+func (src Polygon) MarshalJSON() ([]byte, error) {
+	switch src.Status {
+	case Present:
+		return marshalGeoJSON("Polygon", geoJSONRing(src.P))
+	case Null:
+		return []byte("null"), nil
+	}
+
+	return nil, errBadStatus
+}
+
+func (dst *Polygon) UnmarshalJSON(b []byte) error {
+	g, ok, err := unmarshalGeoJSON(b)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		*dst = Polygon{Status: Null}
+		return nil
+	}
+	if g.Type != "Polygon" {
+		return unsupportedGeoJSON("Polygon", g)
+	}
+
+	var rings [][]geoJSONPosition
+	if err := json.Unmarshal(g.Coordinates, &rings); err != nil {
+		return err
+	}
+	points, err := vec2sFromGeoJSONRing(rings)
+	if err != nil {
+		return err
+	}
+
+	*dst = Polygon{P: points, Status: Present}
+	return nil
+}
+
+// ContainsPoint reports whether p is inside src or on its boundary, as the
+// server's @> operator does, including its tolerance for rounding errors.
+func (src Polygon) ContainsPoint(p Point) bool {
+	if src.Status != Present || p.Status != Present {
+		return false
+	}
+	return pointInside(p.P, src.P) != 0
+}
+
+// Distance returns the distance from p to src, which is 0 if src contains p,
+// as the server's <-> operator does. It is NaN if either is not Present.
+func (src Polygon) Distance(p Point) float64 {
+	if src.Status != Present || p.Status != Present {
+		return math.NaN()
+	}
+	return polygonDistance(p.P, src.P)
+}
@@ -0,0 +1,652 @@
+package tstype
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgio"
+	"github.com/jackc/pgtype"
+
+	errors "golang.org/x/xerrors"
+)
+
+// rangeValue has the same layout as the range types, which convert to and from
+// it to share the codecs and operators below. A range is empty when LowerType
+// is pgtype.Empty. Lower and Upper are only meaningful when their bound type is
+// pgtype.Inclusive or pgtype.Exclusive.
+type rangeValue[T any] struct {
+	Lower     T
+	Upper     T
+	LowerType pgtype.BoundType
+	UpperType pgtype.BoundType
+	Status    Status
+}
+
+type rangeBound interface {
+	pgtype.TextEncoder
+	pgtype.BinaryEncoder
+	json.Marshaler
+}
+
+type rangeBoundPtr[T any] interface {
+	*T
+	pgtype.TextDecoder
+	pgtype.BinaryDecoder
+	json.Unmarshaler
+}
+
+func isFiniteBound(bt pgtype.BoundType) bool {
+	return bt == pgtype.Inclusive || bt == pgtype.Exclusive
+}
+
+func decodeRangeText[T any, PT rangeBoundPtr[T]](ci *pgtype.ConnInfo, src []byte) (rangeValue[T], error) {
+	if src == nil {
+		return rangeValue[T]{Status: Null}, nil
+	}
+
+	if strings.EqualFold(strings.TrimSpace(string(src)), "empty") {
+		return rangeValue[T]{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Status: Present}, nil
+	}
+
+	utr, err := pgtype.ParseUntypedTextRange(string(src))
+	if err != nil {
+		return rangeValue[T]{}, err
+	}
+
+	r := rangeValue[T]{LowerType: utr.LowerType, UpperType: utr.UpperType, Status: Present}
+
+	if isFiniteBound(r.LowerType) {
+		if err := PT(&r.Lower).DecodeText(ci, []byte(utr.Lower)); err != nil {
+			return rangeValue[T]{}, err
+		}
+	}
+
+	if isFiniteBound(r.UpperType) {
+		if err := PT(&r.Upper).DecodeText(ci, []byte(utr.Upper)); err != nil {
+			return rangeValue[T]{}, err
+		}
+	}
+
+	return r, nil
+}
+
+func decodeRangeBinary[T any, PT rangeBoundPtr[T]](ci *pgtype.ConnInfo, src []byte) (rangeValue[T], error) {
+	if src == nil {
+		return rangeValue[T]{Status: Null}, nil
+	}
+
+	ubr, err := pgtype.ParseUntypedBinaryRange(src)
+	if err != nil {
+		return rangeValue[T]{}, err
+	}
+
+	r := rangeValue[T]{LowerType: ubr.LowerType, UpperType: ubr.UpperType, Status: Present}
+
+	if isFiniteBound(r.LowerType) {
+		if err := PT(&r.Lower).DecodeBinary(ci, ubr.Lower); err != nil {
+			return rangeValue[T]{}, err
+		}
+	}
+
+	if isFiniteBound(r.UpperType) {
+		if err := PT(&r.Upper).DecodeBinary(ci, ubr.Upper); err != nil {
+			return rangeValue[T]{}, err
+		}
+	}
+
+	return r, nil
+}
+
+func encodeRangeText[T rangeBound](ci *pgtype.ConnInfo, buf []byte, r rangeValue[T]) ([]byte, error) {
+	switch r.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	switch r.LowerType {
+	case pgtype.Exclusive, pgtype.Unbounded:
+		buf = append(buf, '(')
+	case pgtype.Inclusive:
+		buf = append(buf, '[')
+	case pgtype.Empty:
+		return append(buf, "empty"...), nil
+	default:
+		return nil, errors.Errorf("unknown lower bound type %v", r.LowerType)
+	}
+
+	inBoundBuf := make([]byte, 0, 32)
+
+	if r.LowerType != pgtype.Unbounded {
+		b, err := r.Lower.EncodeText(ci, inBoundBuf)
+		if err != nil {
+			return nil, err
+		} else if b == nil {
+			return nil, errors.Errorf("Lower cannot be null unless LowerType is Unbounded")
+		}
+		buf = appendRangeBoundText(buf, b)
+	}
+
+	buf = append(buf, ',')
+
+	if r.UpperType != pgtype.Unbounded {
+		b, err := r.Upper.EncodeText(ci, inBoundBuf)
+		if err != nil {
+			return nil, err
+		} else if b == nil {
+			return nil, errors.Errorf("Upper cannot be null unless UpperType is Unbounded")
+		}
+		buf = appendRangeBoundText(buf, b)
+	}
+
+	switch r.UpperType {
+	case pgtype.Exclusive, pgtype.Unbounded:
+		buf = append(buf, ')')
+	case pgtype.Inclusive:
+		buf = append(buf, ']')
+	default:
+		return nil, errors.Errorf("unknown upper bound type %v", r.UpperType)
+	}
+
+	return buf, nil
+}
+
+// appendRangeBoundText quotes a bound the way the server's range output does:
+// when it is empty or contains quotes, backslashes, brackets, parentheses,
+// commas or whitespace. Quotes and backslashes are doubled.
+func appendRangeBoundText(buf []byte, b []byte) []byte {
+	if len(b) > 0 && !bytes.ContainsAny(b, "\"\\()[], \t\n\r\v\f") {
+		return append(buf, b...)
+	}
+
+	buf = append(buf, '"')
+	for _, c := range b {
+		if c == '"' || c == '\\' {
+			buf = append(buf, c)
+		}
+		buf = append(buf, c)
+	}
+	return append(buf, '"')
+}
+
+// Flag bits of the range binary format.
+const (
+	rangeEmptyMask          = 0x01
+	rangeLowerInclusiveMask = 0x02
+	rangeUpperInclusiveMask = 0x04
+	rangeLowerUnboundedMask = 0x08
+	rangeUpperUnboundedMask = 0x10
+)
+
+func encodeRangeBinary[T rangeBound](ci *pgtype.ConnInfo, buf []byte, r rangeValue[T]) ([]byte, error) {
+	switch r.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	var rangeType byte
+	switch r.LowerType {
+	case pgtype.Inclusive:
+		rangeType |= rangeLowerInclusiveMask
+	case pgtype.Unbounded:
+		rangeType |= rangeLowerUnboundedMask
+	case pgtype.Exclusive:
+	case pgtype.Empty:
+		return append(buf, rangeEmptyMask), nil
+	default:
+		return nil, errors.Errorf("unknown LowerType: %v", r.LowerType)
+	}
+
+	switch r.UpperType {
+	case pgtype.Inclusive:
+		rangeType |= rangeUpperInclusiveMask
+	case pgtype.Unbounded:
+		rangeType |= rangeUpperUnboundedMask
+	case pgtype.Exclusive:
+	default:
+		return nil, errors.Errorf("unknown UpperType: %v", r.UpperType)
+	}
+
+	buf = append(buf, rangeType)
+
+	var err error
+
+	if r.LowerType != pgtype.Unbounded {
+		sp := len(buf)
+		buf = pgio.AppendInt32(buf, -1)
+
+		buf, err = r.Lower.EncodeBinary(ci, buf)
+		if err != nil {
+			return nil, err
+		}
+		if buf == nil {
+			return nil, errors.Errorf("Lower cannot be null unless LowerType is Unbounded")
+		}
+
+		pgio.SetInt32(buf[sp:], int32(len(buf[sp:])-4))
+	}
+
+	if r.UpperType != pgtype.Unbounded {
+		sp := len(buf)
+		buf = pgio.AppendInt32(buf, -1)
+
+		buf, err = r.Upper.EncodeBinary(ci, buf)
+		if err != nil {
+			return nil, err
+		}
+		if buf == nil {
+			return nil, errors.Errorf("Upper cannot be null unless UpperType is Unbounded")
+		}
+
+		pgio.SetInt32(buf[sp:], int32(len(buf[sp:])-4))
+	}
+
+	return buf, nil
+}
+
+// rangeJSON is the JSON form of a range: {"lower": 1, "upper": 5, "bounds":
+// "[)"}, with a null or missing bound for an unbounded side, or
+// {"empty": true}. Bounds are encoded with the bound type's MarshalJSON.
+type rangeJSON struct {
+	Lower  json.RawMessage `json:"lower,omitempty"`
+	Upper  json.RawMessage `json:"upper,omitempty"`
+	Bounds string          `json:"bounds,omitempty"`
+	Empty  bool            `json:"empty,omitempty"`
+}
+
+func marshalRangeJSON[T rangeBound](r rangeValue[T]) ([]byte, error) {
+	switch r.Status {
+	case Null:
+		return []byte("null"), nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	if r.LowerType == pgtype.Empty {
+		return json.Marshal(rangeJSON{Empty: true})
+	}
+
+	var rj rangeJSON
+	var err error
+	bounds := []byte("()")
+
+	switch r.LowerType {
+	case pgtype.Inclusive:
+		bounds[0] = '['
+		fallthrough
+	case pgtype.Exclusive:
+		if rj.Lower, err = r.Lower.MarshalJSON(); err != nil {
+			return nil, err
+		}
+	case pgtype.Unbounded:
+	default:
+		return nil, errors.Errorf("unknown lower bound type %v", r.LowerType)
+	}
+
+	switch r.UpperType {
+	case pgtype.Inclusive:
+		bounds[1] = ']'
+		fallthrough
+	case pgtype.Exclusive:
+		if rj.Upper, err = r.Upper.MarshalJSON(); err != nil {
+			return nil, err
+		}
+	case pgtype.Unbounded:
+	default:
+		return nil, errors.Errorf("unknown upper bound type %v", r.UpperType)
+	}
+
+	rj.Bounds = string(bounds)
+	return json.Marshal(rj)
+}
+
+// unmarshalRangeJSON decodes the form written by marshalRangeJSON. A missing
+// "bounds" defaults to "[)".
+func unmarshalRangeJSON[T any, PT rangeBoundPtr[T]](b []byte) (rangeValue[T], error) {
+	var prj *rangeJSON
+	if err := json.Unmarshal(b, &prj); err != nil {
+		return rangeValue[T]{}, err
+	}
+
+	if prj == nil {
+		return rangeValue[T]{Status: Null}, nil
+	}
+
+	if prj.Empty {
+		return rangeValue[T]{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Status: Present}, nil
+	}
+
+	bounds := prj.Bounds
+	if bounds == "" {
+		bounds = "[)"
+	}
+	if len(bounds) != 2 || !strings.ContainsRune("[(", rune(bounds[0])) || !strings.ContainsRune("])", rune(bounds[1])) {
+		return rangeValue[T]{}, errors.Errorf("invalid range bounds %q", prj.Bounds)
+	}
+
+	r := rangeValue[T]{LowerType: pgtype.Unbounded, UpperType: pgtype.Unbounded, Status: Present}
+
+	if len(prj.Lower) > 0 && string(prj.Lower) != "null" {
+		if err := PT(&r.Lower).UnmarshalJSON(prj.Lower); err != nil {
+			return rangeValue[T]{}, err
+		}
+		r.LowerType = pgtype.Exclusive
+		if bounds[0] == '[' {
+			r.LowerType = pgtype.Inclusive
+		}
+	}
+
+	if len(prj.Upper) > 0 && string(prj.Upper) != "null" {
+		if err := PT(&r.Upper).UnmarshalJSON(prj.Upper); err != nil {
+			return rangeValue[T]{}, err
+		}
+		r.UpperType = pgtype.Exclusive
+		if bounds[1] == ']' {
+			r.UpperType = pgtype.Inclusive
+		}
+	}
+
+	return r, nil
+}
+
+// rangeSubtype holds the subtype operations the range operators need. next is
+// nil for continuous subtypes. For discrete subtypes it returns the value
+// following v, false when v has no successor that changes the range, e.g.
+// infinity, or an error when v is the largest value of the subtype.
+type rangeSubtype[T any] struct {
+	cmp  func(a, b T) int
+	next func(v T) (T, bool, error)
+}
+
+// rangeBoundOf is a bound as the server's range_cmp_bounds sees it.
+type rangeBoundOf[T any] struct {
+	val       T
+	boundType pgtype.BoundType
+	lower     bool
+}
+
+func (r rangeValue[T]) lowerBound() rangeBoundOf[T] {
+	return rangeBoundOf[T]{val: r.Lower, boundType: r.LowerType, lower: true}
+}
+
+func (r rangeValue[T]) upperBound() rangeBoundOf[T] {
+	return rangeBoundOf[T]{val: r.Upper, boundType: r.UpperType, lower: false}
+}
+
+func (r rangeValue[T]) isEmpty() bool {
+	return r.LowerType == pgtype.Empty
+}
+
+// cmpBounds orders two bounds, taking their inclusivity into account, as the
+// server's range_cmp_bounds does. An exclusive lower bound sorts after an
+// inclusive one with the same value and an exclusive upper bound sorts before
+// an inclusive one.
+func (s rangeSubtype[T]) cmpBounds(b1, b2 rangeBoundOf[T]) int {
+	if b1.boundType == pgtype.Unbounded && b2.boundType == pgtype.Unbounded && b1.lower == b2.lower {
+		return 0
+	}
+	if b1.boundType == pgtype.Unbounded {
+		if b1.lower {
+			return -1
+		}
+		return 1
+	}
+	if b2.boundType == pgtype.Unbounded {
+		if b2.lower {
+			return 1
+		}
+		return -1
+	}
+
+	if result := s.cmp(b1.val, b2.val); result != 0 {
+		return result
+	}
+
+	b1Exclusive := b1.boundType == pgtype.Exclusive
+	b2Exclusive := b2.boundType == pgtype.Exclusive
+	switch {
+	case b1Exclusive && b2Exclusive:
+		if b1.lower == b2.lower {
+			return 0
+		}
+		if b1.lower {
+			return 1
+		}
+		return -1
+	case b1Exclusive:
+		if b1.lower {
+			return 1
+		}
+		return -1
+	case b2Exclusive:
+		if b2.lower {
+			return -1
+		}
+		return 1
+	}
+
+	return 0
+}
+
+// makeRange checks and canonicalizes a non-empty range as the server's
+// make_range does. A range whose bounds are equal but not both inclusive is
+// empty, as is a discrete range with no values. Discrete bounds are made
+// inclusive lower and exclusive upper. When strict is false, a lower bound
+// greater than the upper bound makes the range empty and a bound with no
+// successor is left as it is, instead of returning an error.
+func (s rangeSubtype[T]) makeRange(r rangeValue[T], strict bool) (rangeValue[T], error) {
+	if r.Status != Present || r.isEmpty() {
+		return r, nil
+	}
+
+	empty := rangeValue[T]{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Status: Present}
+
+	checkEmpty := func() (bool, error) {
+		if !isFiniteBound(r.LowerType) || !isFiniteBound(r.UpperType) {
+			return false, nil
+		}
+		c := s.cmp(r.Lower, r.Upper)
+		if c > 0 {
+			if strict {
+				return false, errors.New("range lower bound must be less than or equal to range upper bound")
+			}
+			return true, nil
+		}
+		return c == 0 && !(r.LowerType == pgtype.Inclusive && r.UpperType == pgtype.Inclusive), nil
+	}
+
+	if isEmpty, err := checkEmpty(); err != nil || isEmpty {
+		return empty, err
+	}
+
+	if s.next == nil {
+		return r, nil
+	}
+
+	if r.LowerType == pgtype.Exclusive {
+		v, ok, err := s.next(r.Lower)
+		if err != nil && strict {
+			return rangeValue[T]{}, err
+		}
+		if err == nil && ok {
+			r.Lower, r.LowerType = v, pgtype.Inclusive
+		}
+	}
+
+	if r.UpperType == pgtype.Inclusive {
+		v, ok, err := s.next(r.Upper)
+		if err != nil && strict {
+			return rangeValue[T]{}, err
+		}
+		if err == nil && ok {
+			r.Upper, r.UpperType = v, pgtype.Exclusive
+		}
+	}
+
+	if isEmpty, _ := checkEmpty(); isEmpty {
+		return empty, nil
+	}
+
+	return r, nil
+}
+
+func (s rangeSubtype[T]) normalize(r rangeValue[T]) rangeValue[T] {
+	r, _ = s.makeRange(r, false)
+	return r
+}
+
+func (s rangeSubtype[T]) contains(r rangeValue[T], v T) bool {
+	r = s.normalize(r)
+	if r.Status != Present || r.isEmpty() {
+		return false
+	}
+
+	if isFiniteBound(r.LowerType) {
+		c := s.cmp(r.Lower, v)
+		if c > 0 || (c == 0 && r.LowerType == pgtype.Exclusive) {
+			return false
+		}
+	}
+
+	if isFiniteBound(r.UpperType) {
+		c := s.cmp(r.Upper, v)
+		if c < 0 || (c == 0 && r.UpperType == pgtype.Exclusive) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s rangeSubtype[T]) containsRange(r1, r2 rangeValue[T]) bool {
+	r1, r2 = s.normalize(r1), s.normalize(r2)
+	if r1.Status != Present || r2.Status != Present {
+		return false
+	}
+	if r2.isEmpty() {
+		return true
+	}
+	if r1.isEmpty() {
+		return false
+	}
+
+	return s.cmpBounds(r1.lowerBound(), r2.lowerBound()) <= 0 &&
+		s.cmpBounds(r1.upperBound(), r2.upperBound()) >= 0
+}
+
+func (s rangeSubtype[T]) overlaps(r1, r2 rangeValue[T]) bool {
+	r1, r2 = s.normalize(r1), s.normalize(r2)
+	if r1.Status != Present || r2.Status != Present || r1.isEmpty() || r2.isEmpty() {
+		return false
+	}
+
+	if s.cmpBounds(r1.lowerBound(), r2.lowerBound()) >= 0 &&
+		s.cmpBounds(r1.lowerBound(), r2.upperBound()) <= 0 {
+		return true
+	}
+
+	return s.cmpBounds(r2.lowerBound(), r1.lowerBound()) >= 0 &&
+		s.cmpBounds(r2.lowerBound(), r1.upperBound()) <= 0
+}
+
+func (s rangeSubtype[T]) adjacent(r1, r2 rangeValue[T]) bool {
+	r1, r2 = s.normalize(r1), s.normalize(r2)
+	if r1.Status != Present || r2.Status != Present || r1.isEmpty() || r2.isEmpty() {
+		return false
+	}
+
+	return s.boundsAdjacent(r1.upperBound(), r2.lowerBound()) ||
+		s.boundsAdjacent(r2.upperBound(), r1.lowerBound())
+}
+
+// boundsAdjacent reports whether no value lies between upper and lower, as the
+// server's bounds_adjacent does.
+func (s rangeSubtype[T]) boundsAdjacent(upper, lower rangeBoundOf[T]) bool {
+	if !isFiniteBound(upper.boundType) || !isFiniteBound(lower.boundType) {
+		return false
+	}
+
+	c := s.cmp(upper.val, lower.val)
+	switch {
+	case c == 0:
+		return upper.boundType != lower.boundType
+	case c < 0 && s.next != nil:
+		between := rangeValue[T]{Lower: upper.val, Upper: lower.val, Status: Present}
+		between.LowerType, between.UpperType = pgtype.Inclusive, pgtype.Inclusive
+		if upper.boundType == pgtype.Inclusive {
+			between.LowerType = pgtype.Exclusive
+		}
+		if lower.boundType == pgtype.Inclusive {
+			between.UpperType = pgtype.Exclusive
+		}
+		return s.normalize(between).isEmpty()
+	}
+
+	return false
+}
+
+func (s rangeSubtype[T]) intersect(r1, r2 rangeValue[T]) rangeValue[T] {
+	if r1.Status != Present || r2.Status != Present {
+		return rangeValue[T]{Status: Null}
+	}
+	if !s.overlaps(r1, r2) {
+		return rangeValue[T]{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Status: Present}
+	}
+	r1, r2 = s.normalize(r1), s.normalize(r2)
+
+	r := rangeValue[T]{Status: Present}
+
+	lower := r2.lowerBound()
+	if s.cmpBounds(r1.lowerBound(), r2.lowerBound()) >= 0 {
+		lower = r1.lowerBound()
+	}
+	upper := r2.upperBound()
+	if s.cmpBounds(r1.upperBound(), r2.upperBound()) <= 0 {
+		upper = r1.upperBound()
+	}
+
+	r.LowerType, r.UpperType = lower.boundType, upper.boundType
+	if isFiniteBound(r.LowerType) {
+		r.Lower = lower.val
+	}
+	if isFiniteBound(r.UpperType) {
+		r.Upper = upper.val
+	}
+
+	return s.normalize(r)
+}
+
+func cmpInt64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// cmpInfinityTime orders times that may be -infinity or infinity.
+func cmpInfinityTime(aInf, bInf pgtype.InfinityModifier, a, b time.Time) int {
+	switch {
+	case aInf < bInf:
+		return -1
+	case aInf > bInf:
+		return 1
+	case aInf != pgtype.None:
+		return 0
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,165 @@
+package tstype_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgtype"
+	"github.com/tossp/tstype"
+)
+
+func mustInt4range(t *testing.T, s string) tstype.Int4range {
+	t.Helper()
+	var r tstype.Int4range
+	if err := r.DecodeText(nil, []byte(s)); err != nil {
+		t.Fatalf("%q: %v", s, err)
+	}
+	return r
+}
+
+func TestInt4rangeCanonical(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected string
+	}{
+		{src: "(1,3]", expected: "[2,4)"},
+		{src: "[1,1]", expected: "[1,2)"},
+		{src: "(1,2)", expected: "empty"},
+		{src: "[1,1)", expected: "empty"},
+		{src: "(,5]", expected: "(,6)"},
+		{src: "[3,)", expected: "[3,)"},
+		{src: " EMPTY ", expected: "empty"},
+	}
+
+	for i, tt := range tests {
+		r, err := mustInt4range(t, tt.src).Canonical()
+		if err != nil {
+			t.Errorf("%d: %v", i, err)
+			continue
+		}
+		buf, err := r.EncodeText(nil, nil)
+		if err != nil {
+			t.Errorf("%d: %v", i, err)
+			continue
+		}
+		if string(buf) != tt.expected {
+			t.Errorf("%d: %q: expected %q, got %q", i, tt.src, tt.expected, buf)
+		}
+	}
+
+	for i, src := range []string{"[3,2]", "[1,2147483647]"} {
+		if _, err := mustInt4range(t, src).Canonical(); err == nil {
+			t.Errorf("%d: %q: expected error", i, src)
+		}
+	}
+}
+
+func TestInt4rangeOperators(t *testing.T) {
+	r := func(s string) tstype.Int4range { return mustInt4range(t, s) }
+
+	if !r("[1,5)").Contains(4) || r("[1,5)").Contains(5) || !r("(,5]").Contains(-100) || r("empty").Contains(1) {
+		t.Error("Contains")
+	}
+	if !r("[1,10)").ContainsRange(r("[2,3]")) || r("[1,10)").ContainsRange(r("[2,10]")) || !r("[1,2)").ContainsRange(r("empty")) {
+		t.Error("ContainsRange")
+	}
+	if !r("[1,5)").Overlaps(r("[4,8)")) || r("[1,5)").Overlaps(r("[5,8)")) || r("[1,5)").Overlaps(r("empty")) {
+		t.Error("Overlaps")
+	}
+	if !r("[1,5)").Adjacent(r("[5,8)")) || !r("[1,4]").Adjacent(r("(4,8)")) || !r("[1,4]").Adjacent(r("[5,8)")) || r("[1,5)").Adjacent(r("[6,8)")) {
+		t.Error("Adjacent")
+	}
+
+	buf, _ := r("[1,5)").Intersect(r("(2,8]")).EncodeText(nil, nil)
+	if string(buf) != "[3,5)" {
+		t.Errorf("Intersect: got %q", buf)
+	}
+	buf, _ = r("[1,5)").Intersect(r("[5,8]")).EncodeText(nil, nil)
+	if string(buf) != "empty" {
+		t.Errorf("Intersect: got %q", buf)
+	}
+}
+
+func TestNumrangeAdjacent(t *testing.T) {
+	var r1, r2 tstype.Numrange
+	r1.DecodeText(nil, []byte("[1.5,2.5]"))
+	r2.DecodeText(nil, []byte("(2.5,3)"))
+	if !r1.Adjacent(r2) {
+		t.Error("expected [1.5,2.5] -|- (2.5,3)")
+	}
+	r2.DecodeText(nil, []byte("[2.5,3)"))
+	if r1.Adjacent(r2) || !r1.Overlaps(r2) {
+		t.Error("expected [1.5,2.5] && [2.5,3)")
+	}
+}
+
+func TestTstzrangeCodecs(t *testing.T) {
+	src := tstype.Tstzrange{
+		Lower:     tstype.Timestamptz{Time: time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC), Status: tstype.Present},
+		Upper:     tstype.Timestamptz{InfinityModifier: pgtype.Infinity, Status: tstype.Present},
+		LowerType: pgtype.Inclusive,
+		UpperType: pgtype.Exclusive,
+		Status:    tstype.Present,
+	}
+
+	buf, err := src.EncodeBinary(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dst tstype.Tstzrange
+	if err := dst.DecodeBinary(nil, buf); err != nil {
+		t.Fatal(err)
+	}
+	if !dst.Lower.Time.Equal(src.Lower.Time) || dst.Upper.InfinityModifier != pgtype.Infinity || dst.LowerType != src.LowerType || dst.UpperType != src.UpperType {
+		t.Errorf("binary: expected %+v, got %+v", src, dst)
+	}
+
+	if !src.Contains(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)) || src.Contains(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Error("Contains")
+	}
+
+	buf, err = src.EncodeText(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dst.DecodeText(nil, buf); err != nil {
+		t.Fatalf("%q: %v", buf, err)
+	}
+	if !dst.Lower.Time.Equal(src.Lower.Time) || dst.Upper.InfinityModifier != pgtype.Infinity {
+		t.Errorf("text: %q: got %+v", buf, dst)
+	}
+
+	js, err := json.Marshal(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(js) != `{"lower":"2020-01-01T09:00:00Z","upper":"infinity","bounds":"[)"}` {
+		t.Errorf("json: got %s", js)
+	}
+	dst = tstype.Tstzrange{}
+	if err := json.Unmarshal(js, &dst); err != nil {
+		t.Fatal(err)
+	}
+	if !dst.Lower.Time.Equal(src.Lower.Time) || dst.UpperType != pgtype.Exclusive {
+		t.Errorf("json: got %+v", dst)
+	}
+}
+
+func TestDaterangeCanonical(t *testing.T) {
+	var r tstype.Daterange
+	if err := r.DecodeText(nil, []byte("(2020-01-31,infinity]")); err != nil {
+		t.Fatal(err)
+	}
+	c, err := r.Canonical()
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, _ := c.EncodeText(nil, nil)
+	if string(buf) != "[2020-02-01,infinity]" {
+		t.Errorf("got %q", buf)
+	}
+	if !c.Contains(time.Date(2020, 2, 1, 23, 0, 0, 0, time.UTC)) || c.Contains(time.Date(2020, 1, 31, 23, 0, 0, 0, time.UTC)) {
+		t.Error("Contains")
+	}
+}
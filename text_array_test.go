@@ -0,0 +1,127 @@
+package tstype_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/jackc/pgtype"
+	"github.com/tossp/tstype"
+)
+
+func TestTextArraySetAndAssignTo(t *testing.T) {
+	var a tstype.TextArray
+	if err := a.Set([][]string{{"a", "b"}, {"c", "d"}}); err != nil {
+		t.Fatal(err)
+	}
+	if len(a.Dimensions) != 2 || a.Dimensions[0].Length != 2 || len(a.Elements) != 4 {
+		t.Errorf("Set: got %+v", a)
+	}
+
+	var matrix [][]string
+	if err := a.AssignTo(&matrix); err != nil || !reflect.DeepEqual(matrix, [][]string{{"a", "b"}, {"c", "d"}}) {
+		t.Errorf("AssignTo: got %v, %v", matrix, err)
+	}
+
+	s := "x"
+	if err := a.Set([]*string{&s, nil}); err != nil || a.Elements[1].Status != tstype.Null {
+		t.Errorf("Set []*string: got %+v, %v", a, err)
+	}
+	var ptrs []*string
+	if err := a.AssignTo(&ptrs); err != nil || len(ptrs) != 2 || *ptrs[0] != "x" || ptrs[1] != nil {
+		t.Errorf("AssignTo []*string: got %v, %v", ptrs, err)
+	}
+
+	type label string
+	if err := a.Set([]label{"p", "q"}); err != nil || a.Elements[1].String != "q" {
+		t.Errorf("Set named elements: got %+v, %v", a, err)
+	}
+}
+
+func TestTextArrayCodecs(t *testing.T) {
+	a := tstype.TextArray{
+		Elements: []tstype.Text{
+			{String: "plain", Status: tstype.Present},
+			{String: "with space", Status: tstype.Present},
+			{String: "NULL", Status: tstype.Present},
+			{Status: tstype.Null},
+		},
+		Dimensions: []pgtype.ArrayDimension{{Length: 4, LowerBound: 1}},
+		Status:     tstype.Present,
+	}
+
+	buf, err := a.EncodeText(nil, nil)
+	if err != nil || string(buf) != `{plain,with space,"NULL",NULL}` {
+		t.Errorf("EncodeText: got %s, %v", buf, err)
+	}
+	var fromText tstype.TextArray
+	if err := fromText.DecodeText(nil, buf); err != nil || !reflect.DeepEqual(fromText, a) {
+		t.Errorf("DecodeText: got %+v, %v", fromText, err)
+	}
+
+	ci := pgtype.NewConnInfo()
+	bin, err := a.EncodeBinary(ci, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fromBinary tstype.TextArray
+	if err := fromBinary.DecodeBinary(ci, bin); err != nil || !reflect.DeepEqual(fromBinary, a) {
+		t.Errorf("DecodeBinary: got %+v, %v", fromBinary, err)
+	}
+
+	var v tstype.VarcharArray
+	if err := v.DecodeText(nil, []byte(`{{a,NULL},{"b,c",d}}`)); err != nil || len(v.Dimensions) != 2 || v.Elements[1].Status != tstype.Null || v.Elements[2].String != "b,c" {
+		t.Errorf("VarcharArray DecodeText: got %+v, %v", v, err)
+	}
+}
+
+func TestTextArrayJSON(t *testing.T) {
+	var a tstype.TextArray
+	if err := a.DecodeText(nil, []byte(`{{a,NULL},{b,c}}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	js, err := json.Marshal(a)
+	if err != nil || string(js) != `[["a",null],["b","c"]]` {
+		t.Errorf("MarshalJSON: got %s, %v", js, err)
+	}
+
+	var back tstype.TextArray
+	if err := json.Unmarshal(js, &back); err != nil || !reflect.DeepEqual(back, a) {
+		t.Errorf("UnmarshalJSON: got %+v, %v", back, err)
+	}
+
+	tests := []struct {
+		src      string
+		expected string
+	}{
+		{`[]`, `{}`},
+		{`[[]]`, `{}`},
+		{`["x"]`, `{x}`},
+	}
+	for i, tt := range tests {
+		var v tstype.VarcharArray
+		if err := json.Unmarshal([]byte(tt.src), &v); err != nil {
+			t.Errorf("%d: %v", i, err)
+			continue
+		}
+		if buf, _ := v.EncodeText(nil, nil); string(buf) != tt.expected {
+			t.Errorf("%d: expected %s, got %s", i, tt.expected, buf)
+		}
+	}
+
+	for i, s := range []string{`[["a"],"b"]`, `[["a"],["b","c"]]`, `["a",["b"]]`} {
+		var v tstype.TextArray
+		if err := json.Unmarshal([]byte(s), &v); err == nil {
+			t.Errorf("%d: %s: expected error", i, s)
+		}
+	}
+
+	var null tstype.TextArray
+	if err := json.Unmarshal([]byte(`null`), &null); err != nil || null.Status != tstype.Null {
+		t.Errorf("null: got %+v, %v", null, err)
+	}
+	if js, _ := json.Marshal(null); string(js) != "null" {
+		t.Errorf("MarshalJSON null: got %s", js)
+	}
+}
@@ -0,0 +1,324 @@
+package tstype
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgio"
+	"github.com/jackc/pgtype"
+
+	errors "golang.org/x/xerrors"
+)
+
+const microsecondsPerDay = 86400000000
+
+// Time represents the PostgreSQL time type. Microseconds is the number of
+// microseconds since midnight. 24:00:00 is a valid time in PostgreSQL, so
+// Microseconds ranges from 0 to 86400000000 inclusive.
+type Time struct {
+	Microseconds int64
+	Status       Status
+}
+
+// Set converts src into a Time. time.Time uses its wall clock and ignores its
+// location. time.Duration is interpreted as the time since midnight and int64
+// as microseconds since midnight.
+func (dst *Time) Set(src interface{}) error {
+	if src == nil {
+		*dst = Time{Status: Null}
+		return nil
+	}
+
+	if value, ok := src.(interface{ Get() interface{} }); ok {
+		value2 := value.Get()
+		if value2 != value {
+			return dst.Set(value2)
+		}
+	}
+
+	switch value := src.(type) {
+	case time.Time:
+		*dst = Time{Microseconds: microsecondsSinceMidnight(value), Status: Present}
+	case time.Duration:
+		if value < 0 || value > microsecondsPerDay*time.Microsecond {
+			return errors.Errorf("%v is out of range for Time", value)
+		}
+		*dst = Time{Microseconds: int64(value / time.Microsecond), Status: Present}
+	case int64:
+		if value < 0 || value > microsecondsPerDay {
+			return errors.Errorf("%d is out of range for Time", value)
+		}
+		*dst = Time{Microseconds: value, Status: Present}
+	case string:
+		return dst.DecodeText(nil, []byte(value))
+	case *time.Time:
+		if value == nil {
+			*dst = Time{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	case *time.Duration:
+		if value == nil {
+			*dst = Time{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	case *string:
+		if value == nil {
+			*dst = Time{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	default:
+		if originalSrc, ok := underlyingTimeType(src); ok {
+			return dst.Set(originalSrc)
+		}
+		return errors.Errorf("cannot convert %v to Time", value)
+	}
+
+	return nil
+}
+
+func (dst Time) Get() interface{} {
+	switch dst.Status {
+	case Present:
+		return dst.Microseconds
+	case Null:
+		return nil
+	default:
+		return dst.Status
+	}
+}
+
+// AssignTo assigns src to dst. An int64 destination is set to microseconds
+// since midnight, and a time.Time destination to that time of day on
+// 2000-01-01 UTC.
+func (src *Time) AssignTo(dst interface{}) error {
+	switch src.Status {
+	case Present:
+		switch v := dst.(type) {
+		case *int64:
+			*v = src.Microseconds
+			return nil
+		case *time.Duration:
+			*v = time.Duration(src.Microseconds) * time.Microsecond
+			return nil
+		case *time.Time:
+			*v = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(src.Microseconds) * time.Microsecond)
+			return nil
+		case *string:
+			*v = formatTimeOfDay(src.Microseconds)
+			return nil
+		default:
+			if nextDst, retry := GetAssignToDstType(dst); retry {
+				return src.AssignTo(nextDst)
+			}
+			return errors.Errorf("unable to assign to %T", dst)
+		}
+	case Null:
+		return NullAssignTo(dst)
+	}
+
+	return errors.Errorf("cannot decode %#v into %T", src, dst)
+}
+
+func (dst *Time) DecodeText(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Time{Status: Null}
+		return nil
+	}
+
+	usec, err := parseTimeOfDay(string(src))
+	if err != nil {
+		return err
+	}
+
+	*dst = Time{Microseconds: usec, Status: Present}
+	return nil
+}
+
+func (dst *Time) DecodeBinary(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Time{Status: Null}
+		return nil
+	}
+
+	if len(src) != 8 {
+		return errors.Errorf("invalid length for time: %v", len(src))
+	}
+
+	usec := int64(binary.BigEndian.Uint64(src))
+	if usec < 0 || usec > microsecondsPerDay {
+		return errors.Errorf("%d is out of range for time", usec)
+	}
+	*dst = Time{Microseconds: usec, Status: Present}
+
+	return nil
+}
+
+func (src Time) EncodeText(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	return append(buf, formatTimeOfDay(src.Microseconds)...), nil
+}
+
+func (src Time) EncodeBinary(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	return pgio.AppendInt64(buf, src.Microseconds), nil
+}
+
+// Scan implements the database/sql Scanner interface.
+func (dst *Time) Scan(src interface{}) error {
+	if src == nil {
+		*dst = Time{Status: Null}
+		return nil
+	}
+
+	switch src := src.(type) {
+	case string:
+		return dst.DecodeText(nil, []byte(src))
+	case []byte:
+		srcCopy := make([]byte, len(src))
+		copy(srcCopy, src)
+		return dst.DecodeText(nil, srcCopy)
+	case time.Time:
+		return dst.Set(src)
+	}
+
+	return errors.Errorf("cannot scan %T", src)
+}
+
+// Value implements the database/sql/driver Valuer interface.
+func (src Time) Value() (driver.Value, error) {
+	return EncodeValueText(src)
+}
+
+// MarshalJSON encodes the time as HH:MM:SS with trailing zeros of the
+// fractional seconds removed, e.g. "12:34:56.789".
+func (src Time) MarshalJSON() ([]byte, error) {
+	switch src.Status {
+	case Present:
+		return json.Marshal(formatTimeOfDay(src.Microseconds))
+	case Null:
+		return []byte("null"), nil
+	}
+
+	return nil, errBadStatus
+}
+
+func (dst *Time) UnmarshalJSON(b []byte) error {
+	var s *string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		return err
+	}
+
+	if s == nil {
+		*dst = Time{Status: Null}
+		return nil
+	}
+
+	return dst.DecodeText(nil, []byte(*s))
+}
+
+// microsecondsSinceMidnight returns the wall clock of t as microseconds since
+// midnight.
+func microsecondsSinceMidnight(t time.Time) int64 {
+	return int64(t.Hour())*3600000000 +
+		int64(t.Minute())*60000000 +
+		int64(t.Second())*1000000 +
+		int64(t.Nanosecond())/1000
+}
+
+// parseTimeOfDay parses HH:MM[:SS[.ffffff]] into microseconds since midnight.
+func parseTimeOfDay(s string) (int64, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, errors.Errorf("invalid time format: %q", s)
+	}
+
+	hours, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || len(parts[0]) != 2 {
+		return 0, errors.Errorf("invalid time format: %q", s)
+	}
+	minutes, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || len(parts[1]) != 2 || minutes < 0 || minutes > 59 {
+		return 0, errors.Errorf("invalid time format: %q", s)
+	}
+
+	var seconds, fraction int64
+	if len(parts) == 3 {
+		secPart := parts[2]
+		if i := strings.IndexByte(secPart, '.'); i >= 0 {
+			frac := secPart[i+1:]
+			secPart = secPart[:i]
+			if len(frac) == 0 || len(frac) > 6 {
+				return 0, errors.Errorf("invalid time format: %q", s)
+			}
+			fraction, err = strconv.ParseInt(frac+strings.Repeat("0", 6-len(frac)), 10, 64)
+			if err != nil || fraction < 0 {
+				return 0, errors.Errorf("invalid time format: %q", s)
+			}
+		}
+		seconds, err = strconv.ParseInt(secPart, 10, 64)
+		if err != nil || len(secPart) != 2 || seconds < 0 || seconds > 59 {
+			return 0, errors.Errorf("invalid time format: %q", s)
+		}
+	}
+
+	usec := hours*3600000000 + minutes*60000000 + seconds*1000000 + fraction
+	if hours < 0 || usec > microsecondsPerDay {
+		return 0, errors.Errorf("time out of range: %q", s)
+	}
+
+	return usec, nil
+}
+
+// formatTimeOfDay formats microseconds since midnight as HH:MM:SS with as many
+// fractional digits as needed.
+func formatTimeOfDay(usec int64) string {
+	hours := usec / 3600000000
+	usec -= hours * 3600000000
+	minutes := usec / 60000000
+	usec -= minutes * 60000000
+	seconds := usec / 1000000
+	usec -= seconds * 1000000
+
+	buf := make([]byte, 0, 15)
+	buf = appendTwoDigits(buf, hours)
+	buf = append(buf, ':')
+	buf = appendTwoDigits(buf, minutes)
+	buf = append(buf, ':')
+	buf = appendTwoDigits(buf, seconds)
+	if usec > 0 {
+		frac := strconv.FormatInt(usec+1000000, 10)[1:]
+		buf = append(buf, '.')
+		buf = append(buf, strings.TrimRight(frac, "0")...)
+	}
+
+	return string(buf)
+}
+
+func appendTwoDigits(buf []byte, n int64) []byte {
+	if n < 10 {
+		buf = append(buf, '0')
+	}
+	return strconv.AppendInt(buf, n, 10)
+}
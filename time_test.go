@@ -0,0 +1,188 @@
+package tstype_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgio"
+	"github.com/tossp/tstype"
+)
+
+func TestTimeCodecs(t *testing.T) {
+	tests := []struct {
+		src          string
+		microseconds int64
+	}{
+		{"00:00:00", 0},
+		{"12:34:56.789", 45296789000},
+		{"23:59:59.999999", 86399999999},
+		{"24:00:00", 86400000000},
+	}
+
+	for i, tt := range tests {
+		var tm tstype.Time
+		if err := tm.DecodeText(nil, []byte(tt.src)); err != nil || tm.Microseconds != tt.microseconds {
+			t.Errorf("%d: DecodeText: expected %d, got %d, %v", i, tt.microseconds, tm.Microseconds, err)
+			continue
+		}
+		if buf, err := tm.EncodeText(nil, nil); err != nil || string(buf) != tt.src {
+			t.Errorf("%d: EncodeText: expected %q, got %q, %v", i, tt.src, buf, err)
+		}
+
+		buf, err := tm.EncodeBinary(nil, nil)
+		if err != nil || !bytes.Equal(buf, pgio.AppendInt64(nil, tt.microseconds)) {
+			t.Errorf("%d: EncodeBinary: got %x, %v", i, buf, err)
+		}
+		var fromBinary tstype.Time
+		if err := fromBinary.DecodeBinary(nil, buf); err != nil || fromBinary != tm {
+			t.Errorf("%d: DecodeBinary: expected %v, got %v, %v", i, tm, fromBinary, err)
+		}
+	}
+
+	for i, src := range []string{"", "24:00:01", "12:60:00", "noon"} {
+		var tm tstype.Time
+		if err := tm.DecodeText(nil, []byte(src)); err == nil {
+			t.Errorf("%d: %q: expected error, got %v", i, src, tm)
+		}
+	}
+
+	bad := tstype.Time{Microseconds: 1, Status: tstype.Status(2)}
+	if _, err := bad.EncodeText(nil, nil); err == nil {
+		t.Error("EncodeText invalid status: expected error")
+	}
+	if _, err := bad.EncodeBinary(nil, nil); err == nil {
+		t.Error("EncodeBinary invalid status: expected error")
+	}
+}
+
+func TestTimeSetAndAssignTo(t *testing.T) {
+	var tm tstype.Time
+	loc := time.FixedZone("", 8*60*60)
+	if err := tm.Set(time.Date(2020, 1, 2, 3, 4, 5, 6000, loc)); err != nil || tm.Microseconds != 11045000006 {
+		t.Errorf("Set time.Time: got %v, %v", tm, err)
+	}
+	if err := tm.Set(25 * time.Hour); err == nil {
+		t.Error("Set out of range time.Duration: expected error")
+	}
+	if err := tm.Set(tm.Get()); err != nil {
+		t.Errorf("Set(Get()): %v", err)
+	}
+
+	var d time.Duration
+	if err := tm.AssignTo(&d); err != nil || d != 3*time.Hour+4*time.Minute+5*time.Second+6*time.Microsecond {
+		t.Errorf("AssignTo time.Duration: got %v, %v", d, err)
+	}
+	var s string
+	if err := tm.AssignTo(&s); err != nil || s != "03:04:05.000006" {
+		t.Errorf("AssignTo string: got %q, %v", s, err)
+	}
+	var usec int64
+	if err := tm.AssignTo(&usec); err != nil || usec != 11045000006 {
+		t.Errorf("AssignTo int64: got %d, %v", usec, err)
+	}
+	var back tstype.Time
+	if err := back.Set(usec); err != nil || back != tm {
+		t.Errorf("Set(AssignTo int64): got %v, %v", back, err)
+	}
+}
+
+func TestTimeDecodeBinaryOutOfRange(t *testing.T) {
+	for i, usec := range []int64{-1, 86400000001} {
+		var tm tstype.Time
+		if err := tm.DecodeBinary(nil, pgio.AppendInt64(nil, usec)); err == nil {
+			t.Errorf("%d: Time %d: expected error, got %v", i, usec, tm)
+		}
+		var tz tstype.Timetz
+		if err := tz.DecodeBinary(nil, pgio.AppendInt32(pgio.AppendInt64(nil, usec), 0)); err == nil {
+			t.Errorf("%d: Timetz %d: expected error, got %v", i, usec, tz)
+		}
+	}
+}
+
+func TestTimetzOffsetOnTheWire(t *testing.T) {
+	tests := []struct {
+		src    string
+		offset int32
+	}{
+		{"12:34:56+05:30", 5*60*60 + 30*60},
+		{"12:34:56-03:30", -(3*60*60 + 30*60)},
+		{"12:34:56+00", 0},
+		{"12:34:56-08", -8 * 60 * 60},
+	}
+
+	for i, tt := range tests {
+		var tz tstype.Timetz
+		if err := tz.DecodeText(nil, []byte(tt.src)); err != nil {
+			t.Errorf("%d: %v", i, err)
+			continue
+		}
+		if tz.Offset != tt.offset || tz.Microseconds != 45296000000 {
+			t.Errorf("%d: %q: expected offset %d, got %v", i, tt.src, tt.offset, tz)
+		}
+
+		// The binary format stores the offset in seconds west of UTC.
+		buf, err := tz.EncodeBinary(nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := pgio.AppendInt32(pgio.AppendInt64(nil, 45296000000), -tt.offset)
+		if !bytes.Equal(buf, expected) {
+			t.Errorf("%d: %q: EncodeBinary: expected %x, got %x", i, tt.src, expected, buf)
+		}
+
+		var fromBinary tstype.Timetz
+		if err := fromBinary.DecodeBinary(nil, buf); err != nil {
+			t.Fatal(err)
+		}
+		if text, err := fromBinary.EncodeText(nil, nil); err != nil || string(text) != tt.src {
+			t.Errorf("%d: binary to text: expected %q, got %q, %v", i, tt.src, text, err)
+		}
+	}
+}
+
+func TestTimetzSetAndAssignTo(t *testing.T) {
+	loc := time.FixedZone("", -(3*60*60 + 30*60))
+	var tz tstype.Timetz
+	if err := tz.Set(time.Date(2020, 1, 2, 12, 34, 56, 0, loc)); err != nil {
+		t.Fatal(err)
+	}
+	if tz.Microseconds != 45296000000 || tz.Offset != -(3*60*60+30*60) {
+		t.Errorf("Set time.Time: got %v", tz)
+	}
+
+	var copied tstype.Timetz
+	if err := copied.Set(tz.Get()); err != nil || copied != tz {
+		t.Errorf("Set(Get()): got %v, %v", copied, err)
+	}
+
+	var tm time.Time
+	if err := tz.AssignTo(&tm); err != nil {
+		t.Fatal(err)
+	}
+	if _, offset := tm.Zone(); offset != -(3*60*60+30*60) || tm.Hour() != 12 || tm.Minute() != 34 {
+		t.Errorf("AssignTo time.Time: got %v", tm)
+	}
+	if expected := time.Date(2000, 1, 1, 16, 4, 56, 0, time.UTC); !tm.Equal(expected) {
+		t.Errorf("AssignTo time.Time: expected %v, got %v", expected, tm.UTC())
+	}
+
+	js, err := json.Marshal(tz)
+	if err != nil || string(js) != `"12:34:56-03:30"` {
+		t.Errorf("MarshalJSON: got %s, %v", js, err)
+	}
+	var back tstype.Timetz
+	if err := json.Unmarshal(js, &back); err != nil || back != tz {
+		t.Errorf("UnmarshalJSON: got %v, %v", back, err)
+	}
+
+	bad := tz
+	bad.Status = tstype.Status(2)
+	if _, err := bad.EncodeText(nil, nil); err == nil {
+		t.Error("EncodeText invalid status: expected error")
+	}
+	if _, err := bad.EncodeBinary(nil, nil); err == nil {
+		t.Error("EncodeBinary invalid status: expected error")
+	}
+}
@@ -0,0 +1,341 @@
+package tstype
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgio"
+	"github.com/jackc/pgtype"
+
+	errors "golang.org/x/xerrors"
+)
+
+const pgTimestampClockFormat = "15:04:05.999999999"
+
+// Timestamp represents the PostgreSQL timestamp type. The wall clock of Time is
+// what is stored; its location is ignored when encoding, so a value is never
+// shifted to UTC. Decoded values are always in the UTC location.
+type Timestamp struct {
+	Time             time.Time
+	Status           Status
+	InfinityModifier pgtype.InfinityModifier
+}
+
+func (dst *Timestamp) Set(src interface{}) error {
+	if src == nil {
+		*dst = Timestamp{Status: Null}
+		return nil
+	}
+
+	if value, ok := src.(interface{ Get() interface{} }); ok {
+		value2 := value.Get()
+		if value2 != value {
+			return dst.Set(value2)
+		}
+	}
+
+	switch value := src.(type) {
+	case time.Time:
+		*dst = Timestamp{Time: wallClockUTC(value), Status: Present}
+	case *time.Time:
+		if value == nil {
+			*dst = Timestamp{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	case string:
+		return dst.DecodeText(nil, []byte(value))
+	case *string:
+		if value == nil {
+			*dst = Timestamp{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	case pgtype.InfinityModifier:
+		*dst = Timestamp{InfinityModifier: value, Status: Present}
+	default:
+		if originalSrc, ok := underlyingTimeType(src); ok {
+			return dst.Set(originalSrc)
+		}
+		return errors.Errorf("cannot convert %v to Timestamp", value)
+	}
+
+	return nil
+}
+
+func (dst Timestamp) Get() interface{} {
+	switch dst.Status {
+	case Present:
+		if dst.InfinityModifier != pgtype.None {
+			return dst.InfinityModifier
+		}
+		return dst.Time
+	case Null:
+		return nil
+	default:
+		return dst.Status
+	}
+}
+
+func (src *Timestamp) AssignTo(dst interface{}) error {
+	switch src.Status {
+	case Present:
+		switch v := dst.(type) {
+		case *time.Time:
+			if src.InfinityModifier != pgtype.None {
+				return errors.Errorf("cannot assign %v to %T", src, dst)
+			}
+			*v = src.Time
+			return nil
+		default:
+			if nextDst, retry := GetAssignToDstType(dst); retry {
+				return src.AssignTo(nextDst)
+			}
+			return errors.Errorf("unable to assign to %T", dst)
+		}
+	case Null:
+		return NullAssignTo(dst)
+	}
+
+	return errors.Errorf("cannot decode %#v into %T", src, dst)
+}
+
+// DecodeText decodes from src into dst. The decoded time is in the UTC location
+// with the same wall clock as src.
+func (dst *Timestamp) DecodeText(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Timestamp{Status: Null}
+		return nil
+	}
+
+	sbuf := string(src)
+	switch sbuf {
+	case "infinity":
+		*dst = Timestamp{Status: Present, InfinityModifier: pgtype.Infinity}
+	case "-infinity":
+		*dst = Timestamp{Status: Present, InfinityModifier: pgtype.NegativeInfinity}
+	default:
+		tim, err := parseTimestamp(sbuf, ' ')
+		if err != nil {
+			return err
+		}
+
+		*dst = Timestamp{Time: tim, Status: Present}
+	}
+
+	return nil
+}
+
+// DecodeBinary decodes from src into dst. The decoded time is in the UTC
+// location.
+func (dst *Timestamp) DecodeBinary(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Timestamp{Status: Null}
+		return nil
+	}
+
+	if len(src) != 8 {
+		return errors.Errorf("invalid length for timestamp: %v", len(src))
+	}
+
+	microsecSinceY2K := int64(binary.BigEndian.Uint64(src))
+
+	switch microsecSinceY2K {
+	case infinityMicrosecondOffset:
+		*dst = Timestamp{Status: Present, InfinityModifier: pgtype.Infinity}
+	case negativeInfinityMicrosecondOffset:
+		*dst = Timestamp{Status: Present, InfinityModifier: pgtype.NegativeInfinity}
+	default:
+		microsecSinceUnixEpoch := microsecFromUnixEpochToY2K + microsecSinceY2K
+		tim := time.Unix(microsecSinceUnixEpoch/1000000, (microsecSinceUnixEpoch%1000000)*1000).UTC()
+		*dst = Timestamp{Time: tim, Status: Present}
+	}
+
+	return nil
+}
+
+// EncodeText writes the wall clock of src.Time. The location of src.Time is
+// not used.
+func (src Timestamp) EncodeText(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	var s string
+
+	switch src.InfinityModifier {
+	case pgtype.None:
+		s = formatTimestamp(src.Time, ' ')
+	case pgtype.Infinity:
+		s = "infinity"
+	case pgtype.NegativeInfinity:
+		s = "-infinity"
+	}
+
+	return append(buf, s...), nil
+}
+
+// EncodeBinary writes the wall clock of src.Time. The location of src.Time is
+// not used.
+func (src Timestamp) EncodeBinary(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	var microsecSinceY2K int64
+	switch src.InfinityModifier {
+	case pgtype.None:
+		t := wallClockUTC(src.Time)
+		microsecSinceUnixEpoch := t.Unix()*1000000 + int64(t.Nanosecond())/1000
+		microsecSinceY2K = microsecSinceUnixEpoch - microsecFromUnixEpochToY2K
+	case pgtype.Infinity:
+		microsecSinceY2K = infinityMicrosecondOffset
+	case pgtype.NegativeInfinity:
+		microsecSinceY2K = negativeInfinityMicrosecondOffset
+	}
+
+	return pgio.AppendInt64(buf, microsecSinceY2K), nil
+}
+
+// Scan implements the database/sql Scanner interface.
+func (dst *Timestamp) Scan(src interface{}) error {
+	if src == nil {
+		*dst = Timestamp{Status: Null}
+		return nil
+	}
+
+	switch src := src.(type) {
+	case string:
+		return dst.DecodeText(nil, []byte(src))
+	case []byte:
+		srcCopy := make([]byte, len(src))
+		copy(srcCopy, src)
+		return dst.DecodeText(nil, srcCopy)
+	case time.Time:
+		*dst = Timestamp{Time: wallClockUTC(src), Status: Present}
+		return nil
+	}
+
+	return errors.Errorf("cannot scan %T", src)
+}
+
+// Value implements the database/sql/driver Valuer interface.
+func (src Timestamp) Value() (driver.Value, error) {
+	switch src.Status {
+	case Present:
+		if src.InfinityModifier != pgtype.None {
+			return src.InfinityModifier.String(), nil
+		}
+		return wallClockUTC(src.Time), nil
+	case Null:
+		return nil, nil
+	default:
+		return nil, errBadStatus
+	}
+}
+
+// MarshalJSON encodes the wall clock in ISO 8601 without an offset, e.g.
+// "2000-01-01T12:34:56.789", as PostgreSQL's to_json does for timestamp.
+func (src Timestamp) MarshalJSON() ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return []byte("null"), nil
+	}
+
+	if src.Status != Present {
+		return nil, errBadStatus
+	}
+
+	var s string
+
+	switch src.InfinityModifier {
+	case pgtype.None:
+		s = formatTimestamp(src.Time, 'T')
+	case pgtype.Infinity:
+		s = "infinity"
+	case pgtype.NegativeInfinity:
+		s = "-infinity"
+	}
+
+	return json.Marshal(s)
+}
+
+func (dst *Timestamp) UnmarshalJSON(b []byte) error {
+	var s *string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		return err
+	}
+
+	if s == nil {
+		*dst = Timestamp{Status: Null}
+		return nil
+	}
+
+	switch *s {
+	case "infinity":
+		*dst = Timestamp{Status: Present, InfinityModifier: pgtype.Infinity}
+	case "-infinity":
+		*dst = Timestamp{Status: Present, InfinityModifier: pgtype.NegativeInfinity}
+	default:
+		tim, err := parseTimestamp(*s, 'T')
+		if err != nil {
+			return err
+		}
+
+		*dst = Timestamp{Time: tim, Status: Present}
+	}
+
+	return nil
+}
+
+// wallClockUTC returns a time in the UTC location with the same wall clock as t.
+func wallClockUTC(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+// parseTimestamp parses a timestamp without time zone whose date and clock
+// are separated by sep, e.g. "2000-01-01 12:34:56.789" or
+// "0044-03-15 12:00:00 BC".
+func parseTimestamp(s string, sep byte) (time.Time, error) {
+	era := ""
+	if strings.HasSuffix(s, " BC") {
+		era = " BC"
+		s = s[:len(s)-3]
+	}
+
+	i := strings.IndexByte(s, sep)
+	if i < 0 {
+		return time.Time{}, errors.Errorf("invalid timestamp format: %q", s)
+	}
+
+	date, err := parseDate(s[:i] + era)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	clock, err := time.Parse(pgTimestampClockFormat, s[i+1:])
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Date(date.Year(), date.Month(), date.Day(), clock.Hour(), clock.Minute(), clock.Second(), clock.Nanosecond(), time.UTC), nil
+}
+
+// formatTimestamp is the inverse of parseTimestamp. Precision is truncated to
+// microseconds as PostgreSQL stores it.
+func formatTimestamp(t time.Time, sep byte) string {
+	t = wallClockUTC(t).Truncate(time.Microsecond)
+	return formatDateYear(t.Year()) + t.Format("-01-02") + string(sep) + t.Format(pgTimestampClockFormat) + formatDateEra(t.Year())
+}
@@ -0,0 +1,80 @@
+package tstype_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tossp/tstype"
+)
+
+func TestTimestampKeepsWallClock(t *testing.T) {
+	loc := time.FixedZone("UTC+8", 8*60*60)
+	src := tstype.Timestamp{Time: time.Date(2020, 5, 6, 7, 8, 9, 123456000, loc), Status: tstype.Present}
+	expected := time.Date(2020, 5, 6, 7, 8, 9, 123456000, time.UTC)
+
+	buf, err := src.EncodeText(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(buf) != "2020-05-06 07:08:09.123456" {
+		t.Errorf("unexpected text encoding %q", buf)
+	}
+
+	var dst tstype.Timestamp
+	if err := dst.DecodeText(nil, buf); err != nil {
+		t.Fatal(err)
+	}
+	if !dst.Time.Equal(expected) || dst.Time.Location() != time.UTC {
+		t.Errorf("text: expected %v, got %v", expected, dst.Time)
+	}
+
+	buf, err = src.EncodeBinary(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dst.DecodeBinary(nil, buf); err != nil {
+		t.Fatal(err)
+	}
+	if !dst.Time.Equal(expected) || dst.Time.Location() != time.UTC {
+		t.Errorf("binary: expected %v, got %v", expected, dst.Time)
+	}
+
+	buf, err = src.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(buf) != `"2020-05-06T07:08:09.123456"` {
+		t.Errorf("unexpected JSON %s", buf)
+	}
+}
+
+func TestTimestampDecodeTextBC(t *testing.T) {
+	var ts tstype.Timestamp
+	if err := ts.DecodeText(nil, []byte("0044-03-15 12:00:00 BC")); err != nil {
+		t.Fatal(err)
+	}
+	if expected := time.Date(-43, 3, 15, 12, 0, 0, 0, time.UTC); !ts.Time.Equal(expected) {
+		t.Errorf("expected %v, got %v", expected, ts.Time)
+	}
+
+	buf, err := ts.EncodeText(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(buf) != "0044-03-15 12:00:00 BC" {
+		t.Errorf("unexpected text encoding %q", buf)
+	}
+}
+
+func TestTimestampEncodeInvalidStatus(t *testing.T) {
+	ts := tstype.Timestamp{Time: time.Date(2020, 5, 6, 7, 8, 9, 0, time.UTC), Status: tstype.Status(2)}
+	if _, err := ts.EncodeText(nil, nil); err == nil {
+		t.Error("EncodeText: expected error")
+	}
+	if _, err := ts.EncodeBinary(nil, nil); err == nil {
+		t.Error("EncodeBinary: expected error")
+	}
+	if _, err := ts.Value(); err == nil {
+		t.Error("Value: expected error")
+	}
+}
@@ -0,0 +1,87 @@
+package tstype_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgtype"
+	"github.com/tossp/tstype"
+)
+
+func TestTimestamptzArraySetAndAssignTo(t *testing.T) {
+	t1 := time.Date(2020, 3, 4, 5, 6, 7, 8000, time.UTC)
+	t2 := time.Date(1999, 12, 31, 23, 59, 59, 0, time.UTC)
+
+	var a tstype.TimestamptzArray
+	if err := a.Set([]time.Time{t1, t2}); err != nil {
+		t.Fatal(err)
+	}
+	var times []time.Time
+	if err := a.AssignTo(&times); err != nil || !reflect.DeepEqual(times, []time.Time{t1, t2}) {
+		t.Errorf("AssignTo []time.Time: got %v, %v", times, err)
+	}
+
+	if err := a.Set([]*time.Time{&t1, nil}); err != nil || a.Elements[1].Status != tstype.Null {
+		t.Errorf("Set []*time.Time: got %+v, %v", a, err)
+	}
+	var ptrs []*time.Time
+	if err := a.AssignTo(&ptrs); err != nil || len(ptrs) != 2 || !ptrs[0].Equal(t1) || ptrs[1] != nil {
+		t.Errorf("AssignTo []*time.Time: got %v, %v", ptrs, err)
+	}
+
+	if err := a.Set([][]time.Time{{t1}, {t2}}); err != nil || len(a.Dimensions) != 2 {
+		t.Errorf("Set [][]time.Time: got %+v, %v", a, err)
+	}
+
+	a = tstype.TimestamptzArray{
+		Elements:   []tstype.Timestamptz{{InfinityModifier: pgtype.Infinity, Status: tstype.Present}},
+		Dimensions: []pgtype.ArrayDimension{{Length: 1, LowerBound: 1}},
+		Status:     tstype.Present,
+	}
+	if err := a.AssignTo(&times); err == nil {
+		t.Error("AssignTo infinity: expected error")
+	}
+}
+
+func TestTimestamptzArrayCodecs(t *testing.T) {
+	a := tstype.TimestamptzArray{
+		Elements: []tstype.Timestamptz{
+			{Time: time.Date(2020, 3, 4, 5, 6, 7, 0, time.UTC), Status: tstype.Present},
+			{InfinityModifier: pgtype.NegativeInfinity, Status: tstype.Present},
+			{InfinityModifier: pgtype.Infinity, Status: tstype.Present},
+			{Status: tstype.Null},
+		},
+		Dimensions: []pgtype.ArrayDimension{{Length: 2, LowerBound: 1}, {Length: 2, LowerBound: 1}},
+		Status:     tstype.Present,
+	}
+
+	buf, err := a.EncodeText(nil, nil)
+	if err != nil || string(buf) != `{{2020-03-04 05:06:07Z,-infinity},{infinity,NULL}}` {
+		t.Errorf("EncodeText: got %s, %v", buf, err)
+	}
+	var fromText tstype.TimestamptzArray
+	if err := fromText.DecodeText(nil, buf); err != nil || !fromText.Elements[0].Time.Equal(a.Elements[0].Time) || fromText.Elements[2].InfinityModifier != pgtype.Infinity || fromText.Elements[3].Status != tstype.Null {
+		t.Errorf("DecodeText: got %+v, %v", fromText, err)
+	}
+
+	ci := pgtype.NewConnInfo()
+	bin, err := a.EncodeBinary(ci, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fromBinary tstype.TimestamptzArray
+	if err := fromBinary.DecodeBinary(ci, bin); err != nil || len(fromBinary.Dimensions) != 2 || !fromBinary.Elements[0].Time.Equal(a.Elements[0].Time) || fromBinary.Elements[1].InfinityModifier != pgtype.NegativeInfinity || fromBinary.Elements[3].Status != tstype.Null {
+		t.Errorf("DecodeBinary: got %+v, %v", fromBinary, err)
+	}
+
+	js, err := json.Marshal(a)
+	if err != nil || string(js) != `[["2020-03-04T05:06:07Z","-infinity"],["infinity",null]]` {
+		t.Errorf("MarshalJSON: got %s, %v", js, err)
+	}
+	var back tstype.TimestamptzArray
+	if err := json.Unmarshal(js, &back); err != nil || !back.Elements[0].Time.Equal(a.Elements[0].Time) || back.Elements[1].InfinityModifier != pgtype.NegativeInfinity || back.Elements[3].Status != tstype.Null {
+		t.Errorf("UnmarshalJSON: got %+v, %v", back, err)
+	}
+}
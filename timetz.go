@@ -0,0 +1,303 @@
+package tstype
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgio"
+	"github.com/jackc/pgtype"
+
+	errors "golang.org/x/xerrors"
+)
+
+// Timetz represents the PostgreSQL time with time zone type. Microseconds is the
+// local time of day in microseconds since midnight and Offset is the UTC offset
+// in seconds east of UTC, the same convention as time.Zone. Note that the
+// PostgreSQL binary format stores the offset west of UTC; the sign is flipped
+// on the wire.
+type Timetz struct {
+	Microseconds int64
+	Offset       int32
+	Status       Status
+}
+
+// Set converts src into a Timetz. time.Time uses its wall clock and the offset
+// of its location at that instant. A Timetz, as returned by Get, is copied.
+func (dst *Timetz) Set(src interface{}) error {
+	if src == nil {
+		*dst = Timetz{Status: Null}
+		return nil
+	}
+
+	if value, ok := src.(interface{ Get() interface{} }); ok {
+		value2 := value.Get()
+		if value2 != value {
+			return dst.Set(value2)
+		}
+	}
+
+	switch value := src.(type) {
+	case time.Time:
+		_, offset := value.Zone()
+		*dst = Timetz{Microseconds: microsecondsSinceMidnight(value), Offset: int32(offset), Status: Present}
+	case string:
+		return dst.DecodeText(nil, []byte(value))
+	case *time.Time:
+		if value == nil {
+			*dst = Timetz{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	case *string:
+		if value == nil {
+			*dst = Timetz{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	case Timetz:
+		*dst = value
+	case *Timetz:
+		if value == nil {
+			*dst = Timetz{Status: Null}
+		} else {
+			*dst = *value
+		}
+	default:
+		if originalSrc, ok := underlyingTimeType(src); ok {
+			return dst.Set(originalSrc)
+		}
+		return errors.Errorf("cannot convert %v to Timetz", value)
+	}
+
+	return nil
+}
+
+func (dst Timetz) Get() interface{} {
+	switch dst.Status {
+	case Present:
+		return dst
+	case Null:
+		return nil
+	default:
+		return dst.Status
+	}
+}
+
+// AssignTo assigns src to dst. A time.Time destination is set to that time of
+// day on 2000-01-01 in a fixed zone with the value's offset. A time.Duration
+// destination receives the local time since midnight.
+func (src *Timetz) AssignTo(dst interface{}) error {
+	switch src.Status {
+	case Present:
+		switch v := dst.(type) {
+		case *time.Duration:
+			*v = time.Duration(src.Microseconds) * time.Microsecond
+			return nil
+		case *time.Time:
+			loc := time.FixedZone("", int(src.Offset))
+			*v = time.Date(2000, 1, 1, 0, 0, 0, 0, loc).Add(time.Duration(src.Microseconds) * time.Microsecond)
+			return nil
+		case *string:
+			*v = formatTimeOfDay(src.Microseconds) + formatTimeZoneOffset(src.Offset)
+			return nil
+		default:
+			if nextDst, retry := GetAssignToDstType(dst); retry {
+				return src.AssignTo(nextDst)
+			}
+			return errors.Errorf("unable to assign to %T", dst)
+		}
+	case Null:
+		return NullAssignTo(dst)
+	}
+
+	return errors.Errorf("cannot decode %#v into %T", src, dst)
+}
+
+func (dst *Timetz) DecodeText(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Timetz{Status: Null}
+		return nil
+	}
+
+	sbuf := string(src)
+	i := strings.LastIndexAny(sbuf, "+-")
+	if i < 0 {
+		return errors.Errorf("invalid timetz format, missing offset: %q", sbuf)
+	}
+
+	usec, err := parseTimeOfDay(sbuf[:i])
+	if err != nil {
+		return err
+	}
+
+	offset, err := parseTimeZoneOffset(sbuf[i:])
+	if err != nil {
+		return err
+	}
+
+	*dst = Timetz{Microseconds: usec, Offset: offset, Status: Present}
+	return nil
+}
+
+func (dst *Timetz) DecodeBinary(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Timetz{Status: Null}
+		return nil
+	}
+
+	if len(src) != 12 {
+		return errors.Errorf("invalid length for timetz: %v", len(src))
+	}
+
+	usec := int64(binary.BigEndian.Uint64(src))
+	if usec < 0 || usec > microsecondsPerDay {
+		return errors.Errorf("%d is out of range for timetz", usec)
+	}
+	zone := int32(binary.BigEndian.Uint32(src[8:]))
+	*dst = Timetz{Microseconds: usec, Offset: -zone, Status: Present}
+
+	return nil
+}
+
+func (src Timetz) EncodeText(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	buf = append(buf, formatTimeOfDay(src.Microseconds)...)
+	return append(buf, formatTimeZoneOffset(src.Offset)...), nil
+}
+
+func (src Timetz) EncodeBinary(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	buf = pgio.AppendInt64(buf, src.Microseconds)
+	return pgio.AppendInt32(buf, -src.Offset), nil
+}
+
+// Scan implements the database/sql Scanner interface.
+func (dst *Timetz) Scan(src interface{}) error {
+	if src == nil {
+		*dst = Timetz{Status: Null}
+		return nil
+	}
+
+	switch src := src.(type) {
+	case string:
+		return dst.DecodeText(nil, []byte(src))
+	case []byte:
+		srcCopy := make([]byte, len(src))
+		copy(srcCopy, src)
+		return dst.DecodeText(nil, srcCopy)
+	case time.Time:
+		return dst.Set(src)
+	}
+
+	return errors.Errorf("cannot scan %T", src)
+}
+
+// Value implements the database/sql/driver Valuer interface.
+func (src Timetz) Value() (driver.Value, error) {
+	return EncodeValueText(src)
+}
+
+// MarshalJSON encodes the time as HH:MM:SS followed by the offset, e.g.
+// "12:34:56.789+05:30".
+func (src Timetz) MarshalJSON() ([]byte, error) {
+	switch src.Status {
+	case Present:
+		return json.Marshal(formatTimeOfDay(src.Microseconds) + formatTimeZoneOffset(src.Offset))
+	case Null:
+		return []byte("null"), nil
+	}
+
+	return nil, errBadStatus
+}
+
+func (dst *Timetz) UnmarshalJSON(b []byte) error {
+	var s *string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		return err
+	}
+
+	if s == nil {
+		*dst = Timetz{Status: Null}
+		return nil
+	}
+
+	return dst.DecodeText(nil, []byte(*s))
+}
+
+// parseTimeZoneOffset parses a UTC offset such as "+05", "-03:30" or
+// "+05:30:15" into seconds east of UTC.
+func parseTimeZoneOffset(s string) (int32, error) {
+	if len(s) < 2 || (s[0] != '+' && s[0] != '-') {
+		return 0, errors.Errorf("invalid time zone offset: %q", s)
+	}
+
+	parts := strings.Split(s[1:], ":")
+	if len(parts) > 3 {
+		return 0, errors.Errorf("invalid time zone offset: %q", s)
+	}
+
+	var offset int64
+	for i, p := range parts {
+		n, err := strconv.ParseUint(p, 10, 8)
+		if err != nil || len(p) != 2 || (i > 0 && n > 59) {
+			return 0, errors.Errorf("invalid time zone offset: %q", s)
+		}
+		offset = offset*60 + int64(n)
+	}
+	for i := len(parts); i < 3; i++ {
+		offset *= 60
+	}
+
+	if s[0] == '-' {
+		offset = -offset
+	}
+
+	return int32(offset), nil
+}
+
+// formatTimeZoneOffset formats seconds east of UTC the way PostgreSQL does,
+// omitting minutes and seconds when they are zero.
+func formatTimeZoneOffset(offset int32) string {
+	buf := make([]byte, 0, 9)
+	if offset < 0 {
+		buf = append(buf, '-')
+		offset = -offset
+	} else {
+		buf = append(buf, '+')
+	}
+
+	hours := int64(offset / 3600)
+	minutes := int64(offset % 3600 / 60)
+	seconds := int64(offset % 60)
+
+	buf = appendTwoDigits(buf, hours)
+	if minutes != 0 || seconds != 0 {
+		buf = append(buf, ':')
+		buf = appendTwoDigits(buf, minutes)
+	}
+	if seconds != 0 {
+		buf = append(buf, ':')
+		buf = appendTwoDigits(buf, seconds)
+	}
+
+	return string(buf)
+}
@@ -0,0 +1,203 @@
+package tstype
+
+import (
+	"database/sql/driver"
+	"time"
+
+	"github.com/jackc/pgtype"
+
+	errors "golang.org/x/xerrors"
+)
+
+// Tsmultirange represents the PostgreSQL tsmultirange type, available since
+// PostgreSQL 14. Ranges are kept in the order they were set or decoded; use
+// Canonical to sort and merge them as the server does.
+type Tsmultirange struct {
+	Ranges []Tsrange
+	Status Status
+}
+
+func (dst *Tsmultirange) Set(src interface{}) error {
+	if src == nil {
+		*dst = Tsmultirange{Status: Null}
+		return nil
+	}
+
+	switch value := src.(type) {
+	case Tsmultirange:
+		*dst = value
+	case *Tsmultirange:
+		if value == nil {
+			*dst = Tsmultirange{Status: Null}
+		} else {
+			*dst = *value
+		}
+	case []Tsrange:
+		if value == nil {
+			*dst = Tsmultirange{Status: Null}
+		} else {
+			*dst = Tsmultirange{Ranges: value, Status: Present}
+		}
+	case string:
+		return dst.DecodeText(nil, []byte(value))
+	case *string:
+		if value == nil {
+			*dst = Tsmultirange{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	default:
+		return errors.Errorf("cannot convert %v to Tsmultirange", src)
+	}
+
+	return nil
+}
+
+func (dst Tsmultirange) Get() interface{} {
+	switch dst.Status {
+	case Present:
+		return dst
+	case Null:
+		return nil
+	default:
+		return dst.Status
+	}
+}
+
+func (src *Tsmultirange) AssignTo(dst interface{}) error {
+	switch src.Status {
+	case Present:
+		switch v := dst.(type) {
+		case *Tsmultirange:
+			*v = *src
+			return nil
+		case *[]Tsrange:
+			*v = make([]Tsrange, len(src.Ranges))
+			copy(*v, src.Ranges)
+			return nil
+		case *string:
+			buf, err := src.EncodeText(nil, nil)
+			if err != nil {
+				return err
+			}
+			*v = string(buf)
+			return nil
+		default:
+			if nextDst, retry := GetAssignToDstType(dst); retry {
+				return src.AssignTo(nextDst)
+			}
+			return errors.Errorf("unable to assign to %T", dst)
+		}
+	case Null:
+		return NullAssignTo(dst)
+	}
+
+	return errors.Errorf("cannot decode %#v into %T", src, dst)
+}
+
+func (dst *Tsmultirange) DecodeText(ci *pgtype.ConnInfo, src []byte) error {
+	m, err := decodeMultirangeText[Tsrange, Timestamp](ci, src)
+	if err != nil {
+		return err
+	}
+
+	*dst = Tsmultirange(m)
+	return nil
+}
+
+func (dst *Tsmultirange) DecodeBinary(ci *pgtype.ConnInfo, src []byte) error {
+	m, err := decodeMultirangeBinary[Tsrange, Timestamp](ci, src)
+	if err != nil {
+		return err
+	}
+
+	*dst = Tsmultirange(m)
+	return nil
+}
+
+func (src Tsmultirange) EncodeText(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	return encodeMultirangeText(ci, buf, multirangeValue[Tsrange](src))
+}
+
+func (src Tsmultirange) EncodeBinary(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	return encodeMultirangeBinary(ci, buf, multirangeValue[Tsrange](src))
+}
+
+// Scan implements the database/sql Scanner interface.
+func (dst *Tsmultirange) Scan(src interface{}) error {
+	if src == nil {
+		*dst = Tsmultirange{Status: Null}
+		return nil
+	}
+
+	switch src := src.(type) {
+	case string:
+		return dst.DecodeText(nil, []byte(src))
+	case []byte:
+		srcCopy := make([]byte, len(src))
+		copy(srcCopy, src)
+		return dst.DecodeText(nil, srcCopy)
+	}
+
+	return errors.Errorf("cannot scan %T", src)
+}
+
+// Value implements the database/sql/driver Valuer interface.
+func (src Tsmultirange) Value() (driver.Value, error) {
+	return EncodeValueText(src)
+}
+
+// MarshalJSON encodes src as a JSON array of its ranges, each in the form
+// written by Tsrange.MarshalJSON.
+func (src Tsmultirange) MarshalJSON() ([]byte, error) {
+	return marshalMultirangeJSON(multirangeValue[Tsrange](src))
+}
+
+func (dst *Tsmultirange) UnmarshalJSON(b []byte) error {
+	m, err := unmarshalMultirangeJSON[Tsrange, Timestamp](b)
+	if err != nil {
+		return err
+	}
+
+	*dst = Tsmultirange(m)
+	return nil
+}
+
+// Canonical returns src in the form the server stores it. Each range is
+// canonicalized, empty ranges are dropped, and the rest are sorted with
+// overlapping and adjacent ranges merged. It returns an error for a range the
+// server would reject.
+func (src Tsmultirange) Canonical() (Tsmultirange, error) {
+	m, err := canonicalMultirange(tsRangeSubtype, multirangeValue[Tsrange](src), true)
+	if err != nil {
+		return Tsmultirange{}, err
+	}
+	return Tsmultirange(m), nil
+}
+
+// IsEmpty reports whether src contains no values.
+func (src Tsmultirange) IsEmpty() bool {
+	if src.Status != Present {
+		return false
+	}
+	for _, r := range src.Ranges {
+		if !r.IsEmpty() {
+			return false
+		}
+	}
+	return true
+}
+
+// Contains reports whether v is in one of the ranges of src, as the server's @>
+// operator does.
+func (src Tsmultirange) Contains(v time.Time) bool {
+	if src.Status != Present {
+		return false
+	}
+	for _, r := range src.Ranges {
+		if r.Contains(v) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,650 @@
+package tstype
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgio"
+	"github.com/jackc/pgtype"
+
+	errors "golang.org/x/xerrors"
+)
+
+// TsqueryOperator is the kind of a TsqueryNode. Its values match the wire
+// format, with TsqueryOperand for a lexeme.
+type TsqueryOperator byte
+
+const (
+	TsqueryOperand TsqueryOperator = iota
+	TsqueryNot
+	TsqueryAnd
+	TsqueryOr
+	TsqueryPhrase
+)
+
+// priority returns the precedence of op, as the server's
+// tsquery_op_priorities does.
+func (op TsqueryOperator) priority() int {
+	switch op {
+	case TsqueryNot:
+		return 4
+	case TsqueryPhrase:
+		return 3
+	case TsqueryAnd:
+		return 2
+	case TsqueryOr:
+		return 1
+	}
+	return 0
+}
+
+// TsWeights is a set of tsvector weights a tsquery operand is restricted to.
+// An empty set matches every weight.
+type TsWeights byte
+
+// Has reports whether w is in ws.
+func (ws TsWeights) Has(w TsWeight) bool {
+	return ws&(1<<w) != 0
+}
+
+// With returns ws with w added.
+func (ws TsWeights) With(w TsWeight) TsWeights {
+	return ws | 1<<w
+}
+
+// TsqueryNode is a node of a tsquery. An operand has Lexeme, Weights and
+// Prefix set. TsqueryNot has its operand in Left. TsqueryAnd, TsqueryOr and
+// TsqueryPhrase have both Left and Right, and TsqueryPhrase the Distance
+// between them, which is 1 for <->.
+type TsqueryNode struct {
+	Operator TsqueryOperator
+	Lexeme   string
+	Weights  TsWeights
+	Prefix   bool
+	Distance uint16
+	Left     *TsqueryNode
+	Right    *TsqueryNode
+}
+
+// Lexemes returns the lexemes of the operands of n from left to right.
+func (n *TsqueryNode) Lexemes() []string {
+	var lexemes []string
+	var walk func(n *TsqueryNode)
+	walk = func(n *TsqueryNode) {
+		if n == nil {
+			return
+		}
+		if n.Operator == TsqueryOperand {
+			lexemes = append(lexemes, n.Lexeme)
+			return
+		}
+		walk(n.Left)
+		walk(n.Right)
+	}
+	walk(n)
+	return lexemes
+}
+
+func (n *TsqueryNode) validate() error {
+	switch n.Operator {
+	case TsqueryOperand:
+		if strings.IndexByte(n.Lexeme, 0) >= 0 {
+			return errors.Errorf("tsquery operand %q cannot contain a NUL byte", n.Lexeme)
+		}
+		if n.Weights > 0xf {
+			return errors.Errorf("invalid tsquery weights: %#x", byte(n.Weights))
+		}
+		return nil
+	case TsqueryNot:
+		if n.Left == nil {
+			return errors.New("tsquery NOT requires an operand")
+		}
+		return n.Left.validate()
+	case TsqueryAnd, TsqueryOr, TsqueryPhrase:
+		if n.Left == nil || n.Right == nil {
+			return errors.New("tsquery operator requires two operands")
+		}
+		if n.Operator == TsqueryPhrase && n.Distance > maxTsvectorPosition+1 {
+			return errors.Errorf("distance in phrase operator must be an integer value between zero and %d inclusive", maxTsvectorPosition+1)
+		}
+		if err := n.Left.validate(); err != nil {
+			return err
+		}
+		return n.Right.validate()
+	}
+	return errors.Errorf("unknown tsquery operator %d", n.Operator)
+}
+
+// Tsquery represents the PostgreSQL tsquery type as a tree of nodes. Root is
+// nil for the empty query.
+type Tsquery struct {
+	Root   *TsqueryNode
+	Status Status
+}
+
+func (dst *Tsquery) Set(src interface{}) error {
+	if src == nil {
+		*dst = Tsquery{Status: Null}
+		return nil
+	}
+
+	switch value := src.(type) {
+	case Tsquery:
+		*dst = value
+	case *Tsquery:
+		if value == nil {
+			*dst = Tsquery{Status: Null}
+		} else {
+			*dst = *value
+		}
+	case *TsqueryNode:
+		if value != nil {
+			if err := value.validate(); err != nil {
+				return err
+			}
+		}
+		*dst = Tsquery{Root: value, Status: Present}
+	case string:
+		return dst.DecodeText(nil, []byte(value))
+	case *string:
+		if value == nil {
+			*dst = Tsquery{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	default:
+		if originalSrc, ok := underlyingStringType(src); ok {
+			return dst.Set(originalSrc)
+		}
+		return errors.Errorf("cannot convert %v to Tsquery", value)
+	}
+
+	return nil
+}
+
+func (dst Tsquery) Get() interface{} {
+	switch dst.Status {
+	case Present:
+		return dst
+	case Null:
+		return nil
+	default:
+		return dst.Status
+	}
+}
+
+func (src *Tsquery) AssignTo(dst interface{}) error {
+	switch src.Status {
+	case Present:
+		switch v := dst.(type) {
+		case *Tsquery:
+			*v = *src
+			return nil
+		case *string:
+			buf, err := src.EncodeText(nil, nil)
+			if err != nil {
+				return err
+			}
+			*v = string(buf)
+			return nil
+		default:
+			if nextDst, retry := GetAssignToDstType(dst); retry {
+				return src.AssignTo(nextDst)
+			}
+			return errors.Errorf("unable to assign to %T", dst)
+		}
+	case Null:
+		return NullAssignTo(dst)
+	}
+
+	return errors.Errorf("cannot decode %#v into %T", src, dst)
+}
+
+// DecodeText parses the tsquery text format, such as 'fat':AB* & !('rat' <->
+// 'cat'). ! binds tighter than <-> and <N>, which bind tighter than &, which
+// binds tighter than |. Operators of equal precedence associate to the left.
+func (dst *Tsquery) DecodeText(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Tsquery{Status: Null}
+		return nil
+	}
+
+	p := tsqueryParser{s: string(src)}
+	if p.skipSpace(); p.rp == len(p.s) {
+		*dst = Tsquery{Status: Present}
+		return nil
+	}
+
+	root, err := p.parseExpr(1)
+	if err != nil {
+		return errors.Errorf("syntax error in tsquery: %q: %w", p.s, err)
+	}
+	if p.skipSpace(); p.rp != len(p.s) {
+		return errors.Errorf("syntax error in tsquery: %q", p.s)
+	}
+
+	*dst = Tsquery{Root: root, Status: Present}
+	return nil
+}
+
+type tsqueryParser struct {
+	s  string
+	rp int
+}
+
+func (p *tsqueryParser) skipSpace() {
+	p.rp = skipTsSpace(p.s, p.rp)
+}
+
+// parseExpr parses operators with a priority of at least minPriority.
+func (p *tsqueryParser) parseExpr(minPriority int) (*TsqueryNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		p.skipSpace()
+		start := p.rp
+		op, distance, ok, err := p.scanOperator()
+		if err != nil {
+			return nil, err
+		}
+		if !ok || op.priority() < minPriority {
+			p.rp = start
+			return left, nil
+		}
+
+		right, err := p.parseExpr(op.priority() + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = &TsqueryNode{Operator: op, Distance: distance, Left: left, Right: right}
+	}
+}
+
+// scanOperator reads a binary operator, returning false if there is none.
+func (p *tsqueryParser) scanOperator() (TsqueryOperator, uint16, bool, error) {
+	if p.rp >= len(p.s) {
+		return 0, 0, false, nil
+	}
+
+	switch p.s[p.rp] {
+	case '&':
+		p.rp++
+		return TsqueryAnd, 0, true, nil
+	case '|':
+		p.rp++
+		return TsqueryOr, 0, true, nil
+	case '<':
+		end := strings.IndexByte(p.s[p.rp:], '>')
+		if end < 0 {
+			return 0, 0, false, errors.New("unterminated phrase operator")
+		}
+		spec := p.s[p.rp+1 : p.rp+end]
+		p.rp += end + 1
+		if spec == "-" {
+			return TsqueryPhrase, 1, true, nil
+		}
+		n, err := strconv.ParseUint(spec, 10, 16)
+		if err != nil || n > maxTsvectorPosition+1 {
+			return 0, 0, false, errors.Errorf("distance in phrase operator must be an integer value between zero and %d inclusive", maxTsvectorPosition+1)
+		}
+		return TsqueryPhrase, uint16(n), true, nil
+	}
+
+	return 0, 0, false, nil
+}
+
+func (p *tsqueryParser) parseUnary() (*TsqueryNode, error) {
+	p.skipSpace()
+	if p.rp >= len(p.s) {
+		return nil, errors.New("unexpected end of input")
+	}
+
+	switch p.s[p.rp] {
+	case '!':
+		p.rp++
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &TsqueryNode{Operator: TsqueryNot, Left: operand}, nil
+	case '(':
+		p.rp++
+		n, err := p.parseExpr(1)
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if p.rp >= len(p.s) || p.s[p.rp] != ')' {
+			return nil, errors.New("missing closing parenthesis")
+		}
+		p.rp++
+		return n, nil
+	case '&', '|', '<', ')':
+		return nil, errors.Errorf("unexpected %q", p.s[p.rp])
+	}
+
+	lexeme, next, err := scanTsWord(p.s, p.rp, true)
+	if err != nil {
+		return nil, err
+	}
+	p.rp = next
+
+	n := &TsqueryNode{Operator: TsqueryOperand, Lexeme: lexeme}
+	if p.rp < len(p.s) && p.s[p.rp] == ':' {
+		for p.rp++; p.rp < len(p.s); p.rp++ {
+			if p.s[p.rp] == '*' {
+				n.Prefix = true
+			} else if w, ok := parseTsWeight(p.s[p.rp]); ok {
+				n.Weights = n.Weights.With(w)
+			} else {
+				break
+			}
+		}
+	}
+
+	return n, nil
+}
+
+// Item types of the tsquery binary format.
+const (
+	tsqueryValueItem    = 1
+	tsqueryOperatorItem = 2
+)
+
+// DecodeBinary reads the items of the binary format, which lists each
+// operator before its right and then its left operand.
+func (dst *Tsquery) DecodeBinary(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Tsquery{Status: Null}
+		return nil
+	}
+
+	if len(src) < 4 {
+		return errors.Errorf("invalid length for tsquery: %v", len(src))
+	}
+	count := int(binary.BigEndian.Uint32(src))
+	if count == 0 {
+		if len(src) != 4 {
+			return errors.Errorf("tsquery has %d trailing bytes", len(src)-4)
+		}
+		*dst = Tsquery{Status: Present}
+		return nil
+	}
+
+	r := tsqueryBinaryReader{src: src, rp: 4, remaining: count}
+	root, err := r.readNode()
+	if err != nil {
+		return err
+	}
+	if r.remaining != 0 {
+		return errors.Errorf("malformed tsquery: %d extra items", r.remaining)
+	}
+	if r.rp != len(src) {
+		return errors.Errorf("tsquery has %d trailing bytes", len(src)-r.rp)
+	}
+
+	*dst = Tsquery{Root: root, Status: Present}
+	return nil
+}
+
+type tsqueryBinaryReader struct {
+	src       []byte
+	rp        int
+	remaining int
+}
+
+func (r *tsqueryBinaryReader) readNode() (*TsqueryNode, error) {
+	if r.remaining == 0 {
+		return nil, errors.New("malformed tsquery: operand missing")
+	}
+	r.remaining--
+
+	if len(r.src[r.rp:]) < 2 {
+		return nil, errors.New("tsquery too short")
+	}
+	itemType, op := r.src[r.rp], TsqueryOperator(r.src[r.rp+1])
+	r.rp += 2
+
+	switch itemType {
+	case tsqueryValueItem:
+		if len(r.src[r.rp:]) < 1 {
+			return nil, errors.New("tsquery too short")
+		}
+		n := &TsqueryNode{Operator: TsqueryOperand, Weights: TsWeights(op), Prefix: r.src[r.rp] != 0}
+		r.rp++
+		end := bytes.IndexByte(r.src[r.rp:], 0)
+		if end < 0 {
+			return nil, errors.New("unterminated tsquery operand")
+		}
+		n.Lexeme = string(r.src[r.rp : r.rp+end])
+		r.rp += end + 1
+		return n, n.validate()
+	case tsqueryOperatorItem:
+		n := &TsqueryNode{Operator: op}
+		switch op {
+		case TsqueryNot:
+			var err error
+			if n.Left, err = r.readNode(); err != nil {
+				return nil, err
+			}
+			return n, nil
+		case TsqueryPhrase:
+			if len(r.src[r.rp:]) < 2 {
+				return nil, errors.New("tsquery too short")
+			}
+			n.Distance = binary.BigEndian.Uint16(r.src[r.rp:])
+			r.rp += 2
+			fallthrough
+		case TsqueryAnd, TsqueryOr:
+			var err error
+			if n.Right, err = r.readNode(); err != nil {
+				return nil, err
+			}
+			if n.Left, err = r.readNode(); err != nil {
+				return nil, err
+			}
+			return n, nil
+		}
+		return nil, errors.Errorf("unrecognized tsquery operator: %d", op)
+	}
+
+	return nil, errors.Errorf("unrecognized tsquery item type: %d", itemType)
+}
+
+// EncodeText writes src as the server's tsquery output does, with
+// parentheses only where precedence requires them.
+func (src Tsquery) EncodeText(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	if buf == nil {
+		buf = []byte{}
+	}
+	if src.Root == nil {
+		return buf, nil
+	}
+	if err := src.Root.validate(); err != nil {
+		return nil, err
+	}
+
+	return appendTsqueryNodeText(buf, src.Root, -1, false), nil
+}
+
+// appendTsqueryNodeText writes n as the server's infix does. rightPhrase is
+// true for the right operand of a phrase operator, which must be
+// parenthesized if it is itself a phrase as phrases depend on order.
+func appendTsqueryNodeText(buf []byte, n *TsqueryNode, parentPriority int, rightPhrase bool) []byte {
+	switch n.Operator {
+	case TsqueryOperand:
+		buf = appendTsLexemeText(buf, n.Lexeme)
+		if n.Prefix || n.Weights != 0 {
+			buf = append(buf, ':')
+			if n.Prefix {
+				buf = append(buf, '*')
+			}
+			for _, w := range []TsWeight{TsWeightA, TsWeightB, TsWeightC, TsWeightD} {
+				if n.Weights.Has(w) {
+					buf = append(buf, w.String()...)
+				}
+			}
+		}
+		return buf
+	case TsqueryNot:
+		priority := n.Operator.priority()
+		if priority < parentPriority {
+			buf = append(buf, "( "...)
+		}
+		buf = append(buf, '!')
+		buf = appendTsqueryNodeText(buf, n.Left, priority, false)
+		if priority < parentPriority {
+			buf = append(buf, " )"...)
+		}
+		return buf
+	}
+
+	priority := n.Operator.priority()
+	parens := priority < parentPriority || (n.Operator == TsqueryPhrase && rightPhrase)
+	if parens {
+		buf = append(buf, "( "...)
+	}
+
+	buf = appendTsqueryNodeText(buf, n.Left, priority, false)
+	switch n.Operator {
+	case TsqueryOr:
+		buf = append(buf, " | "...)
+	case TsqueryAnd:
+		buf = append(buf, " & "...)
+	case TsqueryPhrase:
+		if n.Distance == 1 {
+			buf = append(buf, " <-> "...)
+		} else {
+			buf = append(buf, " <"...)
+			buf = strconv.AppendUint(buf, uint64(n.Distance), 10)
+			buf = append(buf, "> "...)
+		}
+	}
+	buf = appendTsqueryNodeText(buf, n.Right, priority, n.Operator == TsqueryPhrase)
+
+	if parens {
+		buf = append(buf, " )"...)
+	}
+	return buf
+}
+
+func (src Tsquery) EncodeBinary(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	if src.Root == nil {
+		return pgio.AppendUint32(buf, 0), nil
+	}
+	if err := src.Root.validate(); err != nil {
+		return nil, err
+	}
+
+	sp := len(buf)
+	buf = pgio.AppendUint32(buf, 0)
+	buf, count := appendTsqueryNodeBinary(buf, src.Root)
+	pgio.SetInt32(buf[sp:], int32(count))
+
+	return buf, nil
+}
+
+func appendTsqueryNodeBinary(buf []byte, n *TsqueryNode) ([]byte, int) {
+	if n.Operator == TsqueryOperand {
+		buf = append(buf, tsqueryValueItem, byte(n.Weights))
+		if n.Prefix {
+			buf = append(buf, 1)
+		} else {
+			buf = append(buf, 0)
+		}
+		buf = append(buf, n.Lexeme...)
+		return append(buf, 0), 1
+	}
+
+	buf = append(buf, tsqueryOperatorItem, byte(n.Operator))
+	if n.Operator == TsqueryNot {
+		buf, count := appendTsqueryNodeBinary(buf, n.Left)
+		return buf, count + 1
+	}
+	if n.Operator == TsqueryPhrase {
+		buf = pgio.AppendUint16(buf, n.Distance)
+	}
+
+	buf, rightCount := appendTsqueryNodeBinary(buf, n.Right)
+	buf, leftCount := appendTsqueryNodeBinary(buf, n.Left)
+	return buf, rightCount + leftCount + 1
+}
+
+// Scan implements the database/sql Scanner interface.
+func (dst *Tsquery) Scan(src interface{}) error {
+	if src == nil {
+		*dst = Tsquery{Status: Null}
+		return nil
+	}
+
+	switch src := src.(type) {
+	case string:
+		return dst.DecodeText(nil, []byte(src))
+	case []byte:
+		srcCopy := make([]byte, len(src))
+		copy(srcCopy, src)
+		return dst.DecodeText(nil, srcCopy)
+	}
+
+	return errors.Errorf("cannot scan %T", src)
+}
+
+// Value implements the database/sql/driver Valuer interface.
+func (src Tsquery) Value() (driver.Value, error) {
+	return EncodeValueText(src)
+}
+
+// MarshalJSON encodes src as a JSON string of its text form, as the server's
+// to_json does.
+func (src Tsquery) MarshalJSON() ([]byte, error) {
+	switch src.Status {
+	case Present:
+		buf, err := src.EncodeText(nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(string(buf))
+	case Null:
+		return []byte("null"), nil
+	}
+
+	return nil, errBadStatus
+}
+
+func (dst *Tsquery) UnmarshalJSON(b []byte) error {
+	var s *string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		return err
+	}
+
+	if s == nil {
+		*dst = Tsquery{Status: Null}
+		return nil
+	}
+
+	return dst.DecodeText(nil, []byte(*s))
+}
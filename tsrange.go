@@ -0,0 +1,210 @@
+package tstype
+
+import (
+	"database/sql/driver"
+	"time"
+
+	"github.com/jackc/pgtype"
+
+	errors "golang.org/x/xerrors"
+)
+
+// Tsrange represents the PostgreSQL tsrange type. Lower and Upper are only used
+// when LowerType and UpperType are pgtype.Inclusive or pgtype.Exclusive. An
+// empty range has both bound types set to pgtype.Empty.
+type Tsrange struct {
+	Lower     Timestamp
+	Upper     Timestamp
+	LowerType pgtype.BoundType
+	UpperType pgtype.BoundType
+	Status    Status
+}
+
+func (dst *Tsrange) Set(src interface{}) error {
+	if src == nil {
+		*dst = Tsrange{Status: Null}
+		return nil
+	}
+
+	switch value := src.(type) {
+	case Tsrange:
+		*dst = value
+	case *Tsrange:
+		if value == nil {
+			*dst = Tsrange{Status: Null}
+		} else {
+			*dst = *value
+		}
+	case string:
+		return dst.DecodeText(nil, []byte(value))
+	case *string:
+		if value == nil {
+			*dst = Tsrange{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	default:
+		return errors.Errorf("cannot convert %v to Tsrange", src)
+	}
+
+	return nil
+}
+
+func (dst Tsrange) Get() interface{} {
+	switch dst.Status {
+	case Present:
+		return dst
+	case Null:
+		return nil
+	default:
+		return dst.Status
+	}
+}
+
+func (src *Tsrange) AssignTo(dst interface{}) error {
+	switch src.Status {
+	case Present:
+		switch v := dst.(type) {
+		case *Tsrange:
+			*v = *src
+			return nil
+		case *string:
+			buf, err := src.EncodeText(nil, nil)
+			if err != nil {
+				return err
+			}
+			*v = string(buf)
+			return nil
+		default:
+			if nextDst, retry := GetAssignToDstType(dst); retry {
+				return src.AssignTo(nextDst)
+			}
+			return errors.Errorf("unable to assign to %T", dst)
+		}
+	case Null:
+		return NullAssignTo(dst)
+	}
+
+	return errors.Errorf("cannot decode %#v into %T", src, dst)
+}
+
+func (dst *Tsrange) DecodeText(ci *pgtype.ConnInfo, src []byte) error {
+	r, err := decodeRangeText[Timestamp](ci, src)
+	if err != nil {
+		return err
+	}
+
+	*dst = Tsrange(r)
+	return nil
+}
+
+func (dst *Tsrange) DecodeBinary(ci *pgtype.ConnInfo, src []byte) error {
+	r, err := decodeRangeBinary[Timestamp](ci, src)
+	if err != nil {
+		return err
+	}
+
+	*dst = Tsrange(r)
+	return nil
+}
+
+func (src Tsrange) EncodeText(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	return encodeRangeText(ci, buf, rangeValue[Timestamp](src))
+}
+
+func (src Tsrange) EncodeBinary(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	return encodeRangeBinary(ci, buf, rangeValue[Timestamp](src))
+}
+
+// Scan implements the database/sql Scanner interface.
+func (dst *Tsrange) Scan(src interface{}) error {
+	if src == nil {
+		*dst = Tsrange{Status: Null}
+		return nil
+	}
+
+	switch src := src.(type) {
+	case string:
+		return dst.DecodeText(nil, []byte(src))
+	case []byte:
+		srcCopy := make([]byte, len(src))
+		copy(srcCopy, src)
+		return dst.DecodeText(nil, srcCopy)
+	}
+
+	return errors.Errorf("cannot scan %T", src)
+}
+
+// Value implements the database/sql/driver Valuer interface.
+func (src Tsrange) Value() (driver.Value, error) {
+	return EncodeValueText(src)
+}
+
+// MarshalJSON encodes src as {"lower": "2020-01-01T09:00:00", "upper":
+// "infinity", "bounds": "[)"}, with null for an unbounded side, or as {"empty":
+// true}.
+func (src Tsrange) MarshalJSON() ([]byte, error) {
+	return marshalRangeJSON(rangeValue[Timestamp](src))
+}
+
+func (dst *Tsrange) UnmarshalJSON(b []byte) error {
+	r, err := unmarshalRangeJSON[Timestamp](b)
+	if err != nil {
+		return err
+	}
+
+	*dst = Tsrange(r)
+	return nil
+}
+
+// Canonical returns src in the form the server stores it. A range with no
+// values becomes empty. It returns an error for a range the server would
+// reject, such as one with a lower bound greater than its upper bound.
+func (src Tsrange) Canonical() (Tsrange, error) {
+	r, err := tsRangeSubtype.makeRange(rangeValue[Timestamp](src), true)
+	if err != nil {
+		return Tsrange{}, err
+	}
+	return Tsrange(r), nil
+}
+
+// IsEmpty reports whether src contains no values.
+func (src Tsrange) IsEmpty() bool {
+	r := tsRangeSubtype.normalize(rangeValue[Timestamp](src))
+	return r.Status == Present && r.isEmpty()
+}
+
+// Contains reports whether v is in src, as the server's @> operator does.
+func (src Tsrange) Contains(v time.Time) bool {
+	return tsRangeSubtype.contains(rangeValue[Timestamp](src), Timestamp{Time: wallClockUTC(v), Status: Present})
+}
+
+// ContainsRange reports whether other is entirely within src, as the server's
+// @> operator does. Every range contains the empty range.
+func (src Tsrange) ContainsRange(other Tsrange) bool {
+	return tsRangeSubtype.containsRange(rangeValue[Timestamp](src), rangeValue[Timestamp](other))
+}
+
+// Overlaps reports whether src and other have values in common, as the server's
+// && operator does.
+func (src Tsrange) Overlaps(other Tsrange) bool {
+	return tsRangeSubtype.overlaps(rangeValue[Timestamp](src), rangeValue[Timestamp](other))
+}
+
+// Adjacent reports whether src and other do not overlap and no value lies
+// between them, as the server's -|- operator does.
+func (src Tsrange) Adjacent(other Tsrange) bool {
+	return tsRangeSubtype.adjacent(rangeValue[Timestamp](src), rangeValue[Timestamp](other))
+}
+
+// Intersect returns the values src and other have in common, as the server's *
+// operator does. The result is Null if either range is Null.
+func (src Tsrange) Intersect(other Tsrange) Tsrange {
+	return Tsrange(tsRangeSubtype.intersect(rangeValue[Timestamp](src), rangeValue[Timestamp](other)))
+}
+
+var tsRangeSubtype = rangeSubtype[Timestamp]{
+	cmp: func(a, b Timestamp) int {
+		return cmpInfinityTime(a.InfinityModifier, b.InfinityModifier, a.Time, b.Time)
+	},
+}
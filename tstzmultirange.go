@@ -0,0 +1,203 @@
+package tstype
+
+import (
+	"database/sql/driver"
+	"time"
+
+	"github.com/jackc/pgtype"
+
+	errors "golang.org/x/xerrors"
+)
+
+// Tstzmultirange represents the PostgreSQL tstzmultirange type, available since
+// PostgreSQL 14. Ranges are kept in the order they were set or decoded; use
+// Canonical to sort and merge them as the server does.
+type Tstzmultirange struct {
+	Ranges []Tstzrange
+	Status Status
+}
+
+func (dst *Tstzmultirange) Set(src interface{}) error {
+	if src == nil {
+		*dst = Tstzmultirange{Status: Null}
+		return nil
+	}
+
+	switch value := src.(type) {
+	case Tstzmultirange:
+		*dst = value
+	case *Tstzmultirange:
+		if value == nil {
+			*dst = Tstzmultirange{Status: Null}
+		} else {
+			*dst = *value
+		}
+	case []Tstzrange:
+		if value == nil {
+			*dst = Tstzmultirange{Status: Null}
+		} else {
+			*dst = Tstzmultirange{Ranges: value, Status: Present}
+		}
+	case string:
+		return dst.DecodeText(nil, []byte(value))
+	case *string:
+		if value == nil {
+			*dst = Tstzmultirange{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	default:
+		return errors.Errorf("cannot convert %v to Tstzmultirange", src)
+	}
+
+	return nil
+}
+
+func (dst Tstzmultirange) Get() interface{} {
+	switch dst.Status {
+	case Present:
+		return dst
+	case Null:
+		return nil
+	default:
+		return dst.Status
+	}
+}
+
+func (src *Tstzmultirange) AssignTo(dst interface{}) error {
+	switch src.Status {
+	case Present:
+		switch v := dst.(type) {
+		case *Tstzmultirange:
+			*v = *src
+			return nil
+		case *[]Tstzrange:
+			*v = make([]Tstzrange, len(src.Ranges))
+			copy(*v, src.Ranges)
+			return nil
+		case *string:
+			buf, err := src.EncodeText(nil, nil)
+			if err != nil {
+				return err
+			}
+			*v = string(buf)
+			return nil
+		default:
+			if nextDst, retry := GetAssignToDstType(dst); retry {
+				return src.AssignTo(nextDst)
+			}
+			return errors.Errorf("unable to assign to %T", dst)
+		}
+	case Null:
+		return NullAssignTo(dst)
+	}
+
+	return errors.Errorf("cannot decode %#v into %T", src, dst)
+}
+
+func (dst *Tstzmultirange) DecodeText(ci *pgtype.ConnInfo, src []byte) error {
+	m, err := decodeMultirangeText[Tstzrange, Timestamptz](ci, src)
+	if err != nil {
+		return err
+	}
+
+	*dst = Tstzmultirange(m)
+	return nil
+}
+
+func (dst *Tstzmultirange) DecodeBinary(ci *pgtype.ConnInfo, src []byte) error {
+	m, err := decodeMultirangeBinary[Tstzrange, Timestamptz](ci, src)
+	if err != nil {
+		return err
+	}
+
+	*dst = Tstzmultirange(m)
+	return nil
+}
+
+func (src Tstzmultirange) EncodeText(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	return encodeMultirangeText(ci, buf, multirangeValue[Tstzrange](src))
+}
+
+func (src Tstzmultirange) EncodeBinary(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	return encodeMultirangeBinary(ci, buf, multirangeValue[Tstzrange](src))
+}
+
+// Scan implements the database/sql Scanner interface.
+func (dst *Tstzmultirange) Scan(src interface{}) error {
+	if src == nil {
+		*dst = Tstzmultirange{Status: Null}
+		return nil
+	}
+
+	switch src := src.(type) {
+	case string:
+		return dst.DecodeText(nil, []byte(src))
+	case []byte:
+		srcCopy := make([]byte, len(src))
+		copy(srcCopy, src)
+		return dst.DecodeText(nil, srcCopy)
+	}
+
+	return errors.Errorf("cannot scan %T", src)
+}
+
+// Value implements the database/sql/driver Valuer interface.
+func (src Tstzmultirange) Value() (driver.Value, error) {
+	return EncodeValueText(src)
+}
+
+// MarshalJSON encodes src as a JSON array of its ranges, each in the form
+// written by Tstzrange.MarshalJSON.
+func (src Tstzmultirange) MarshalJSON() ([]byte, error) {
+	return marshalMultirangeJSON(multirangeValue[Tstzrange](src))
+}
+
+func (dst *Tstzmultirange) UnmarshalJSON(b []byte) error {
+	m, err := unmarshalMultirangeJSON[Tstzrange, Timestamptz](b)
+	if err != nil {
+		return err
+	}
+
+	*dst = Tstzmultirange(m)
+	return nil
+}
+
+// Canonical returns src in the form the server stores it. Each range is
+// canonicalized, empty ranges are dropped, and the rest are sorted with
+// overlapping and adjacent ranges merged. It returns an error for a range the
+// server would reject.
+func (src Tstzmultirange) Canonical() (Tstzmultirange, error) {
+	m, err := canonicalMultirange(tstzRangeSubtype, multirangeValue[Tstzrange](src), true)
+	if err != nil {
+		return Tstzmultirange{}, err
+	}
+	return Tstzmultirange(m), nil
+}
+
+// IsEmpty reports whether src contains no values.
+func (src Tstzmultirange) IsEmpty() bool {
+	if src.Status != Present {
+		return false
+	}
+	for _, r := range src.Ranges {
+		if !r.IsEmpty() {
+			return false
+		}
+	}
+	return true
+}
+
+// Contains reports whether v is in one of the ranges of src, as the server's @>
+// operator does.
+func (src Tstzmultirange) Contains(v time.Time) bool {
+	if src.Status != Present {
+		return false
+	}
+	for _, r := range src.Ranges {
+		if r.Contains(v) {
+			return true
+		}
+	}
+	return false
+}
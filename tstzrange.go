@@ -0,0 +1,210 @@
+package tstype
+
+import (
+	"database/sql/driver"
+	"time"
+
+	"github.com/jackc/pgtype"
+
+	errors "golang.org/x/xerrors"
+)
+
+// Tstzrange represents the PostgreSQL tstzrange type. Lower and Upper are only
+// used when LowerType and UpperType are pgtype.Inclusive or pgtype.Exclusive.
+// An empty range has both bound types set to pgtype.Empty.
+type Tstzrange struct {
+	Lower     Timestamptz
+	Upper     Timestamptz
+	LowerType pgtype.BoundType
+	UpperType pgtype.BoundType
+	Status    Status
+}
+
+func (dst *Tstzrange) Set(src interface{}) error {
+	if src == nil {
+		*dst = Tstzrange{Status: Null}
+		return nil
+	}
+
+	switch value := src.(type) {
+	case Tstzrange:
+		*dst = value
+	case *Tstzrange:
+		if value == nil {
+			*dst = Tstzrange{Status: Null}
+		} else {
+			*dst = *value
+		}
+	case string:
+		return dst.DecodeText(nil, []byte(value))
+	case *string:
+		if value == nil {
+			*dst = Tstzrange{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	default:
+		return errors.Errorf("cannot convert %v to Tstzrange", src)
+	}
+
+	return nil
+}
+
+func (dst Tstzrange) Get() interface{} {
+	switch dst.Status {
+	case Present:
+		return dst
+	case Null:
+		return nil
+	default:
+		return dst.Status
+	}
+}
+
+func (src *Tstzrange) AssignTo(dst interface{}) error {
+	switch src.Status {
+	case Present:
+		switch v := dst.(type) {
+		case *Tstzrange:
+			*v = *src
+			return nil
+		case *string:
+			buf, err := src.EncodeText(nil, nil)
+			if err != nil {
+				return err
+			}
+			*v = string(buf)
+			return nil
+		default:
+			if nextDst, retry := GetAssignToDstType(dst); retry {
+				return src.AssignTo(nextDst)
+			}
+			return errors.Errorf("unable to assign to %T", dst)
+		}
+	case Null:
+		return NullAssignTo(dst)
+	}
+
+	return errors.Errorf("cannot decode %#v into %T", src, dst)
+}
+
+func (dst *Tstzrange) DecodeText(ci *pgtype.ConnInfo, src []byte) error {
+	r, err := decodeRangeText[Timestamptz](ci, src)
+	if err != nil {
+		return err
+	}
+
+	*dst = Tstzrange(r)
+	return nil
+}
+
+func (dst *Tstzrange) DecodeBinary(ci *pgtype.ConnInfo, src []byte) error {
+	r, err := decodeRangeBinary[Timestamptz](ci, src)
+	if err != nil {
+		return err
+	}
+
+	*dst = Tstzrange(r)
+	return nil
+}
+
+func (src Tstzrange) EncodeText(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	return encodeRangeText(ci, buf, rangeValue[Timestamptz](src))
+}
+
+func (src Tstzrange) EncodeBinary(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	return encodeRangeBinary(ci, buf, rangeValue[Timestamptz](src))
+}
+
+// Scan implements the database/sql Scanner interface.
+func (dst *Tstzrange) Scan(src interface{}) error {
+	if src == nil {
+		*dst = Tstzrange{Status: Null}
+		return nil
+	}
+
+	switch src := src.(type) {
+	case string:
+		return dst.DecodeText(nil, []byte(src))
+	case []byte:
+		srcCopy := make([]byte, len(src))
+		copy(srcCopy, src)
+		return dst.DecodeText(nil, srcCopy)
+	}
+
+	return errors.Errorf("cannot scan %T", src)
+}
+
+// Value implements the database/sql/driver Valuer interface.
+func (src Tstzrange) Value() (driver.Value, error) {
+	return EncodeValueText(src)
+}
+
+// MarshalJSON encodes src as {"lower": "2020-01-01T09:00:00Z", "upper":
+// "infinity", "bounds": "[)"}, with null for an unbounded side, or as {"empty":
+// true}.
+func (src Tstzrange) MarshalJSON() ([]byte, error) {
+	return marshalRangeJSON(rangeValue[Timestamptz](src))
+}
+
+func (dst *Tstzrange) UnmarshalJSON(b []byte) error {
+	r, err := unmarshalRangeJSON[Timestamptz](b)
+	if err != nil {
+		return err
+	}
+
+	*dst = Tstzrange(r)
+	return nil
+}
+
+// Canonical returns src in the form the server stores it. A range with no
+// values becomes empty. It returns an error for a range the server would
+// reject, such as one with a lower bound greater than its upper bound.
+func (src Tstzrange) Canonical() (Tstzrange, error) {
+	r, err := tstzRangeSubtype.makeRange(rangeValue[Timestamptz](src), true)
+	if err != nil {
+		return Tstzrange{}, err
+	}
+	return Tstzrange(r), nil
+}
+
+// IsEmpty reports whether src contains no values.
+func (src Tstzrange) IsEmpty() bool {
+	r := tstzRangeSubtype.normalize(rangeValue[Timestamptz](src))
+	return r.Status == Present && r.isEmpty()
+}
+
+// Contains reports whether v is in src, as the server's @> operator does.
+func (src Tstzrange) Contains(v time.Time) bool {
+	return tstzRangeSubtype.contains(rangeValue[Timestamptz](src), Timestamptz{Time: v, Status: Present})
+}
+
+// ContainsRange reports whether other is entirely within src, as the server's
+// @> operator does. Every range contains the empty range.
+func (src Tstzrange) ContainsRange(other Tstzrange) bool {
+	return tstzRangeSubtype.containsRange(rangeValue[Timestamptz](src), rangeValue[Timestamptz](other))
+}
+
+// Overlaps reports whether src and other have values in common, as the server's
+// && operator does.
+func (src Tstzrange) Overlaps(other Tstzrange) bool {
+	return tstzRangeSubtype.overlaps(rangeValue[Timestamptz](src), rangeValue[Timestamptz](other))
+}
+
+// Adjacent reports whether src and other do not overlap and no value lies
+// between them, as the server's -|- operator does.
+func (src Tstzrange) Adjacent(other Tstzrange) bool {
+	return tstzRangeSubtype.adjacent(rangeValue[Timestamptz](src), rangeValue[Timestamptz](other))
+}
+
+// Intersect returns the values src and other have in common, as the server's *
+// operator does. The result is Null if either range is Null.
+func (src Tstzrange) Intersect(other Tstzrange) Tstzrange {
+	return Tstzrange(tstzRangeSubtype.intersect(rangeValue[Timestamptz](src), rangeValue[Timestamptz](other)))
+}
+
+var tstzRangeSubtype = rangeSubtype[Timestamptz]{
+	cmp: func(a, b Timestamptz) int {
+		return cmpInfinityTime(a.InfinityModifier, b.InfinityModifier, a.Time, b.Time)
+	},
+}
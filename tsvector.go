@@ -0,0 +1,572 @@
+package tstype
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgio"
+	"github.com/jackc/pgtype"
+
+	errors "golang.org/x/xerrors"
+)
+
+// TsWeight is the weight of a lexeme position in a tsvector. Its values match
+// the wire format, where D is 0 and A is 3.
+type TsWeight byte
+
+const (
+	TsWeightD TsWeight = iota
+	TsWeightC
+	TsWeightB
+	TsWeightA
+)
+
+func (w TsWeight) String() string {
+	return string("DCBA"[w&3])
+}
+
+func parseTsWeight(c byte) (TsWeight, bool) {
+	switch c {
+	case 'A', 'a':
+		return TsWeightA, true
+	case 'B', 'b':
+		return TsWeightB, true
+	case 'C', 'c':
+		return TsWeightC, true
+	case 'D', 'd':
+		return TsWeightD, true
+	}
+	return 0, false
+}
+
+// Limits of the server's tsvector representation.
+const (
+	maxTsvectorPosition  = 1<<14 - 1
+	maxTsvectorPositions = 256
+)
+
+// TsvectorPosition is a position of a lexeme in a document, from 1 to 16383.
+type TsvectorPosition struct {
+	Position uint16
+	Weight   TsWeight
+}
+
+// TsvectorLexeme is a lexeme of a tsvector and the positions it occurs at. A
+// lexeme without positions was stripped of them or given without any.
+type TsvectorLexeme struct {
+	Lexeme    string
+	Positions []TsvectorPosition
+}
+
+// Tsvector represents the PostgreSQL tsvector type. The decoders keep Lexemes
+// in the server's order: sorted, with duplicate lexemes merged and positions
+// sorted and unique.
+type Tsvector struct {
+	Lexemes []TsvectorLexeme
+	Status  Status
+}
+
+func (dst *Tsvector) Set(src interface{}) error {
+	if src == nil {
+		*dst = Tsvector{Status: Null}
+		return nil
+	}
+
+	switch value := src.(type) {
+	case Tsvector:
+		*dst = value
+	case *Tsvector:
+		if value == nil {
+			*dst = Tsvector{Status: Null}
+		} else {
+			*dst = *value
+		}
+	case []TsvectorLexeme:
+		if value == nil {
+			*dst = Tsvector{Status: Null}
+			return nil
+		}
+		lexemes, err := normalizeTsvectorLexemes(value)
+		if err != nil {
+			return err
+		}
+		*dst = Tsvector{Lexemes: lexemes, Status: Present}
+	case []string:
+		if value == nil {
+			*dst = Tsvector{Status: Null}
+			return nil
+		}
+		lexemes := make([]TsvectorLexeme, len(value))
+		for i := range value {
+			lexemes[i].Lexeme = value[i]
+		}
+		lexemes, err := normalizeTsvectorLexemes(lexemes)
+		if err != nil {
+			return err
+		}
+		*dst = Tsvector{Lexemes: lexemes, Status: Present}
+	case string:
+		return dst.DecodeText(nil, []byte(value))
+	case *string:
+		if value == nil {
+			*dst = Tsvector{Status: Null}
+		} else {
+			return dst.Set(*value)
+		}
+	default:
+		if originalSrc, ok := underlyingStringType(src); ok {
+			return dst.Set(originalSrc)
+		}
+		return errors.Errorf("cannot convert %v to Tsvector", value)
+	}
+
+	return nil
+}
+
+func (dst Tsvector) Get() interface{} {
+	switch dst.Status {
+	case Present:
+		return dst
+	case Null:
+		return nil
+	default:
+		return dst.Status
+	}
+}
+
+func (src *Tsvector) AssignTo(dst interface{}) error {
+	switch src.Status {
+	case Present:
+		switch v := dst.(type) {
+		case *Tsvector:
+			*v = *src
+			return nil
+		case *[]TsvectorLexeme:
+			*v = make([]TsvectorLexeme, len(src.Lexemes))
+			copy(*v, src.Lexemes)
+			return nil
+		case *[]string:
+			*v = src.Words()
+			return nil
+		case *string:
+			buf, err := src.EncodeText(nil, nil)
+			if err != nil {
+				return err
+			}
+			*v = string(buf)
+			return nil
+		default:
+			if nextDst, retry := GetAssignToDstType(dst); retry {
+				return src.AssignTo(nextDst)
+			}
+			return errors.Errorf("unable to assign to %T", dst)
+		}
+	case Null:
+		return NullAssignTo(dst)
+	}
+
+	return errors.Errorf("cannot decode %#v into %T", src, dst)
+}
+
+// Words returns the lexemes of src without their positions.
+func (src Tsvector) Words() []string {
+	words := make([]string, len(src.Lexemes))
+	for i := range src.Lexemes {
+		words[i] = src.Lexemes[i].Lexeme
+	}
+	return words
+}
+
+// DecodeText parses the tsvector text format, such as 'a':1A,3 'cat':2. As the
+// server does, a position above 16383 is lowered to 16383 and a position
+// without a weight has weight D.
+func (dst *Tsvector) DecodeText(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Tsvector{Status: Null}
+		return nil
+	}
+
+	s := string(src)
+	var lexemes []TsvectorLexeme
+	for rp := skipTsSpace(s, 0); rp < len(s); rp = skipTsSpace(s, rp) {
+		word, next, err := scanTsWord(s, rp, false)
+		if err != nil {
+			return errors.Errorf("syntax error in tsvector: %q: %w", s, err)
+		}
+		rp = next
+
+		lexeme := TsvectorLexeme{Lexeme: word}
+		if rp < len(s) && s[rp] == ':' {
+			for {
+				rp++
+				start := rp
+				for rp < len(s) && s[rp] >= '0' && s[rp] <= '9' {
+					rp++
+				}
+				if start == rp {
+					return errors.Errorf("syntax error in tsvector: %q", s)
+				}
+				n, err := strconv.ParseUint(s[start:rp], 10, 32)
+				if err != nil || n > maxTsvectorPosition {
+					n = maxTsvectorPosition
+				}
+				if n == 0 {
+					return errors.Errorf("wrong position info in tsvector: %q", s)
+				}
+
+				pos := TsvectorPosition{Position: uint16(n), Weight: TsWeightD}
+				if rp < len(s) {
+					if w, ok := parseTsWeight(s[rp]); ok {
+						pos.Weight = w
+						rp++
+					}
+				}
+				lexeme.Positions = append(lexeme.Positions, pos)
+
+				if rp >= len(s) || s[rp] != ',' {
+					break
+				}
+			}
+		}
+
+		if rp < len(s) && !isTsSpace(s[rp]) {
+			return errors.Errorf("syntax error in tsvector: %q", s)
+		}
+
+		lexemes = append(lexemes, lexeme)
+	}
+
+	lexemes, err := normalizeTsvectorLexemes(lexemes)
+	if err != nil {
+		return err
+	}
+
+	*dst = Tsvector{Lexemes: lexemes, Status: Present}
+	return nil
+}
+
+func (dst *Tsvector) DecodeBinary(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = Tsvector{Status: Null}
+		return nil
+	}
+
+	if len(src) < 4 {
+		return errors.Errorf("invalid length for tsvector: %v", len(src))
+	}
+	count := int(binary.BigEndian.Uint32(src))
+	rp := 4
+
+	if count > len(src[rp:])/3 {
+		return errors.Errorf("invalid tsvector lexeme count: %d", count)
+	}
+
+	lexemes := make([]TsvectorLexeme, count)
+	for i := range lexemes {
+		end := bytes.IndexByte(src[rp:], 0)
+		if end < 0 {
+			return errors.Errorf("unterminated tsvector lexeme %d", i)
+		}
+		lexemes[i].Lexeme = string(src[rp : rp+end])
+		rp += end + 1
+
+		if len(src[rp:]) < 2 {
+			return errors.Errorf("tsvector too short for lexeme %d", i)
+		}
+		npos := int(binary.BigEndian.Uint16(src[rp:]))
+		rp += 2
+
+		if len(src[rp:]) < npos*2 {
+			return errors.Errorf("tsvector too short for lexeme %d", i)
+		}
+		if npos > 0 {
+			lexemes[i].Positions = make([]TsvectorPosition, npos)
+		}
+		for j := 0; j < npos; j++ {
+			wep := binary.BigEndian.Uint16(src[rp:])
+			rp += 2
+			lexemes[i].Positions[j] = TsvectorPosition{Position: wep & maxTsvectorPosition, Weight: TsWeight(wep >> 14)}
+		}
+	}
+
+	if rp != len(src) {
+		return errors.Errorf("tsvector has %d trailing bytes", len(src)-rp)
+	}
+
+	lexemes, err := normalizeTsvectorLexemes(lexemes)
+	if err != nil {
+		return err
+	}
+
+	*dst = Tsvector{Lexemes: lexemes, Status: Present}
+	return nil
+}
+
+// normalizeTsvectorLexemes puts lexemes in the server's order. Lexemes are
+// sorted and duplicates merged, and positions are sorted with duplicates
+// merged into the highest weight and at most 256 kept, as the server's
+// tsvectorin does.
+func normalizeTsvectorLexemes(lexemes []TsvectorLexeme) ([]TsvectorLexeme, error) {
+	sorted := make([]TsvectorLexeme, len(lexemes))
+	copy(sorted, lexemes)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Lexeme < sorted[j].Lexeme
+	})
+
+	result := sorted[:0]
+	for _, l := range sorted {
+		if len(l.Lexeme) == 0 {
+			return nil, errors.New("tsvector lexeme cannot be empty")
+		}
+		if strings.IndexByte(l.Lexeme, 0) >= 0 {
+			return nil, errors.Errorf("tsvector lexeme %q cannot contain a NUL byte", l.Lexeme)
+		}
+		if n := len(result); n > 0 && result[n-1].Lexeme == l.Lexeme {
+			result[n-1].Positions = append(result[n-1].Positions, l.Positions...)
+			continue
+		}
+		l.Positions = append([]TsvectorPosition(nil), l.Positions...)
+		result = append(result, l)
+	}
+
+	for i := range result {
+		positions := result[i].Positions
+		for _, p := range positions {
+			if p.Position == 0 || p.Position > maxTsvectorPosition || p.Weight > TsWeightA {
+				return nil, errors.Errorf("wrong position info in tsvector lexeme %q", result[i].Lexeme)
+			}
+		}
+
+		sort.SliceStable(positions, func(i, j int) bool {
+			return positions[i].Position < positions[j].Position
+		})
+
+		unique := positions[:0]
+		for _, p := range positions {
+			if n := len(unique); n > 0 && unique[n-1].Position == p.Position {
+				if p.Weight > unique[n-1].Weight {
+					unique[n-1].Weight = p.Weight
+				}
+				continue
+			}
+			unique = append(unique, p)
+		}
+		if len(unique) > maxTsvectorPositions {
+			unique = unique[:maxTsvectorPositions]
+		}
+		if len(unique) == 0 {
+			unique = nil
+		}
+		result[i].Positions = unique
+	}
+
+	return result, nil
+}
+
+func (src Tsvector) EncodeText(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	if buf == nil {
+		buf = []byte{}
+	}
+	for i, l := range src.Lexemes {
+		if i > 0 {
+			buf = append(buf, ' ')
+		}
+		buf = appendTsLexemeText(buf, l.Lexeme)
+
+		for j, p := range l.Positions {
+			if j == 0 {
+				buf = append(buf, ':')
+			} else {
+				buf = append(buf, ',')
+			}
+			buf = strconv.AppendUint(buf, uint64(p.Position), 10)
+			if p.Weight != TsWeightD {
+				buf = append(buf, p.Weight.String()...)
+			}
+		}
+	}
+
+	return buf, nil
+}
+
+func (src Tsvector) EncodeBinary(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	lexemes, err := normalizeTsvectorLexemes(src.Lexemes)
+	if err != nil {
+		return nil, err
+	}
+
+	buf = pgio.AppendUint32(buf, uint32(len(lexemes)))
+	for _, l := range lexemes {
+		buf = append(buf, l.Lexeme...)
+		buf = append(buf, 0)
+		buf = pgio.AppendUint16(buf, uint16(len(l.Positions)))
+		for _, p := range l.Positions {
+			buf = pgio.AppendUint16(buf, uint16(p.Weight)<<14|p.Position)
+		}
+	}
+
+	return buf, nil
+}
+
+// Scan implements the database/sql Scanner interface.
+func (dst *Tsvector) Scan(src interface{}) error {
+	if src == nil {
+		*dst = Tsvector{Status: Null}
+		return nil
+	}
+
+	switch src := src.(type) {
+	case string:
+		return dst.DecodeText(nil, []byte(src))
+	case []byte:
+		srcCopy := make([]byte, len(src))
+		copy(srcCopy, src)
+		return dst.DecodeText(nil, srcCopy)
+	}
+
+	return errors.Errorf("cannot scan %T", src)
+}
+
+// Value implements the database/sql/driver Valuer interface.
+func (src Tsvector) Value() (driver.Value, error) {
+	return EncodeValueText(src)
+}
+
+// MarshalJSON encodes src as a JSON string of its text form, as the server's
+// to_json does.
+func (src Tsvector) MarshalJSON() ([]byte, error) {
+	switch src.Status {
+	case Present:
+		buf, err := src.EncodeText(nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(string(buf))
+	case Null:
+		return []byte("null"), nil
+	}
+
+	return nil, errBadStatus
+}
+
+func (dst *Tsvector) UnmarshalJSON(b []byte) error {
+	var s *string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		return err
+	}
+
+	if s == nil {
+		*dst = Tsvector{Status: Null}
+		return nil
+	}
+
+	return dst.DecodeText(nil, []byte(*s))
+}
+
+func isTsSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\v' || c == '\f'
+}
+
+func skipTsSpace(s string, rp int) int {
+	for rp < len(s) && isTsSpace(s[rp]) {
+		rp++
+	}
+	return rp
+}
+
+// isTsqueryOperator reports whether c ends an unquoted tsquery operand.
+func isTsqueryOperator(c byte) bool {
+	return strings.IndexByte("!&|()<", c) >= 0
+}
+
+// scanTsWord reads the lexeme starting at rp, as the server's
+// gettoken_tsvector does. A quoted lexeme is enclosed in single quotes, with
+// a quote written twice. An unquoted lexeme ends at whitespace or a colon, and
+// in a tsquery at an operator. A backslash escapes the next character in
+// either form. It returns the lexeme and the index following it.
+func scanTsWord(s string, rp int, tsquery bool) (string, int, error) {
+	var word []byte
+
+	if s[rp] == '\'' {
+		rp++
+		for {
+			if rp >= len(s) {
+				return "", 0, errors.New("unterminated quoted string")
+			}
+			switch c := s[rp]; {
+			case c == '\\':
+				rp++
+				if rp >= len(s) {
+					return "", 0, errors.New("there is no escaped character")
+				}
+				word = append(word, s[rp])
+			case c == '\'' && rp+1 < len(s) && s[rp+1] == '\'':
+				rp++
+				word = append(word, '\'')
+			case c == '\'':
+				return string(word), rp + 1, nil
+			default:
+				word = append(word, c)
+			}
+			rp++
+		}
+	}
+
+	for rp < len(s) {
+		c := s[rp]
+		if isTsSpace(c) || c == ':' || (tsquery && isTsqueryOperator(c)) {
+			break
+		}
+		if c == '\\' {
+			rp++
+			if rp >= len(s) {
+				return "", 0, errors.New("there is no escaped character")
+			}
+			c = s[rp]
+		}
+		word = append(word, c)
+		rp++
+	}
+
+	if len(word) == 0 {
+		return "", 0, errors.New("missing lexeme")
+	}
+
+	return string(word), rp, nil
+}
+
+// appendTsLexemeText quotes a lexeme as the server's tsvector and tsquery
+// output does: always in single quotes, with quotes and backslashes doubled.
+func appendTsLexemeText(buf []byte, lexeme string) []byte {
+	buf = append(buf, '\'')
+	for i := 0; i < len(lexeme); i++ {
+		c := lexeme[i]
+		if c == '\'' || c == '\\' {
+			buf = append(buf, c)
+		}
+		buf = append(buf, c)
+	}
+	return append(buf, '\'')
+}
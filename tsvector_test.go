@@ -0,0 +1,153 @@
+package tstype_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/tossp/tstype"
+)
+
+func TestTsvectorDecodeText(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected string
+	}{
+		{src: "", expected: ""},
+		{src: "a fat  cat sat on a mat", expected: "'a' 'cat' 'fat' 'mat' 'on' 'sat'"},
+		{src: "a:1 fat:2 cat:3 a:4", expected: "'a':1,4 'cat':3 'fat':2"},
+		{src: "'a':3,1B,3A ab:1c", expected: "'a':1B,3A 'ab':1C"},
+		{src: `'It''s' 'back\\slash' es\ caped`, expected: `'It''s' 'back\\slash' 'es caped'`},
+		{src: "big:99999", expected: "'big':16383"},
+	}
+
+	for i, tt := range tests {
+		var v tstype.Tsvector
+		if err := v.DecodeText(nil, []byte(tt.src)); err != nil {
+			t.Errorf("%d: %q: %v", i, tt.src, err)
+			continue
+		}
+		buf, err := v.EncodeText(nil, nil)
+		if err != nil {
+			t.Errorf("%d: %v", i, err)
+			continue
+		}
+		if buf == nil || string(buf) != tt.expected {
+			t.Errorf("%d: %q: expected %q, got %q", i, tt.src, tt.expected, buf)
+		}
+	}
+
+	for i, src := range []string{"'unterminated", "a:0", "a:", "a:1x", `trailing\`} {
+		var v tstype.Tsvector
+		if err := v.DecodeText(nil, []byte(src)); err == nil {
+			t.Errorf("%d: %q: expected error", i, src)
+		}
+	}
+}
+
+func TestTsvectorBinary(t *testing.T) {
+	var src tstype.Tsvector
+	if err := src.Set("'fat':2A,4 'cat':3"); err != nil {
+		t.Fatal(err)
+	}
+	if words := src.Words(); !reflect.DeepEqual(words, []string{"cat", "fat"}) {
+		t.Errorf("Words: got %v", words)
+	}
+
+	buf, err := src.EncodeBinary(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []byte{0, 0, 0, 2, 'c', 'a', 't', 0, 0, 1, 0, 3, 'f', 'a', 't', 0, 0, 2, 0xc0, 2, 0, 4}
+	if !reflect.DeepEqual(buf, expected) {
+		t.Errorf("EncodeBinary: expected %v, got %v", expected, buf)
+	}
+
+	var dst tstype.Tsvector
+	if err := dst.DecodeBinary(nil, buf); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dst, src) {
+		t.Errorf("DecodeBinary: expected %+v, got %+v", src, dst)
+	}
+
+	js, _ := json.Marshal(src)
+	if string(js) != `"'cat':3 'fat':2A,4"` {
+		t.Errorf("MarshalJSON: got %s", js)
+	}
+}
+
+func TestTsqueryText(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected string
+	}{
+		{src: "", expected: ""},
+		{src: "fat & rat", expected: "'fat' & 'rat'"},
+		{src: "fat & (rat | cat)", expected: "'fat' & ( 'rat' | 'cat' )"},
+		{src: "(fat & rat) | cat", expected: "'fat' & 'rat' | 'cat'"},
+		{src: "fat & rat & ! cat", expected: "'fat' & 'rat' & !'cat'"},
+		{src: "!(a & b)", expected: "!( 'a' & 'b' )"},
+		{src: "super:*AB & c:d", expected: "'super':*AB & 'c':D"},
+		{src: "a <-> b <2> c", expected: "'a' <-> 'b' <2> 'c'"},
+		{src: "a <-> (b <-> c)", expected: "'a' <-> ( 'b' <-> 'c' )"},
+		{src: "a <-> b & c", expected: "'a' <-> 'b' & 'c'"},
+		{src: `'it''s' | 'x y'`, expected: `'it''s' | 'x y'`},
+	}
+
+	for i, tt := range tests {
+		var q tstype.Tsquery
+		if err := q.DecodeText(nil, []byte(tt.src)); err != nil {
+			t.Errorf("%d: %q: %v", i, tt.src, err)
+			continue
+		}
+		buf, err := q.EncodeText(nil, nil)
+		if err != nil {
+			t.Errorf("%d: %v", i, err)
+			continue
+		}
+		if buf == nil || string(buf) != tt.expected {
+			t.Errorf("%d: %q: expected %q, got %q", i, tt.src, tt.expected, buf)
+		}
+
+		bin, err := q.EncodeBinary(nil, nil)
+		if err != nil {
+			t.Errorf("%d: %v", i, err)
+			continue
+		}
+		var dst tstype.Tsquery
+		if err := dst.DecodeBinary(nil, bin); err != nil {
+			t.Errorf("%d: %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(dst, q) {
+			t.Errorf("%d: binary: expected %+v, got %+v", i, q, dst)
+		}
+	}
+
+	for i, src := range []string{"a &", "& a", "(a | b", "a b", "a <x> b", "a <99999> b", "a)"} {
+		var q tstype.Tsquery
+		if err := q.DecodeText(nil, []byte(src)); err == nil {
+			t.Errorf("%d: %q: expected error", i, src)
+		}
+	}
+}
+
+func TestTsqueryBinaryLayout(t *testing.T) {
+	var q tstype.Tsquery
+	if err := q.Set("a & !b"); err != nil {
+		t.Fatal(err)
+	}
+	buf, err := q.EncodeBinary(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Operators come before their right, then their left operand.
+	expected := []byte{0, 0, 0, 4, 2, 2, 2, 1, 1, 0, 0, 'b', 0, 1, 0, 0, 'a', 0}
+	if !reflect.DeepEqual(buf, expected) {
+		t.Errorf("expected %v, got %v", expected, buf)
+	}
+	if lexemes := q.Root.Lexemes(); !reflect.DeepEqual(lexemes, []string{"a", "b"}) {
+		t.Errorf("Lexemes: got %v", lexemes)
+	}
+}
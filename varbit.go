@@ -0,0 +1,456 @@
+package tstype
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+
+	"github.com/jackc/pgio"
+	"github.com/jackc/pgtype"
+
+	errors "golang.org/x/xerrors"
+)
+
+// Varbit represents the PostgreSQL bit varying type. Bytes holds the bits
+// from the most significant bit of the first byte on; the unused bits of the
+// last byte are zero. BitLen is the number of bits.
+//
+// Typmod is the declared length of a bit varying(n) column. When it is
+// positive, Set, the decoders and the encoders reject values longer than
+// Typmod bits, and Set keeps it when replacing dst.
+type Varbit struct {
+	Bytes  []byte
+	BitLen int32
+	Typmod int32
+	Status Status
+}
+
+func bitBytesLen(bitLen int32) int {
+	return (int(bitLen) + 7) / 8
+}
+
+// checkBitTypmod checks bitLen against the declared length typmod of a
+// bit(n) column if fixed is set, or of a bit varying(n) column otherwise.
+func checkBitTypmod(bitLen, typmod int32, fixed bool) error {
+	switch {
+	case typmod <= 0:
+		return nil
+	case fixed && bitLen != typmod:
+		return errors.Errorf("bit string length %d does not match type bit(%d)", bitLen, typmod)
+	case !fixed && bitLen > typmod:
+		return errors.Errorf("bit string too long for type bit varying(%d)", typmod)
+	}
+	return nil
+}
+
+// validate checks that Bytes holds exactly BitLen bits and that BitLen
+// satisfies Typmod.
+func (src Varbit) validate(fixed bool) error {
+	if src.Status != Present {
+		return nil
+	}
+	if src.BitLen < 0 || len(src.Bytes) != bitBytesLen(src.BitLen) {
+		return errors.Errorf("bit string of %d bits cannot have %d bytes", src.BitLen, len(src.Bytes))
+	}
+	return checkBitTypmod(src.BitLen, src.Typmod, fixed)
+}
+
+// padBits zeroes the unused bits of the last byte of buf, which holds bitLen
+// bits.
+func padBits(buf []byte, bitLen int32) {
+	if rem := bitLen % 8; rem != 0 {
+		buf[len(buf)-1] &= 0xff << (8 - rem)
+	}
+}
+
+func (dst *Varbit) Set(src interface{}) error {
+	return dst.set(src, false)
+}
+
+func (dst *Varbit) set(src interface{}, fixed bool) error {
+	if src == nil {
+		*dst = Varbit{Typmod: dst.Typmod, Status: Null}
+		return nil
+	}
+
+	var v Varbit
+	switch value := src.(type) {
+	case Varbit:
+		v = Varbit{BitLen: value.BitLen, Typmod: dst.Typmod, Status: value.Status}
+		if value.Bytes != nil {
+			v.Bytes = make([]byte, len(value.Bytes))
+			copy(v.Bytes, value.Bytes)
+		}
+	case *Varbit:
+		if value == nil {
+			*dst = Varbit{Typmod: dst.Typmod, Status: Null}
+			return nil
+		}
+		return dst.set(*value, fixed)
+	case Bit:
+		return dst.set(Varbit(value), fixed)
+	case *Bit:
+		if value == nil {
+			*dst = Varbit{Typmod: dst.Typmod, Status: Null}
+			return nil
+		}
+		return dst.set(Varbit(*value), fixed)
+	case string:
+		return dst.decodeText([]byte(value), fixed)
+	case *string:
+		if value == nil {
+			*dst = Varbit{Typmod: dst.Typmod, Status: Null}
+			return nil
+		}
+		return dst.decodeText([]byte(*value), fixed)
+	case []bool:
+		if value == nil {
+			*dst = Varbit{Typmod: dst.Typmod, Status: Null}
+			return nil
+		}
+		if len(value) > math.MaxInt32 {
+			return errors.Errorf("bit string of %d bits is too long", len(value))
+		}
+		v = Varbit{Bytes: make([]byte, bitBytesLen(int32(len(value)))), BitLen: int32(len(value)), Typmod: dst.Typmod, Status: Present}
+		for i, b := range value {
+			if b {
+				v.Bytes[i/8] |= 0x80 >> (i % 8)
+			}
+		}
+	default:
+		if originalSrc, ok := underlyingStringType(src); ok {
+			return dst.set(originalSrc, fixed)
+		}
+		return errors.Errorf("cannot convert %v to %s", value, bitTypeName(fixed))
+	}
+
+	if err := v.validate(fixed); err != nil {
+		return err
+	}
+
+	*dst = v
+	return nil
+}
+
+func bitTypeName(fixed bool) string {
+	if fixed {
+		return "Bit"
+	}
+	return "Varbit"
+}
+
+func (dst Varbit) Get() interface{} {
+	switch dst.Status {
+	case Present:
+		return dst
+	case Null:
+		return nil
+	default:
+		return dst.Status
+	}
+}
+
+func (src *Varbit) AssignTo(dst interface{}) error {
+	switch src.Status {
+	case Present:
+		switch v := dst.(type) {
+		case *Varbit:
+			*v = *src
+			return nil
+		case *[]bool:
+			if err := src.validate(false); err != nil {
+				return err
+			}
+			*v = make([]bool, src.BitLen)
+			for i := range *v {
+				(*v)[i] = src.GetBit(i)
+			}
+			return nil
+		case *string:
+			buf, err := src.EncodeText(nil, nil)
+			if err != nil {
+				return err
+			}
+			*v = string(buf)
+			return nil
+		default:
+			if nextDst, retry := GetAssignToDstType(dst); retry {
+				return src.AssignTo(nextDst)
+			}
+			return errors.Errorf("unable to assign to %T", dst)
+		}
+	case Null:
+		return NullAssignTo(dst)
+	}
+
+	return errors.Errorf("cannot decode %#v into %T", src, dst)
+}
+
+// DecodeText accepts a string of 0s and 1s, optionally prefixed with B, and
+// hexadecimal digits prefixed with X, as the server does.
+func (dst *Varbit) DecodeText(ci *pgtype.ConnInfo, src []byte) error {
+	return dst.decodeText(src, false)
+}
+
+func (dst *Varbit) decodeText(src []byte, fixed bool) error {
+	if src == nil {
+		*dst = Varbit{Typmod: dst.Typmod, Status: Null}
+		return nil
+	}
+
+	var v Varbit
+	if len(src) > 0 && (src[0] == 'x' || src[0] == 'X') {
+		digits := src[1:]
+		v = Varbit{Bytes: make([]byte, (len(digits)+1)/2), BitLen: int32(len(digits) * 4)}
+		for i, c := range digits {
+			n, ok := hexDigitValue(c)
+			if !ok {
+				return errors.Errorf("%q is not a valid hexadecimal digit", c)
+			}
+			v.Bytes[i/2] |= n << (4 * (1 - i%2))
+		}
+	} else {
+		if len(src) > 0 && (src[0] == 'b' || src[0] == 'B') {
+			src = src[1:]
+		}
+		v = Varbit{Bytes: make([]byte, bitBytesLen(int32(len(src)))), BitLen: int32(len(src))}
+		for i, c := range src {
+			switch c {
+			case '0':
+			case '1':
+				v.Bytes[i/8] |= 0x80 >> (i % 8)
+			default:
+				return errors.Errorf("%q is not a valid binary digit", c)
+			}
+		}
+	}
+	v.Typmod = dst.Typmod
+	v.Status = Present
+
+	if err := v.validate(fixed); err != nil {
+		return err
+	}
+
+	*dst = v
+	return nil
+}
+
+func hexDigitValue(c byte) (byte, bool) {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0', true
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10, true
+	case 'A' <= c && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+// DecodeBinary decodes the bit length followed by the bytes holding the bits.
+func (dst *Varbit) DecodeBinary(ci *pgtype.ConnInfo, src []byte) error {
+	return dst.decodeBinary(src, false)
+}
+
+func (dst *Varbit) decodeBinary(src []byte, fixed bool) error {
+	if src == nil {
+		*dst = Varbit{Typmod: dst.Typmod, Status: Null}
+		return nil
+	}
+
+	if len(src) < 4 {
+		return errors.Errorf("invalid length for %s: %v", bitTypeName(fixed), len(src))
+	}
+	bitLen := int32(binary.BigEndian.Uint32(src))
+	if bitLen < 0 || len(src)-4 != bitBytesLen(bitLen) {
+		return errors.Errorf("invalid length in external bit string: %d", bitLen)
+	}
+
+	v := Varbit{Bytes: make([]byte, len(src)-4), BitLen: bitLen, Typmod: dst.Typmod, Status: Present}
+	copy(v.Bytes, src[4:])
+	padBits(v.Bytes, bitLen)
+
+	if err := v.validate(fixed); err != nil {
+		return err
+	}
+
+	*dst = v
+	return nil
+}
+
+func (src Varbit) EncodeText(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	return src.encodeText(buf, false)
+}
+
+func (src Varbit) encodeText(buf []byte, fixed bool) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	if err := src.validate(fixed); err != nil {
+		return nil, err
+	}
+
+	if buf == nil {
+		buf = []byte{}
+	}
+	return appendBitsText(buf, src.Bytes, src.BitLen), nil
+}
+
+func appendBitsText(buf []byte, bytes []byte, bitLen int32) []byte {
+	for i := 0; i < int(bitLen); i++ {
+		if bytes[i/8]&(0x80>>(i%8)) != 0 {
+			buf = append(buf, '1')
+		} else {
+			buf = append(buf, '0')
+		}
+	}
+	return buf
+}
+
+func (src Varbit) EncodeBinary(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	return src.encodeBinary(buf, false)
+}
+
+func (src Varbit) encodeBinary(buf []byte, fixed bool) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	if err := src.validate(fixed); err != nil {
+		return nil, err
+	}
+
+	buf = pgio.AppendInt32(buf, src.BitLen)
+	sp := len(buf)
+	buf = append(buf, src.Bytes...)
+	padBits(buf[sp:], src.BitLen)
+	return buf, nil
+}
+
+// Scan implements the database/sql Scanner interface.
+func (dst *Varbit) Scan(src interface{}) error {
+	if src == nil {
+		*dst = Varbit{Typmod: dst.Typmod, Status: Null}
+		return nil
+	}
+
+	switch src := src.(type) {
+	case string:
+		return dst.DecodeText(nil, []byte(src))
+	case []byte:
+		srcCopy := make([]byte, len(src))
+		copy(srcCopy, src)
+		return dst.DecodeText(nil, srcCopy)
+	}
+
+	return errors.Errorf("cannot scan %T", src)
+}
+
+// Value implements the database/sql/driver Valuer interface.
+func (src Varbit) Value() (driver.Value, error) {
+	return EncodeValueText(src)
+}
+
+// MarshalJSON encodes src as a JSON string of 0s and 1s.
+func (src Varbit) MarshalJSON() ([]byte, error) {
+	switch src.Status {
+	case Present:
+		buf, err := src.EncodeText(nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(string(buf))
+	case Null:
+		return []byte("null"), nil
+	}
+
+	return nil, errBadStatus
+}
+
+func (dst *Varbit) UnmarshalJSON(b []byte) error {
+	var s *string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		return err
+	}
+
+	return dst.Set(s)
+}
+
+// NewVarbit returns a Present Varbit of n zero bits.
+func NewVarbit(n int) Varbit {
+	return Varbit{Bytes: make([]byte, bitBytesLen(int32(n))), BitLen: int32(n), Status: Present}
+}
+
+// Len returns the number of bits in src.
+func (src Varbit) Len() int {
+	return int(src.BitLen)
+}
+
+// GetBit reports whether bit i of src is set. Bits are numbered from the
+// left, as the server's get_bit does. It panics if i is out of range. The name
+// avoids the Get method of the pgtype.Value interface.
+func (src Varbit) GetBit(i int) bool {
+	if i < 0 || i >= int(src.BitLen) {
+		panic(errors.Errorf("bit index %d out of valid range (0..%d)", i, src.BitLen-1))
+	}
+	return src.Bytes[i/8]&(0x80>>(i%8)) != 0
+}
+
+// SetBit sets bit i of dst to v in place. It panics if i is out of range.
+func (dst *Varbit) SetBit(i int, v bool) {
+	if i < 0 || i >= int(dst.BitLen) {
+		panic(errors.Errorf("bit index %d out of valid range (0..%d)", i, dst.BitLen-1))
+	}
+	if v {
+		dst.Bytes[i/8] |= 0x80 >> (i % 8)
+	} else {
+		dst.Bytes[i/8] &^= 0x80 >> (i % 8)
+	}
+}
+
+// And returns the bitwise AND of src and other, as the server's & operator
+// does. It returns an error if they have different lengths. The result is Null
+// if either is not Present.
+func (src Varbit) And(other Varbit) (Varbit, error) {
+	return src.combine(other, "AND", func(a, b byte) byte { return a & b })
+}
+
+// Or returns the bitwise OR of src and other, as the server's | operator does.
+func (src Varbit) Or(other Varbit) (Varbit, error) {
+	return src.combine(other, "OR", func(a, b byte) byte { return a | b })
+}
+
+// Xor returns the bitwise XOR of src and other, as the server's # operator
+// does.
+func (src Varbit) Xor(other Varbit) (Varbit, error) {
+	return src.combine(other, "XOR", func(a, b byte) byte { return a ^ b })
+}
+
+func (src Varbit) combine(other Varbit, name string, op func(a, b byte) byte) (Varbit, error) {
+	if src.Status != Present || other.Status != Present {
+		return Varbit{Typmod: src.Typmod, Status: Null}, nil
+	}
+	if src.BitLen != other.BitLen {
+		return Varbit{}, errors.Errorf("cannot %s bit strings of different sizes", name)
+	}
+	if len(src.Bytes) != len(other.Bytes) {
+		return Varbit{}, errors.Errorf("bit string of %d bits cannot have %d bytes", src.BitLen, len(other.Bytes))
+	}
+
+	result := Varbit{Bytes: make([]byte, len(src.Bytes)), BitLen: src.BitLen, Typmod: src.Typmod, Status: Present}
+	for i := range result.Bytes {
+		result.Bytes[i] = op(src.Bytes[i], other.Bytes[i])
+	}
+	return result, nil
+}
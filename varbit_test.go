@@ -0,0 +1,157 @@
+package tstype_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/tossp/tstype"
+)
+
+func TestVarbitCodecs(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected string
+		binary   []byte
+	}{
+		{"", "", []byte{0, 0, 0, 0}},
+		{"1010", "1010", []byte{0, 0, 0, 4, 0xa0}},
+		{"B101100001", "101100001", []byte{0, 0, 0, 9, 0xb0, 0x80}},
+		{"x1F", "00011111", []byte{0, 0, 0, 8, 0x1f}},
+	}
+
+	for i, tt := range tests {
+		var v tstype.Varbit
+		if err := v.DecodeText(nil, []byte(tt.src)); err != nil {
+			t.Errorf("%d: %q: %v", i, tt.src, err)
+			continue
+		}
+		buf, err := v.EncodeText(nil, nil)
+		if err != nil || buf == nil || string(buf) != tt.expected {
+			t.Errorf("%d: EncodeText: expected %q, got %q, %v", i, tt.expected, buf, err)
+		}
+		bin, err := v.EncodeBinary(nil, nil)
+		if err != nil || !reflect.DeepEqual(bin, tt.binary) {
+			t.Errorf("%d: EncodeBinary: expected %x, got %x, %v", i, tt.binary, bin, err)
+		}
+
+		var again tstype.Varbit
+		if err := again.DecodeBinary(nil, bin); err != nil || again.Len() != len(tt.expected) {
+			t.Errorf("%d: DecodeBinary: got %+v, %v", i, again, err)
+		}
+	}
+
+	var v tstype.Varbit
+	for i, s := range []string{"102", "x1G"} {
+		if err := v.DecodeText(nil, []byte(s)); err == nil {
+			t.Errorf("%d: %q: expected error", i, s)
+		}
+	}
+	if err := v.DecodeBinary(nil, []byte{0, 0, 0, 9, 0xff}); err == nil {
+		t.Error("DecodeBinary: expected error for missing byte")
+	}
+
+	// Pad bits are cleared on decode.
+	if err := v.DecodeBinary(nil, []byte{0, 0, 0, 3, 0xff}); err != nil || v.Bytes[0] != 0xe0 {
+		t.Errorf("DecodeBinary: got %+v, %v", v, err)
+	}
+
+	js, _ := json.Marshal(tstype.Varbit{Bytes: []byte{0xa0}, BitLen: 4, Status: tstype.Present})
+	if string(js) != `"1010"` {
+		t.Errorf("MarshalJSON: got %s", js)
+	}
+	if err := json.Unmarshal([]byte(`"011"`), &v); err != nil || v.Len() != 3 || v.GetBit(0) || !v.GetBit(2) {
+		t.Errorf("UnmarshalJSON: got %+v, %v", v, err)
+	}
+}
+
+func TestVarbitTypmod(t *testing.T) {
+	v := tstype.Varbit{Typmod: 4}
+	if err := v.Set("1010"); err != nil || v.Typmod != 4 {
+		t.Errorf("Set: got %+v, %v", v, err)
+	}
+	if err := v.Set("10101"); err == nil {
+		t.Error("Set: expected error for too long value")
+	}
+	if err := v.Set(nil); err != nil || v.Status != tstype.Null || v.Typmod != 4 {
+		t.Errorf("Set nil: got %+v, %v", v, err)
+	}
+
+	tooLong := tstype.Varbit{Bytes: []byte{0xff}, BitLen: 8, Typmod: 4, Status: tstype.Present}
+	if _, err := tooLong.EncodeBinary(nil, nil); err == nil {
+		t.Error("EncodeBinary: expected error for too long value")
+	}
+
+	b := tstype.Bit{Typmod: 4}
+	if err := b.Set("101"); err == nil {
+		t.Error("Bit.Set: expected error for short value")
+	}
+	if err := b.DecodeBinary(nil, []byte{0, 0, 0, 4, 0xa0}); err != nil {
+		t.Errorf("Bit.DecodeBinary: %v", err)
+	}
+	if err := b.Set([]bool{true, false, true, true, false}); err == nil {
+		t.Error("Bit.Set: expected error for long value")
+	}
+}
+
+func TestVarbitBitset(t *testing.T) {
+	flags := tstype.NewVarbit(10)
+	flags.SetBit(0, true)
+	flags.SetBit(9, true)
+	flags.SetBit(3, true)
+	flags.SetBit(3, false)
+	if s, _ := flags.EncodeText(nil, nil); string(s) != "1000000001" {
+		t.Errorf("SetBit: got %s", s)
+	}
+
+	var mask tstype.Varbit
+	if err := mask.Set("1100000000"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		op       func(tstype.Varbit) (tstype.Varbit, error)
+		expected string
+	}{
+		{flags.And, "1000000000"},
+		{flags.Or, "1100000001"},
+		{flags.Xor, "0100000001"},
+	}
+	for i, tt := range tests {
+		result, err := tt.op(mask)
+		if s, _ := result.EncodeText(nil, nil); err != nil || string(s) != tt.expected {
+			t.Errorf("%d: expected %s, got %s, %v", i, tt.expected, s, err)
+		}
+	}
+
+	if _, err := flags.And(tstype.NewVarbit(3)); err == nil {
+		t.Error("And: expected error for different sizes")
+	}
+
+	var bools []bool
+	if err := flags.AssignTo(&bools); err != nil || len(bools) != 10 || !bools[0] || bools[1] || !bools[9] {
+		t.Errorf("AssignTo: got %v, %v", bools, err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("GetBit: expected panic for index out of range")
+		}
+	}()
+	flags.GetBit(10)
+}
+
+func TestBitBitset(t *testing.T) {
+	b := tstype.NewBit(3)
+	b.SetBit(1, true)
+	other := tstype.NewBit(3)
+	other.SetBit(2, true)
+
+	result, err := b.Or(other)
+	if s, _ := result.EncodeText(nil, nil); err != nil || string(s) != "011" || result.Typmod != 3 {
+		t.Errorf("Or: got %s, %+v, %v", s, result, err)
+	}
+	if b.Len() != 3 || !b.GetBit(1) || b.GetBit(0) {
+		t.Errorf("GetBit: got %+v", b)
+	}
+}
@@ -0,0 +1,279 @@
+package tstype
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+
+	"github.com/jackc/pgtype"
+
+	errors "golang.org/x/xerrors"
+)
+
+// XML represents the PostgreSQL xml type. Like the server with its default
+// xmloption, Set and the encoders accept any well-formed content: a document,
+// or a fragment such as text and several sibling elements. Values decoded from
+// the server are not checked again.
+type XML struct {
+	Bytes  []byte
+	Status Status
+}
+
+// newXMLDecoder returns a strict decoder for b that reads any declared
+// encoding as is, since only the structure of b is checked.
+func newXMLDecoder(b []byte) *xml.Decoder {
+	d := xml.NewDecoder(bytes.NewReader(b))
+	d.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+	return d
+}
+
+// scanXML reads every token of b and reports whether b is a document: a
+// single element, with only comments, processing instructions, directives and
+// white space around it.
+func scanXML(b []byte) (isDocument bool, err error) {
+	d := newXMLDecoder(b)
+	depth, roots, topLevelText := 0, 0, false
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return roots == 1 && !topLevelText, nil
+		}
+		if err != nil {
+			return false, errors.Errorf("invalid XML content: %w", err)
+		}
+
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			if depth == 0 {
+				roots++
+			}
+			depth++
+		case xml.EndElement:
+			depth--
+		case xml.CharData:
+			if depth == 0 && len(bytes.TrimSpace(tok)) > 0 {
+				topLevelText = true
+			}
+		}
+	}
+}
+
+func (dst *XML) Set(src interface{}) error {
+	if src == nil {
+		*dst = XML{Status: Null}
+		return nil
+	}
+
+	var x XML
+	switch value := src.(type) {
+	case string:
+		x = XML{Bytes: []byte(value), Status: Present}
+	case *string:
+		if value == nil {
+			*dst = XML{Status: Null}
+			return nil
+		}
+		x = XML{Bytes: []byte(*value), Status: Present}
+	case []byte:
+		if value == nil {
+			*dst = XML{Status: Null}
+			return nil
+		}
+		x = XML{Bytes: value, Status: Present}
+	case XML:
+		x = value
+	case *XML:
+		if value == nil {
+			*dst = XML{Status: Null}
+			return nil
+		}
+		x = *value
+	default:
+		if originalSrc, ok := underlyingStringType(src); ok {
+			return dst.Set(originalSrc)
+		}
+		buf, err := xml.Marshal(value)
+		if err != nil {
+			return err
+		}
+		*dst = XML{Bytes: buf, Status: Present}
+		return nil
+	}
+
+	if x.Status == Present {
+		if _, err := scanXML(x.Bytes); err != nil {
+			return err
+		}
+	}
+
+	*dst = x
+	return nil
+}
+
+func (dst XML) Get() interface{} {
+	switch dst.Status {
+	case Present:
+		return string(dst.Bytes)
+	case Null:
+		return nil
+	default:
+		return dst.Status
+	}
+}
+
+// AssignTo assigns src to dst. Destinations other than strings and byte
+// slices are filled in by xml.Unmarshal.
+func (src *XML) AssignTo(dst interface{}) error {
+	switch v := dst.(type) {
+	case *string:
+		if src.Status == Present {
+			*v = string(src.Bytes)
+		} else {
+			return errors.Errorf("cannot assign non-present status to %T", dst)
+		}
+	case **string:
+		if src.Status == Present {
+			s := string(src.Bytes)
+			*v = &s
+		} else {
+			*v = nil
+		}
+	case *[]byte:
+		if src.Status != Present {
+			*v = nil
+		} else {
+			buf := make([]byte, len(src.Bytes))
+			copy(buf, src.Bytes)
+			*v = buf
+		}
+	default:
+		if src.Status != Present {
+			return NullAssignTo(dst)
+		}
+		return newXMLDecoder(src.Bytes).Decode(dst)
+	}
+
+	return nil
+}
+
+func (XML) PreferredResultFormat() int16 {
+	return pgtype.TextFormatCode
+}
+
+func (dst *XML) DecodeText(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		*dst = XML{Status: Null}
+		return nil
+	}
+
+	*dst = XML{Bytes: src, Status: Present}
+	return nil
+}
+
+// DecodeBinary decodes the binary format, which is the same as the text one.
+func (dst *XML) DecodeBinary(ci *pgtype.ConnInfo, src []byte) error {
+	return dst.DecodeText(ci, src)
+}
+
+func (XML) PreferredParamFormat() int16 {
+	return pgtype.TextFormatCode
+}
+
+func (src XML) EncodeText(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	switch src.Status {
+	case Null:
+		return nil, nil
+	case Present:
+	default:
+		return nil, errBadStatus
+	}
+
+	if _, err := scanXML(src.Bytes); err != nil {
+		return nil, err
+	}
+
+	if buf == nil {
+		buf = []byte{}
+	}
+	return append(buf, src.Bytes...), nil
+}
+
+func (src XML) EncodeBinary(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	return src.EncodeText(ci, buf)
+}
+
+// Scan implements the database/sql Scanner interface.
+func (dst *XML) Scan(src interface{}) error {
+	if src == nil {
+		*dst = XML{Status: Null}
+		return nil
+	}
+
+	switch src := src.(type) {
+	case string:
+		return dst.DecodeText(nil, []byte(src))
+	case []byte:
+		srcCopy := make([]byte, len(src))
+		copy(srcCopy, src)
+		return dst.DecodeText(nil, srcCopy)
+	}
+
+	return errors.Errorf("cannot scan %T", src)
+}
+
+// Value implements the database/sql/driver Valuer interface.
+func (src XML) Value() (driver.Value, error) {
+	return EncodeValueText(src)
+}
+
+// MarshalJSON encodes src as a JSON string of the XML text.
+func (src XML) MarshalJSON() ([]byte, error) {
+	switch src.Status {
+	case Present:
+		return json.Marshal(string(src.Bytes))
+	case Null:
+		return []byte("null"), nil
+	}
+
+	return nil, errBadStatus
+}
+
+func (dst *XML) UnmarshalJSON(b []byte) error {
+	var s *string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		return err
+	}
+
+	return dst.Set(s)
+}
+
+// IsDocument reports whether src is a well-formed document rather than just
+// content, as the server's IS DOCUMENT does.
+func (src XML) IsDocument() bool {
+	if src.Status != Present {
+		return false
+	}
+	isDocument, err := scanXML(src.Bytes)
+	return err == nil && isDocument
+}
+
+// ValidateDocument returns an error unless src is a well-formed document, for
+// values bound for a server with xmloption set to document.
+func (src XML) ValidateDocument() error {
+	if src.Status != Present {
+		return nil
+	}
+	isDocument, err := scanXML(src.Bytes)
+	if err != nil {
+		return err
+	}
+	if !isDocument {
+		return errors.New("invalid XML document: content is not a single root element")
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package tstype_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/tossp/tstype"
+)
+
+func TestXMLSet(t *testing.T) {
+	valid := []string{
+		`<invoice id="1"><total>10.00</total></invoice>`,
+		`<?xml version="1.0" encoding="ISO-8859-1"?><invoice/>`,
+		`<!-- note --><invoice/>`,
+		`abc<b>bold</b>def`,
+		`<a/><b/>`,
+		``,
+	}
+	for i, s := range valid {
+		var x tstype.XML
+		if err := x.Set(s); err != nil || x.Status != tstype.Present {
+			t.Errorf("%d: %q: got %+v, %v", i, s, x, err)
+		}
+	}
+
+	invalid := []string{
+		`<invoice>`,
+		`<a></b>`,
+		`<a>&nbsp;</a>`,
+		`<a x=1/>`,
+	}
+	for i, s := range invalid {
+		var x tstype.XML
+		if err := x.Set(s); err == nil {
+			t.Errorf("%d: %q: expected error", i, s)
+		}
+	}
+
+	var x tstype.XML
+	if err := x.Set((*string)(nil)); err != nil || x.Status != tstype.Null {
+		t.Errorf("Set nil: got %+v, %v", x, err)
+	}
+
+	type total struct {
+		Currency string `xml:"currency,attr"`
+		Amount   string `xml:",chardata"`
+	}
+	if err := x.Set(total{Currency: "EUR", Amount: "10.00"}); err != nil || string(x.Bytes) != `<total currency="EUR">10.00</total>` {
+		t.Errorf("Set struct: got %s, %v", x.Bytes, err)
+	}
+
+	bad := tstype.XML{Bytes: []byte("<a>"), Status: tstype.Present}
+	if _, err := bad.EncodeText(nil, nil); err == nil {
+		t.Error("EncodeText: expected error for malformed XML")
+	}
+}
+
+func TestXMLIsDocument(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected bool
+	}{
+		{`<?xml version="1.0"?>` + "\n<invoice><line/></invoice>\n", true},
+		{`<!-- a --><invoice/><?pi x?>`, true},
+		{`<a/><b/>`, false},
+		{`text<a/>`, false},
+		{`text`, false},
+		{``, false},
+	}
+
+	for i, tt := range tests {
+		x := tstype.XML{Bytes: []byte(tt.src), Status: tstype.Present}
+		if got := x.IsDocument(); got != tt.expected {
+			t.Errorf("%d: %q: expected %v", i, tt.src, tt.expected)
+		}
+		if err := x.ValidateDocument(); (err == nil) != tt.expected {
+			t.Errorf("%d: %q: ValidateDocument: %v", i, tt.src, err)
+		}
+	}
+}
+
+func TestXMLAssignTo(t *testing.T) {
+	type line struct {
+		SKU string `xml:"sku,attr"`
+	}
+	type invoice struct {
+		ID    string `xml:"id,attr"`
+		Lines []line `xml:"line"`
+	}
+
+	x := tstype.XML{Bytes: []byte(`<invoice id="7"><line sku="a"/><line sku="b"/></invoice>`), Status: tstype.Present}
+	var inv invoice
+	if err := x.AssignTo(&inv); err != nil || inv.ID != "7" || len(inv.Lines) != 2 || inv.Lines[1].SKU != "b" {
+		t.Errorf("AssignTo struct: got %+v, %v", inv, err)
+	}
+
+	var s string
+	if err := x.AssignTo(&s); err != nil || s != string(x.Bytes) {
+		t.Errorf("AssignTo string: got %q, %v", s, err)
+	}
+
+	var ps *string
+	null := tstype.XML{Status: tstype.Null}
+	if err := null.AssignTo(&ps); err != nil || ps != nil {
+		t.Errorf("AssignTo Null: got %v, %v", ps, err)
+	}
+
+	js, _ := json.Marshal(tstype.XML{Bytes: []byte(`<a b="c"/>`), Status: tstype.Present})
+	var back tstype.XML
+	if err := json.Unmarshal(js, &back); err != nil || string(back.Bytes) != `<a b="c"/>` {
+		t.Errorf("JSON round trip: got %s, %+v, %v", js, back, err)
+	}
+}